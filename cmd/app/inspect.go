@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dnsoftware/mpm-shares-processor/config"
+	clickhouse2 "github.com/dnsoftware/mpm-shares-processor/internal/infrastructure/clickhouse"
+)
+
+// runInspect вывод сохраненных данных: share <uuid> - шара из ClickHouse в формате JSON
+func runInspect(args []string) error {
+	action, args := splitAction(args)
+	if action != "share" {
+		return fmt.Errorf("unknown object %q (expected share)", action)
+	}
+
+	fs, flags := newFlagSet("inspect")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("share uuid is required")
+	}
+	shareUUID := fs.Arg(0)
+
+	cfg, err := loadConfig(flags, needsOnly(config.Needs{Clickhouse: true}))
+	if err != nil {
+		return err
+	}
+
+	conn, err := clickhouse2.NewClickhouseConnect(clickhouse2.Config{
+		Addr:             cfg.Clickhouse.Addr,
+		Database:         cfg.Clickhouse.Database,
+		Username:         cfg.Clickhouse.Username,
+		Password:         cfg.Clickhouse.Password,
+		MaxExecutionTime: 10,
+	})
+	if err != nil {
+		return fmt.Errorf("NewClickhouseConnect: %w", err)
+	}
+	defer conn.Close()
+
+	storage, err := clickhouse2.NewClickhouseShareStorage(clickhouse2.ShareStorageConfig{
+		Conn:     conn,
+		Database: cfg.Clickhouse.Database,
+	})
+	if err != nil {
+		return fmt.Errorf("NewShareStorage: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	share, err := storage.GetShareRow(ctx, shareUUID)
+	if err != nil {
+		return err
+	}
+	if share == nil {
+		return fmt.Errorf("share %s not found", shareUUID)
+	}
+	share.UUID = shareUUID
+
+	data, err := json.MarshalIndent(share, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+
+	return nil
+}
@@ -1,29 +1,47 @@
 package main
 
 import (
-	"context"
-	"log"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
 
 	"github.com/dnsoftware/mpmslib/pkg/configloader"
 
 	"github.com/dnsoftware/mpm-shares-processor/config"
+	"github.com/dnsoftware/mpm-shares-processor/internal/constants"
 	"github.com/dnsoftware/mpm-shares-processor/internal/infrastructure/loaders"
 	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
 	"github.com/dnsoftware/mpm-shares-processor/pkg/utils"
-
-	"github.com/dnsoftware/mpm-shares-processor/internal/app"
-	"github.com/dnsoftware/mpm-shares-processor/internal/constants"
 )
 
+// command подкоманда
+type command struct {
+	usage string // аргументы и описание для справки
+	run   func(args []string) error
+}
+
+var commands = map[string]command{
+	"serve":   {usage: "[флаги]  запуск сервиса (по умолчанию)", run: runServe},
+	"migrate": {usage: "up|down|status [-steps N] [-dry-run] [-allow-drop]  миграции ClickHouse", run: runMigrate},
+	"replay":  {usage: "-from-offset N|-from-time T [-to-offset N|-to-time T] [-partitions 0,1] [-reprocess]  повторная обработка диапазона Кафки", run: runReplay},
+	"offsets": {usage: "reset -offset N  сброс смещений группы потребителей (не ниже сохраненных в ClickHouse)", run: runOffsets},
+	"inspect": {usage: "share <uuid>  вывод шары из ClickHouse", run: runInspect},
+}
+
 func main() {
-	ctx := context.Background()
+	// без подкоманды (или сразу с флагами) - serve
+	name, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
 
-	basePath, err := utils.GetProjectRoot(constants.ProjectRootAnchorFile)
-	if err != nil {
-		log.Fatalf("GetProjectRoot failed: %s", err.Error())
+	cmd, ok := commands[name]
+	if !ok {
+		usage()
+		os.Exit(2)
 	}
-	configFile := basePath + "/config.yaml"
-	envFile := basePath + "/.env"
 
 	filePath, err := logger.GetLoggerMainLogPath()
 	if err != nil {
@@ -31,26 +49,92 @@ func main() {
 	}
 	logger.InitLogger(logger.LogLevelDebug, filePath)
 
-	startConf, err := configloader.LoadStartConfig(basePath + constants.StartConfigFilename)
-	if err != nil {
-		log.Fatalf("start config load error: %w", err)
+	if err := cmd.run(args); err != nil {
+		logger.Log().Error(name + " failed: " + err.Error())
+		fmt.Fprintf(os.Stderr, "%s: %s\n", name, err.Error())
+		os.Exit(1)
 	}
+}
 
-	err = loaders.LoadRemoteConfig(basePath, *startConf, logger.Log().Logger)
-	if err != nil {
-		logger.Log().Error("Remote config failed: " + err.Error())
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(os.Stderr, "Использование: %s <команда> [аргументы]\n", os.Args[0])
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", name, commands[name].usage)
+	}
+}
+
+// newFlagSet флаги подкоманды вместе с флагами конфига
+func newFlagSet(name string) (*flag.FlagSet, *config.Flags) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	return fs, config.RegisterFlags(fs)
+}
+
+// splitAction действие подкоманды (migrate up, offsets reset) и остальные аргументы
+func splitAction(args []string) (string, []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return "", args
 	}
+	return args[0], args[1:]
+}
 
-	cfg, err := config.New(configFile, envFile)
+// needsOnly системы служебной подкоманды, не зависящие от режима сервиса
+func needsOnly(needs config.Needs) func(mode string) config.Needs {
+	return func(string) config.Needs { return needs }
+}
+
+// loadConfig загрузка конфига: удаленный конфиг из etcd (кроме автономного режима), config.yaml, окружение, флаги
+// обязательны только параметры систем needs (config.ServeNeeds - сервис в режиме из конфига)
+func loadConfig(flags *config.Flags, needs func(mode string) config.Needs) (config.Config, error) {
+	basePath, err := utils.GetProjectRoot(constants.ProjectRootAnchorFile)
 	if err != nil {
-		log.Fatalf("Main config failed: %s", err.Error())
+		return config.Config{}, fmt.Errorf("GetProjectRoot: %w", err)
+	}
+	configFile := basePath + "/config.yaml"
+	envFile := basePath + "/.env"
+
+	// В автономном режиме конфиг только из config.yaml и окружения, startconf.yaml и etcd не нужны
+	standalone := config.IsStandalone(configFile, envFile)
+
+	var startConf *configloader.StartConfig
+	if !standalone {
+		startConf, err = configloader.LoadStartConfig(basePath + constants.StartConfigFilename)
+		if err != nil {
+			return config.Config{}, fmt.Errorf("start config load: %w", err)
+		}
+
+		err = loaders.LoadRemoteConfig(basePath, *startConf, logger.Log().Logger)
+		if err != nil {
+			logger.Log().Error("Remote config failed: " + err.Error())
+		}
 	}
 
-	cfg.App.AppID = startConf.AppID
-	cfg.EtcdConfig.Endpoints = startConf.Etcd.Endpoints
-	cfg.EtcdConfig.Username = startConf.Etcd.Auth.Username
-	cfg.EtcdConfig.Password = startConf.Etcd.Auth.Password
+	cfg, err := config.Load(configFile, envFile, flags, needs)
+	if err != nil {
+		return cfg, fmt.Errorf("main config: %w", err)
+	}
+	if cfg.App.LogLevel != "" {
+		if err := logger.SetLevel(cfg.App.LogLevel); err != nil {
+			return cfg, fmt.Errorf("bad log level: %w", err)
+		}
+	}
 
-	err = app.Run(ctx, cfg)
+	if standalone {
+		cfg.App.Standalone = true
+		if cfg.App.AppID == "" {
+			cfg.App.AppID = constants.StandaloneAppID
+		}
+	} else {
+		cfg.App.AppID = startConf.AppID
+		cfg.EtcdConfig.Endpoints = startConf.Etcd.Endpoints
+		cfg.EtcdConfig.Username = startConf.Etcd.Auth.Username
+		cfg.EtcdConfig.Password = startConf.Etcd.Auth.Password
+	}
 
+	return cfg, nil
 }
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dnsoftware/mpm-shares-processor/config"
+	"github.com/dnsoftware/mpm-shares-processor/internal/constants"
+	clickhouse2 "github.com/dnsoftware/mpm-shares-processor/internal/infrastructure/clickhouse"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/etcd"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/utils"
+)
+
+// runMigrate миграции ClickHouse: up - применить все, down - откатить -steps последних, status - состояние
+// up и down выполняются под блокировкой в etcd (кроме автономного режима), -dry-run только выводит SQL
+func runMigrate(args []string) error {
+	action, args := splitAction(args)
+	fs, flags := newFlagSet("migrate")
+	steps := fs.Int("steps", 1, "количество откатываемых миграций (для down)")
+	dryRun := fs.Bool("dry-run", false, "вывести SQL без выполнения (для up и down)")
+	allowDrop := fs.Bool("allow-drop", false, "разрешить откат, удаляющий данные (для down)")
+	lockTimeout := fs.Duration("lock-timeout", 5*time.Minute, "ожидание блокировки миграций")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if action != "up" && action != "down" && action != "status" {
+		return fmt.Errorf("unknown action %q (expected up, down or status)", action)
+	}
+
+	cfg, err := loadConfig(flags, needsOnly(config.Needs{Clickhouse: true}))
+	if err != nil {
+		return err
+	}
+	basePath, err := utils.GetProjectRoot(constants.ProjectRootAnchorFile)
+	if err != nil {
+		return fmt.Errorf("GetProjectRoot: %w", err)
+	}
+
+	// база не указывается: до первой миграции ее может не быть, имена в миграциях полные
+	conn, err := clickhouse2.NewClickhouseConnect(clickhouse2.Config{
+		Addr:             cfg.Clickhouse.Addr,
+		Username:         cfg.Clickhouse.Username,
+		Password:         cfg.Clickhouse.Password,
+		MaxExecutionTime: 600,
+	})
+	if err != nil {
+		return fmt.Errorf("NewClickhouseConnect: %w", err)
+	}
+	defer conn.Close()
+
+	migratorCfg := clickhouse2.MigratorConfig{
+		Conn:     conn,
+		Dir:      basePath + "/" + constants.MigrationDir,
+		Database: cfg.Clickhouse.Database,
+		Cluster:  cfg.Clickhouse.Cluster,
+	}
+	if !cfg.App.Standalone && !*dryRun && action != "status" {
+		locker, closeLocker, err := migrationLocker(cfg, basePath)
+		if err != nil {
+			return err
+		}
+		defer closeLocker()
+		migratorCfg.Locker = timeoutLocker{locker: locker, timeout: *lockTimeout}
+	}
+	migrator, err := clickhouse2.NewMigrator(migratorCfg)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	switch {
+	case action == "up" && *dryRun:
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		printMigrationSQL(pending, true)
+		return nil
+	case action == "down" && *dryRun:
+		rollback, err := migrator.Rollback(ctx, *steps, *allowDrop)
+		if err != nil {
+			return err
+		}
+		printMigrationSQL(rollback, false)
+		return nil
+	case action == "up":
+		applied, err := migrator.Up(ctx)
+		logMigrations("applied", applied)
+		if err != nil {
+			return err
+		}
+	case action == "down":
+		rolledBack, err := migrator.Down(ctx, *steps, *allowDrop)
+		logMigrations("rolled back", rolledBack)
+		if err != nil {
+			return err
+		}
+	}
+
+	status, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+	printMigrationStatus(status)
+
+	return nil
+}
+
+// migrationLocker блокировка миграций в etcd по базе ClickHouse: одновременно мигрирует одна реплика
+func migrationLocker(cfg config.Config, basePath string) (*etcd.Mutex, func(), error) {
+	client, err := etcd.NewEtcdClient(etcd.EtcdConfig{
+		Nodes:       strings.Split(cfg.EtcdConfig.Endpoints, ","),
+		Username:    cfg.EtcdConfig.Username,
+		Password:    cfg.EtcdConfig.Password,
+		CertCaPath:  basePath + constants.CaPath,
+		CertPath:    basePath + constants.PublicPath,
+		CertKeyPath: basePath + constants.PrivatePath,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("NewEtcdClient: %w", err)
+	}
+
+	key := constants.MigrationLockPath + "/" + cfg.Clickhouse.Database
+	return etcd.NewMutex(client, key, constants.MigrationLockTTL), func() { client.Close() }, nil
+}
+
+// timeoutLocker ограничение ожидания блокировки миграций, сами миграции выполняются без этого ограничения
+type timeoutLocker struct {
+	locker  clickhouse2.Locker
+	timeout time.Duration
+}
+
+func (l timeoutLocker) Lock(ctx context.Context) (func(), error) {
+	ctx, cancel := context.WithTimeout(ctx, l.timeout)
+	defer cancel()
+
+	return l.locker.Lock(ctx)
+}
+
+func logMigrations(action string, migrations []clickhouse2.Migration) {
+	for _, m := range migrations {
+		logger.Log().Info(fmt.Sprintf("Migration %06d_%s %s", m.Version, m.Name, action))
+	}
+}
+
+// printMigrationSQL вывод SQL миграций без выполнения
+func printMigrationSQL(migrations []clickhouse2.Migration, up bool) {
+	if len(migrations) == 0 {
+		fmt.Println("-- nothing to do")
+		return
+	}
+	for _, m := range migrations {
+		statements, direction := m.Up, "up"
+		if !up {
+			statements, direction = m.Down, "down"
+		}
+		fmt.Printf("-- %06d_%s.%s.sql (checksum %s)\n", m.Version, m.Name, direction, m.Checksum)
+		for _, stmt := range statements {
+			fmt.Printf("%s;\n\n", stmt)
+		}
+	}
+}
+
+func printMigrationStatus(status clickhouse2.MigrationStatus) {
+	fmt.Printf("version: %d\n", status.Version)
+	for _, m := range status.Migrations {
+		state := "pending"
+		if m.Applied {
+			state = "applied"
+		}
+		if m.Modified {
+			state = "applied, checksum mismatch"
+		}
+		fmt.Printf("  %06d %-40s %s\n", m.Version, m.Name, state)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testLocker блокировка: busy - занята другой репликой (ожидание до отмены ctx)
+type testLocker struct {
+	busy     bool
+	deadline bool // у ожидания блокировки был срок
+}
+
+func (l *testLocker) Lock(ctx context.Context) (func(), error) {
+	_, l.deadline = ctx.Deadline()
+	if l.busy {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return func() {}, nil
+}
+
+func TestTimeoutLocker(t *testing.T) {
+	locker := &testLocker{busy: true}
+	_, err := timeoutLocker{locker: locker, timeout: 10 * time.Millisecond}.Lock(context.Background())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// срок ограничивает только ожидание блокировки, контекст миграций его не получает
+	ctx := context.Background()
+	locker = &testLocker{}
+	unlock, err := timeoutLocker{locker: locker, timeout: time.Minute}.Lock(ctx)
+	require.NoError(t, err)
+	defer unlock()
+	require.True(t, locker.deadline)
+	require.NoError(t, ctx.Err())
+}
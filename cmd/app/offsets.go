@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/dnsoftware/mpm-shares-processor/config"
+	"github.com/dnsoftware/mpm-shares-processor/internal/constants"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/kafka_reader"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+)
+
+// runOffsets смещения группы потребителей топика шар: reset - установка смещения -offset во всех партициях
+// экземпляры сервиса группы должны быть остановлены, иначе они перезапишут смещения;
+// откат ниже последнего смещения, сохраненного вместе с шарами в ClickHouse, не действует:
+// при получении партиции сервис сдвигает смещение за сохраненное (для повторной обработки - replay -reprocess)
+func runOffsets(args []string) error {
+	action, args := splitAction(args)
+	if action != "reset" {
+		return fmt.Errorf("unknown action %q (expected reset)", action)
+	}
+
+	fs, flags := newFlagSet("offsets")
+	offset := fs.Int64("offset", -1, "новое смещение во всех партициях (0 - с начала топика)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *offset < 0 {
+		return fmt.Errorf("-offset is required")
+	}
+
+	cfg, err := loadConfig(flags, needsOnly(config.Needs{Kafka: true}))
+	if err != nil {
+		return err
+	}
+
+	reader, err := kafka_reader.NewKafkaReader(kafka_reader.Config{
+		Brokers:            cfg.KafkaShareReader.Brokers,
+		Group:              cfg.KafkaShareReader.Group,
+		Topic:              cfg.KafkaShareReader.Topic,
+		AutoCommitInterval: constants.KafkaSharesAutocommitInterval,
+	}, logger.Log())
+	if err != nil {
+		return fmt.Errorf("NewKafkaReader: %w", err)
+	}
+	defer reader.Close()
+
+	if err := reader.SetGroupOffset(*offset); err != nil {
+		return err
+	}
+	fmt.Printf("group %s, topic %s: offsets reset to %d\n", cfg.KafkaShareReader.Group, cfg.KafkaShareReader.Topic, *offset)
+	fmt.Println("note: partitions with shares stored in ClickHouse past this offset resume after the stored offset; use replay -reprocess to reprocess them")
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/IBM/sarama"
+
+	"github.com/dnsoftware/mpm-shares-processor/config"
+	"github.com/dnsoftware/mpm-shares-processor/internal/adapter/kafka_consumer/shares"
+	"github.com/dnsoftware/mpm-shares-processor/internal/app"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/kafka_reader"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+)
+
+// replayComponents компоненты, нужные для нормализации и сохранения шар
+var replayComponents = []string{app.ComponentDiscovery, app.ComponentGRPCClients, app.ComponentCaches, app.ComponentClickhouse, app.ComponentUsecases}
+
+// runReplay повторная обработка диапазона сообщений топика шар тем же конвейером, что и у сервиса
+// сообщения читаются без группы потребителей, смещения группы не меняются;
+// отклоненные шары только логируются (в dead-letter топик они попали при первой обработке);
+// сообщения до последнего смещения, сохраненного вместе с шарами в ClickHouse, пропускаются (повторно не вставляются);
+// с -reprocess пропуск отключается: шары, которых нет в ClickHouse, вставляются (уже сохраненные отсекаются по UUID при clickhouse.insert_deduplication)
+func runReplay(args []string) error {
+	fs, flags := newFlagSet("replay")
+	partitions := fs.String("partitions", "", "партиции через запятую (пусто - все)")
+	fromOffset := fs.String("from-offset", "", "первое смещение")
+	toOffset := fs.String("to-offset", "", "смещение после последнего (пусто - до конца партиции)")
+	fromTime := fs.String("from-time", "", "начало диапазона, RFC3339")
+	toTime := fs.String("to-time", "", "конец диапазона (не включается), RFC3339")
+	batchSize := fs.Int("batch", 0, "размер пакета (0 - read_batch_size из конфига)")
+	reprocess := fs.Bool("reprocess", false, "обрабатывать и сообщения до сохраненного смещения (без insert_deduplication шары задваиваются)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	r, err := parseReplayRange(*partitions, *fromOffset, *toOffset, *fromTime, *toTime)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(flags, needsOnly(config.Needs{Kafka: true, Clickhouse: true, Services: true}))
+	if err != nil {
+		return err
+	}
+	if *batchSize <= 0 {
+		*batchSize = cfg.KafkaShareReader.ReadBatchSize
+	}
+	cfg.Spill.Dir = "" // буфер на диске принадлежит работающему сервису
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	application, err := app.New(cfg, replayComponents...)
+	if err != nil {
+		return err
+	}
+	if err := application.Registry.Start(ctx); err != nil {
+		return err
+	}
+	defer application.Registry.Stop()
+
+	var offsetStore shares.OffsetStore = application.Deps.ShareStorage
+	if *reprocess {
+		offsetStore = nil // без хранилища смещений уже сохраненные сообщения не пропускаются
+	}
+	consumer, err := shares.NewShareConsumer(shares.Config{
+		NormalizeWorkers: cfg.KafkaShareReader.NormalizeWorkers,
+		RetryBackoffMin:  cfg.KafkaShareReader.RetryBackoffMin * time.Second,
+		RetryBackoffMax:  cfg.KafkaShareReader.RetryBackoffMax * time.Second,
+		UnknownRetries:   cfg.KafkaShareReader.UnknownRetries,
+	}, nil, application.Deps.ShareUseCase, nil, offsetStore)
+	if err != nil {
+		return fmt.Errorf("NewShareConsumer: %w", err)
+	}
+
+	var processed int
+	err = kafka_reader.ReadRange(ctx, cfg.KafkaShareReader.Brokers, cfg.KafkaShareReader.Topic, r, *batchSize, func(batch []*sarama.ConsumerMessage) error {
+		if err := consumer.ProcessMessages(ctx, batch); err != nil {
+			return err
+		}
+		processed += len(batch)
+		return nil
+	})
+	logger.Log().Info(fmt.Sprintf("Replay of topic %s: %d messages processed, %d already stored, %d rejected, %d duplicates",
+		cfg.KafkaShareReader.Topic, processed, consumer.SkippedCount(), consumer.DeadLetteredCount(), application.Deps.ShareUseCase.DuplicatesCount()))
+	fmt.Printf("processed: %d, already stored: %d, rejected: %d, duplicates: %d\n",
+		processed, consumer.SkippedCount(), consumer.DeadLetteredCount(), application.Deps.ShareUseCase.DuplicatesCount())
+
+	return err
+}
+
+// parseReplayRange диапазон повторной обработки из флагов
+// начало обязательно и задается либо смещением, либо временем; конец - аналогично или не задается
+func parseReplayRange(partitions, fromOffset, toOffset, fromTime, toTime string) (kafka_reader.Range, error) {
+	var r kafka_reader.Range
+	var err error
+
+	if partitions != "" {
+		for _, p := range strings.Split(partitions, ",") {
+			partition, err := strconv.ParseInt(strings.TrimSpace(p), 10, 32)
+			if err != nil || partition < 0 {
+				return r, fmt.Errorf("bad partition %q", p)
+			}
+			r.Partitions = append(r.Partitions, int32(partition))
+		}
+	}
+
+	if (fromOffset == "") == (fromTime == "") {
+		return r, fmt.Errorf("exactly one of -from-offset and -from-time is required")
+	}
+	if toOffset != "" && toTime != "" {
+		return r, fmt.Errorf("-to-offset and -to-time are mutually exclusive")
+	}
+
+	parseOffset := func(name, value string) (int64, error) {
+		offset, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || offset < 0 {
+			return 0, fmt.Errorf("bad %s %q", name, value)
+		}
+		return offset, nil
+	}
+	parseTime := func(name, value string) (time.Time, error) {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return t, fmt.Errorf("bad %s %q: %w", name, value, err)
+		}
+		return t, nil
+	}
+
+	if fromOffset != "" {
+		if r.FromOffset, err = parseOffset("-from-offset", fromOffset); err != nil {
+			return r, err
+		}
+	} else if r.FromTime, err = parseTime("-from-time", fromTime); err != nil {
+		return r, err
+	}
+
+	if toOffset != "" {
+		if r.ToOffset, err = parseOffset("-to-offset", toOffset); err != nil {
+			return r, err
+		}
+		if fromOffset != "" && r.ToOffset <= r.FromOffset {
+			return r, fmt.Errorf("-to-offset must be greater than -from-offset")
+		}
+	}
+	if toTime != "" {
+		if r.ToTime, err = parseTime("-to-time", toTime); err != nil {
+			return r, err
+		}
+		if !r.FromTime.IsZero() && !r.ToTime.After(r.FromTime) {
+			return r, fmt.Errorf("-to-time must be after -from-time")
+		}
+	}
+
+	return r, nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReplayRange(t *testing.T) {
+	r, err := parseReplayRange("0, 2", "100", "200", "", "")
+	require.NoError(t, err)
+	require.Equal(t, []int32{0, 2}, r.Partitions)
+	require.Equal(t, int64(100), r.FromOffset)
+	require.Equal(t, int64(200), r.ToOffset)
+
+	r, err = parseReplayRange("", "", "", "2025-01-10T12:00:00Z", "2025-01-10T13:00:00Z")
+	require.NoError(t, err)
+	require.Nil(t, r.Partitions)
+	require.Equal(t, time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC), r.FromTime.UTC())
+	require.Equal(t, time.Hour, r.ToTime.Sub(r.FromTime))
+
+	for name, args := range map[string][5]string{
+		"no start":          {"", "", "", "", ""},
+		"two starts":        {"", "1", "", "2025-01-10T12:00:00Z", ""},
+		"two ends":          {"", "1", "5", "", "2025-01-10T12:00:00Z"},
+		"bad partition":     {"a", "1", "", "", ""},
+		"negative offset":   {"", "-1", "", "", ""},
+		"end before start":  {"", "10", "10", "", ""},
+		"bad time":          {"", "", "", "2025-01-10", ""},
+		"time end <= start": {"", "", "", "2025-01-10T12:00:00Z", "2025-01-10T11:00:00Z"},
+	} {
+		_, err := parseReplayRange(args[0], args[1], args[2], args[3], args[4])
+		require.Error(t, err, name)
+	}
+}
+
+func TestSplitAction(t *testing.T) {
+	action, args := splitAction([]string{"down", "-steps", "2"})
+	require.Equal(t, "down", action)
+	require.Equal(t, []string{"-steps", "2"}, args)
+
+	action, args = splitAction([]string{"-offset", "0"})
+	require.Empty(t, action)
+	require.Equal(t, []string{"-offset", "0"}, args)
+}
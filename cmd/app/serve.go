@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+
+	"github.com/dnsoftware/mpm-shares-processor/config"
+	"github.com/dnsoftware/mpm-shares-processor/internal/app"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+)
+
+// runServe запуск сервиса до сигнала остановки (схема ClickHouse должна быть создана командой migrate up)
+func runServe(args []string) error {
+	fs, flags := newFlagSet("serve")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(flags, config.ServeNeeds)
+	if err != nil {
+		return err
+	}
+	logger.Log().Info("Effective config:\n" + cfg.Redacted())
+
+	return app.Run(context.Background(), cfg)
+}
@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"strings"
+	"strconv"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -14,11 +14,15 @@ import (
 )
 
 type App struct {
-	AppID                string
+	AppID                string            `envconfig:"APP_ID" required:"false"` // из startconf.yaml, в автономном режиме - из окружения
 	Name                 string            `yaml:"app_name" envconfig:"APP_NAME"    required:"false"`
 	Version              string            `yaml:"app_version" envconfig:"APP_VERSION" required:"false"`
-	Dependencies         []string          `yaml:"dependencies"` // Зависимости от других микросервисов (будет ожидать их запуска, отслеживание через Service Discovery)
+	Mode                 string            `yaml:"mode" envconfig:"APP_MODE" required:"false"`             // режим запуска: ingest (только Кафка), api (только REST/gRPC), all (по умолчанию)
+	Standalone           bool              `yaml:"standalone" envconfig:"APP_STANDALONE" required:"false"` // автономный режим без etcd: конфиг только из config.yaml/env, статические адреса gRPC
+	LogLevel             string            `yaml:"log_level" envconfig:"APP_LOG_LEVEL" required:"false"`   // уровень логирования: debug, info, warn, error (пусто - по умолчанию)
+	Dependencies         []string          `yaml:"dependencies"`                                           // Зависимости от других микросервисов (будет ожидать их запуска, отслеживание через Service Discovery)
 	ServiceDiscoveryList map[string]string // список текущих сервисов из Service Discovery
+	ShutdownTimeout      time.Duration     `yaml:"shutdown_timeout"` // время на каждый шаг остановки сервиса, в секундах (0 - 10 секунд)
 }
 
 type ApiBaseUrls struct {
@@ -34,6 +38,12 @@ type KafkaShareReaderConfig struct {
 	AutoCommitInterval int           `yaml:"auto_commit_interval" envconfig:"KAFKA_SHARE_AUTO_COMMIT_INTERVAL" required:"false"` // в секундах
 	ReadBatchSize      int           `yaml:"read_batch_size"`
 	ReadFlushInterval  time.Duration `yaml:"read_flush_interval"`
+	NormalizeWorkers   int           `yaml:"normalize_workers"` // размер пула параллельной нормализации шар пакета
+	DedupSize          int           `yaml:"dedup_size"`        // количество UUID последних шар для отсева дубликатов из Кафки и gRPC (0 - отключено)
+	RetryBackoffMin    time.Duration `yaml:"retry_backoff_min"` // начальная пауза между повторами сохранения пакета, в секундах
+	RetryBackoffMax    time.Duration `yaml:"retry_backoff_max"` // максимальная пауза между повторами сохранения пакета, в секундах
+	FlushTimeout       time.Duration `yaml:"flush_timeout"`     // время на сохранение незавершенных пакетов при ребалансировке и остановке, в секундах
+	UnknownRetries     int           `yaml:"unknown_retries"`   // попыток сохранения пакета при неклассифицированной ошибке, затем шары уходят в dead-letter
 }
 
 type KafkaMetricWriterConfig struct {
@@ -41,9 +51,19 @@ type KafkaMetricWriterConfig struct {
 	Topic   string   `yaml:"topic" envconfig:"KAFKA_METRIC_WRITER_TOPIC" required:"false"`
 }
 
+// KafkaDeadLetterWriterConfig топик для шар, которые не удалось декодировать или нормализовать
+// если Topic пустой - dead-letter топик не используется
+type KafkaDeadLetterWriterConfig struct {
+	Brokers []string `yaml:"brokers" envconfig:"KAFKA_DEAD_LETTER_WRITER_BROKERS" required:"false"`
+	Topic   string   `yaml:"topic" envconfig:"KAFKA_DEAD_LETTER_WRITER_TOPIC" required:"false"`
+}
+
 type GRPCConfig struct {
 	CoinTarget  string `yaml:"coin_target" envconfig:"GRPC_COIN_TARGET" required:"false"`   // ServiceDiscovery ID для адреса сервиса справочника монет
 	MinerTarget string `yaml:"miner_target" envconfig:"GRPC_MINER_TARGET" required:"false"` // ServiceDiscovery ID для адреса сервиса работы с майнерами/воркерами
+	CoinAddr    string `yaml:"coin_addr" envconfig:"GRPC_COIN_ADDR" required:"false"`       // хост:порт сервиса справочника монет в автономном режиме
+	MinerAddr   string `yaml:"miner_addr" envconfig:"GRPC_MINER_ADDR" required:"false"`     // хост:порт сервиса работы с майнерами/воркерами в автономном режиме
+	Insecure    bool   `yaml:"insecure" envconfig:"GRPC_INSECURE" required:"false"`         // gRPC без TLS (только в автономном режиме, для локальной разработки)
 	// Deprecated
 	SharesTarget string `yaml:"shares_target" envconfig:"GRPC_SHARES_TARGET" required:"false"` // хост:порт удаленного хранилища shares timeseries
 }
@@ -62,10 +82,39 @@ type OtelConfig struct {
 }
 
 type ClickhouseConfig struct {
-	Addr     []string `yaml:"addr" envconfig:"CLICKHOUSE_ADDR" required:"false"`         // хост:порт clickhouse
-	Database string   `yaml:"database" envconfig:"CLICKHOUSE_DATABASE" required:"false"` // название базы clickhouse
-	Username string   `yaml:"username" envconfig:"CLICKHOUSE_USERNAME" required:"false"` // имя пользователя базы clickhouse
-	Password string   `yaml:"password" envconfig:"CLICKHOUSE_PASSWORD" required:"false"` // пароль пользователя базы clickhouse
+	Addr                []string `yaml:"addr" envconfig:"CLICKHOUSE_ADDR" required:"false"`         // хост:порт clickhouse
+	Database            string   `yaml:"database" envconfig:"CLICKHOUSE_DATABASE" required:"false"` // название базы clickhouse
+	Username            string   `yaml:"username" envconfig:"CLICKHOUSE_USERNAME" required:"false"` // имя пользователя базы clickhouse
+	Password            string   `yaml:"password" envconfig:"CLICKHOUSE_PASSWORD" required:"false"` // пароль пользователя базы clickhouse
+	Cluster             string   `yaml:"cluster" envconfig:"CLICKHOUSE_CLUSTER" required:"false"`   // кластер для ON CLUSTER в миграциях
+	InsertDeduplication bool     `yaml:"insert_deduplication"`                                      // не вставлять шары с UUID, уже сохраненными в shares, и токен дедупликации для пакетных вставок
+}
+
+type AnaliticsConfig struct {
+	HashrateCurrentWindow  time.Duration      `yaml:"hashrate_current_window"`  // окно расчета текущего хешрейта, в секундах
+	HashrateAverageWindows []time.Duration    `yaml:"hashrate_average_windows"` // окна расчета среднего хешрейта, в секундах
+	CoinAlgorithms         map[string]string  `yaml:"coin_algorithms"`          // алгоритмы монет (символ монеты -> алгоритм), переопределяют справочник монет
+	AlgorithmMultipliers   map[string]float64 `yaml:"algorithm_multipliers"`    // кол-во хешей на шару единичной сложности по алгоритмам
+}
+
+// SpillConfig буфер пакетов шар на диске на время недоступности ClickHouse
+// если Dir пустой - буфер не используется
+type SpillConfig struct {
+	Dir           string        `yaml:"dir"`            // каталог сегментов журнала
+	MaxSizeMB     int64         `yaml:"max_size_mb"`    // максимальный размер журнала в мегабайтах (0 - без ограничения)
+	DrainInterval time.Duration `yaml:"drain_interval"` // интервал проверки доступности ClickHouse, в секундах
+}
+
+// SharesConfig проверка шар перед нормализацией
+type SharesConfig struct {
+	RewardMethods []string `yaml:"reward_methods"` // допустимые методы начисления вознаграждения (пусто - любой непустой)
+}
+
+// NonceReplayConfig отслеживание повторной отправки воркером шары с тем же nonce
+type NonceReplayConfig struct {
+	Enabled bool          `yaml:"enabled"`
+	Window  time.Duration `yaml:"window"` // сколько помнить nonce воркера, в секундах
+	Reject  bool          `yaml:"reject"` // отклонять повторные шары в dead-letter (false - сохранять с пометкой в nonce_replays)
 }
 
 type Etcd struct {
@@ -78,19 +127,62 @@ type Config struct {
 	App               App         `yaml:"application"`
 	ApiBaseUrls       ApiBaseUrls `yaml:"api_base_urls"`
 	EtcdConfig        Etcd
-	KafkaShareReader  KafkaShareReaderConfig  `yaml:"kafka_share_reader"`
-	KafkaMetricWriter KafkaMetricWriterConfig `yaml:"kafka_metric_writer"`
-	GRPC              GRPCConfig              `yaml:"grpc"`
-	Auth              AuthConfig              `yaml:"auth"`
-	Otel              OtelConfig              `yaml:"otel"`
-	Clickhouse        ClickhouseConfig        `yaml:"clickhouse"`
+	KafkaShareReader  KafkaShareReaderConfig      `yaml:"kafka_share_reader"`
+	KafkaMetricWriter KafkaMetricWriterConfig     `yaml:"kafka_metric_writer"`
+	KafkaDeadLetter   KafkaDeadLetterWriterConfig `yaml:"kafka_dead_letter_writer"`
+	GRPC              GRPCConfig                  `yaml:"grpc"`
+	Auth              AuthConfig                  `yaml:"auth"`
+	Otel              OtelConfig                  `yaml:"otel"`
+	Clickhouse        ClickhouseConfig            `yaml:"clickhouse"`
+	Analitics         AnaliticsConfig             `yaml:"analitics"`
+	Shares            SharesConfig                `yaml:"shares"`
+	NonceReplay       NonceReplayConfig           `yaml:"nonce_replay"`
+	Spill             SpillConfig                 `yaml:"spill"`
 }
 
-func New(filePath string, envFile string) (Config, error) {
-	var config Config
-	var err error
+// IsStandalone включен ли автономный режим (application.standalone в config.yaml или APP_STANDALONE в окружении/.env)
+// проверяется до загрузки удаленного конфига, который в автономном режиме не используется
+func IsStandalone(filePath string, envFile string) bool {
+	_ = godotenv.Load(envFile)
+	if value, ok := os.LookupEnv("APP_STANDALONE"); ok {
+		standalone, _ := strconv.ParseBool(value)
+		return standalone
+	}
 
-	config.App.ServiceDiscoveryList = make(map[string]string)
+	var config struct {
+		App struct {
+			Standalone bool `yaml:"standalone"`
+		} `yaml:"application"`
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return false
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return false
+	}
+
+	return config.App.Standalone
+}
+
+// New загрузка конфига с приоритетом (каждый следующий источник переопределяет предыдущий):
+// значения по умолчанию (Defaults) < config.yaml < .env и переменные окружения < флаги командной строки args
+// возвращается ошибка, если конфиг не прошел проверку Validate
+func New(filePath string, envFile string, args ...string) (Config, error) {
+	fs := flag.NewFlagSet("config", flag.ContinueOnError)
+	flags := RegisterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return Config{}, fmt.Errorf("flags: %w", err)
+	}
+
+	return Load(filePath, envFile, flags, ServeNeeds)
+}
+
+// Load загрузка конфига с флагами, уже разобранными вызывающим (флаги подкоманд регистрируются вместе с RegisterFlags)
+// приоритет источников тот же, что и у New; flags == nil - без флагов
+// needs - системы, параметры которых обязательны (по режиму из загруженного конфига), проверка ValidateFor
+func Load(filePath string, envFile string, flags *Flags, needs func(mode string) Needs) (Config, error) {
+	config := Defaults()
 
 	// 1. Читаем из config.yaml.
 	file, err := os.Open(filePath)
@@ -116,20 +208,13 @@ func New(filePath string, envFile string) (Config, error) {
 		return config, fmt.Errorf("envconfig.Process: %w", err)
 	}
 
-	// 3. Чтение параметров командной строки
-	// Регистрируем флаги
-	kafkaShareBrokers := flag.String("kafka_share_brokers", "", "Хост для подключения")
-	jwtSecret := flag.String("js", "", "JWT secret")
-
-	// Устанавливаем тестовые аргументы (TODO убрать)
-	flag.CommandLine.Parse([]string{"-kafka_share_brokers=localhost:9092", "-js=jwtsecret"})
-
-	flag.Parse()
+	// 3. Переопределяем параметрами командной строки
+	if flags != nil {
+		flags.Apply(&config)
+	}
 
-	// для каждого аргумента проверяем не пустой ли он, и если не пустой - переопределяем переменную конфига
-	if *kafkaShareBrokers != "" {
-		config.KafkaShareReader.Brokers = strings.Split(*kafkaShareBrokers, ",")
-		config.Auth.JWTSecret = *jwtSecret
+	if err := config.ValidateFor(needs(config.App.Mode)); err != nil {
+		return config, fmt.Errorf("invalid config: %w", err)
 	}
 
 	return config, nil
@@ -2,6 +2,7 @@ package config
 
 import (
 	"log"
+	"os"
 	"testing"
 
 	"github.com/dnsoftware/mpmslib/pkg/configloader"
@@ -39,3 +40,157 @@ func TestConfigNew(t *testing.T) {
 	assert.Equal(t, "127.0.0.1:6878", cfg.GRPC.SharesTarget)
 
 }
+
+func TestIsStandalone(t *testing.T) {
+	dir := t.TempDir()
+	configFile := dir + "/config.yaml"
+	envFile := dir + "/.env"
+
+	require.False(t, IsStandalone(configFile, envFile))
+
+	require.NoError(t, os.WriteFile(configFile, []byte("application:\n  standalone: true\n"), 0o644))
+	require.True(t, IsStandalone(configFile, envFile))
+
+	// переменная окружения важнее config.yaml
+	t.Setenv("APP_STANDALONE", "false")
+	require.False(t, IsStandalone(configFile, envFile))
+}
+
+func TestDiff(t *testing.T) {
+	prev, err := Parse([]byte(`
+application:
+  log_level: "info"
+kafka_share_reader:
+  brokers: ["127.0.0.1:9092"]
+  topic: "shares"
+  read_batch_size: 100
+clickhouse:
+  password: "old"
+`))
+	require.NoError(t, err)
+
+	next := prev
+	next.App.LogLevel = "debug"
+	next.KafkaShareReader.ReadBatchSize = 200
+	next.KafkaShareReader.Brokers = []string{"10.0.0.1:9092"}
+	next.Clickhouse.Password = "new"
+	next.App.AppID = "runtime"
+
+	reloadable, restart := Diff(prev, next)
+	require.Equal(t, []Change{
+		{Field: "application.log_level", Old: "info", New: "debug"},
+		{Field: "kafka_share_reader.read_batch_size", Old: "100", New: "200"},
+	}, reloadable)
+	require.Equal(t, []Change{
+		{Field: "kafka_share_reader.brokers", Old: "[127.0.0.1:9092]", New: "[10.0.0.1:9092]"},
+		{Field: "clickhouse.password", Old: "***", New: "***"},
+	}, restart)
+
+	// поверх prev переносятся только параметры, применяемые на ходу
+	merged := WithReloadable(prev, next)
+	require.Equal(t, "debug", merged.App.LogLevel)
+	require.Equal(t, 200, merged.KafkaShareReader.ReadBatchSize)
+	require.Equal(t, []string{"127.0.0.1:9092"}, merged.KafkaShareReader.Brokers)
+	require.Equal(t, "old", merged.Clickhouse.Password)
+	require.Empty(t, merged.App.AppID)
+}
+
+func TestNewPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	configFile := dir + "/config.yaml"
+	envFile := dir + "/.env"
+
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+kafka_share_reader:
+  topic: "yaml_topic"
+  group: "yaml_group"
+  brokers: ["yaml:9092"]
+clickhouse:
+  addr: ["localhost:9000"]
+auth:
+  jwt_secret: "yaml_secret"
+`), 0o644))
+	require.NoError(t, os.WriteFile(envFile, []byte("KAFKA_SHARE_READER_GROUP=env_group\nKAFKA_SHARE_READER_TOPIC=env_topic\n"), 0o644))
+	// godotenv не переопределяет заданные переменные окружения: снимаем их (t.Setenv восстановит после теста)
+	t.Setenv("KAFKA_SHARE_READER_GROUP", "")
+	t.Setenv("KAFKA_SHARE_READER_TOPIC", "")
+	os.Unsetenv("KAFKA_SHARE_READER_GROUP")
+	os.Unsetenv("KAFKA_SHARE_READER_TOPIC")
+
+	cfg, err := New(configFile, envFile, "-kafka_share_topic=flag_topic", "-js=flag_secret")
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"yaml:9092"}, cfg.KafkaShareReader.Brokers) // только yaml
+	require.Equal(t, "env_group", cfg.KafkaShareReader.Group)             // env поверх yaml
+	require.Equal(t, "flag_topic", cfg.KafkaShareReader.Topic)            // флаг поверх env
+	require.Equal(t, "flag_secret", cfg.Auth.JWTSecret)
+	require.Equal(t, 10000, cfg.KafkaShareReader.ReadBatchSize) // по умолчанию
+
+	_, err = New(configFile, envFile, "-unknown_flag=1")
+	require.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	// брокеры Кафки умолчаний не имеют
+	require.ErrorContains(t, Defaults().Validate(), "kafka_share_reader.brokers")
+
+	cfg := Defaults()
+	cfg.KafkaShareReader.Brokers = []string{"localhost:9092"}
+	cfg.Clickhouse.Addr = []string{"localhost:9000"}
+	cfg.Auth.JWTSecret = "secret"
+	require.NoError(t, cfg.Validate())
+
+	invalid := cfg
+	invalid.KafkaShareReader.ReadBatchSize = 0
+	invalid.KafkaShareReader.ReadFlushInterval = -1
+	invalid.Clickhouse.Addr = nil
+	invalid.Clickhouse.Cluster = ""
+	invalid.App.Mode = "batch"
+	err := invalid.Validate()
+	require.Error(t, err)
+	for _, field := range []string{"read_batch_size", "read_flush_interval", "clickhouse.addr", "clickhouse.cluster", "application.mode"} {
+		require.Contains(t, err.Error(), field)
+	}
+
+	// в режиме api Кафка не нужна
+	api := cfg
+	api.App.Mode = ModeAPI
+	api.KafkaShareReader.Brokers = nil
+	require.NoError(t, api.Validate())
+
+	// gRPC без TLS допустим только в автономном режиме
+	plain := cfg
+	plain.GRPC.Insecure = true
+	require.ErrorContains(t, plain.Validate(), "grpc.insecure")
+	plain.App.Standalone = true
+	plain.GRPC.CoinAddr = "127.0.0.1:7878"
+	plain.GRPC.MinerAddr = "127.0.0.1:7878"
+	require.NoError(t, plain.Validate())
+
+	// служебные подкоманды проверяют только используемые системы
+	offsets := Defaults()
+	offsets.KafkaShareReader.Brokers = []string{"localhost:9092"}
+	require.NoError(t, offsets.ValidateFor(Needs{Kafka: true}))
+	require.ErrorContains(t, offsets.ValidateFor(Needs{Kafka: true, Clickhouse: true}), "clickhouse.addr")
+	migrate := Defaults()
+	migrate.Clickhouse.Addr = []string{"localhost:9000"}
+	require.NoError(t, migrate.ValidateFor(Needs{Clickhouse: true}))
+	require.ErrorContains(t, migrate.ValidateFor(Needs{Clickhouse: true, Services: true}), "auth.jwt_secret")
+
+	// допустимые значения проверяются всегда
+	migrate.KafkaShareReader.ReadBatchSize = 0
+	require.ErrorContains(t, migrate.ValidateFor(Needs{Clickhouse: true}), "read_batch_size")
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := Defaults()
+	cfg.Auth.JWTSecret = "jwt-secret-value"
+	cfg.Clickhouse.Password = "ch-password-value"
+	cfg.EtcdConfig.Password = "etcd-password-value"
+
+	dump := cfg.Redacted()
+	require.NotContains(t, dump, "secret-value")
+	require.NotContains(t, dump, "password-value")
+	require.Contains(t, dump, "read_batch_size: 10000")
+	require.Equal(t, "jwt-secret-value", cfg.Auth.JWTSecret) // исходный конфиг не изменяется
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Redacted действующий конфиг в формате yaml для лога, пароли и секреты скрыты
+func (c Config) Redacted() string {
+	redactValue(reflect.ValueOf(&c).Elem())
+
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return "config marshal error: " + err.Error()
+	}
+
+	return string(data)
+}
+
+// redactValue замена непустых строковых полей с паролями и секретами (по имени поля) на ***
+func redactValue(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		switch field.Kind() {
+		case reflect.Struct:
+			redactValue(field)
+		case reflect.String:
+			name := strings.ToLower(v.Type().Field(i).Name)
+			if field.String() != "" && (strings.Contains(name, "password") || strings.Contains(name, "secret")) {
+				field.SetString("***")
+			}
+		}
+	}
+}
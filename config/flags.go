@@ -0,0 +1,60 @@
+package config
+
+import (
+	"flag"
+	"strings"
+)
+
+// Flags параметры командной строки, переопределяющие config.yaml и окружение
+// пустое значение - параметр не задан
+type Flags struct {
+	Mode           string
+	LogLevel       string
+	KafkaBrokers   string // через запятую
+	KafkaTopic     string
+	KafkaGroup     string
+	ClickhouseAddr string // через запятую
+	GRPCAddr       string
+	RestAddr       string
+	JWTSecret      string
+}
+
+// RegisterFlags регистрация флагов конфига в fs
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{}
+	fs.StringVar(&f.Mode, "mode", "", "режим запуска: all, ingest, api")
+	fs.StringVar(&f.LogLevel, "log_level", "", "уровень логирования: debug, info, warn, error")
+	fs.StringVar(&f.KafkaBrokers, "kafka_share_brokers", "", "брокеры Кафки для чтения шар (через запятую)")
+	fs.StringVar(&f.KafkaTopic, "kafka_share_topic", "", "топик шар")
+	fs.StringVar(&f.KafkaGroup, "kafka_share_group", "", "группа потребителей топика шар")
+	fs.StringVar(&f.ClickhouseAddr, "clickhouse_addr", "", "адреса ClickHouse (через запятую)")
+	fs.StringVar(&f.GRPCAddr, "grpc", "", "host:port gRPC API")
+	fs.StringVar(&f.RestAddr, "rest", "", "host:port REST API")
+	fs.StringVar(&f.JWTSecret, "jwt_secret", "", "JWT секрет")
+	fs.StringVar(&f.JWTSecret, "js", "", "JWT секрет (то же, что -jwt_secret)")
+
+	return f
+}
+
+// Apply переопределение параметров конфига заданными флагами
+func (f *Flags) Apply(config *Config) {
+	set := func(dst *string, value string) {
+		if value != "" {
+			*dst = value
+		}
+	}
+	set(&config.App.Mode, f.Mode)
+	set(&config.App.LogLevel, f.LogLevel)
+	set(&config.KafkaShareReader.Topic, f.KafkaTopic)
+	set(&config.KafkaShareReader.Group, f.KafkaGroup)
+	set(&config.ApiBaseUrls.Grps, f.GRPCAddr)
+	set(&config.ApiBaseUrls.Rest, f.RestAddr)
+	set(&config.Auth.JWTSecret, f.JWTSecret)
+
+	if f.KafkaBrokers != "" {
+		config.KafkaShareReader.Brokers = strings.Split(f.KafkaBrokers, ",")
+	}
+	if f.ClickhouseAddr != "" {
+		config.Clickhouse.Addr = strings.Split(f.ClickhouseAddr, ",")
+	}
+}
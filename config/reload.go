@@ -0,0 +1,117 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/kelseyhightower/envconfig"
+	"gopkg.in/yaml.v2"
+)
+
+// ReloadableFields параметры (пути в yaml), которые применяются без перезапуска сервиса
+var ReloadableFields = map[string]bool{
+	"application.log_level":                  true,
+	"kafka_share_reader.read_batch_size":     true,
+	"kafka_share_reader.read_flush_interval": true,
+	"analitics.hashrate_current_window":      true,
+	"analitics.hashrate_average_windows":     true,
+}
+
+// Change изменение параметра конфига
+type Change struct {
+	Field string // путь в yaml
+	Old   string
+	New   string
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Field, c.Old, c.New)
+}
+
+// Parse разбор текста config.yaml поверх значений по умолчанию с переопределением из переменных окружения
+// (без флагов командной строки и проверки Validate)
+func Parse(data []byte) (Config, error) {
+	config := Defaults()
+
+	if err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&config); err != nil {
+		return config, fmt.Errorf("yaml decode: %w", err)
+	}
+	if err := envconfig.Process("", &config); err != nil {
+		return config, fmt.Errorf("envconfig.Process: %w", err)
+	}
+
+	return config, nil
+}
+
+// Diff изменения параметров конфига с yaml тегами
+// reloadable - параметры из ReloadableFields, restart - параметры, требующие перезапуска
+func Diff(prev Config, next Config) (reloadable []Change, restart []Change) {
+	diffValues("", reflect.ValueOf(prev), reflect.ValueOf(next), func(c Change) {
+		if ReloadableFields[c.Field] {
+			reloadable = append(reloadable, c)
+		} else {
+			restart = append(restart, c)
+		}
+	})
+
+	return reloadable, restart
+}
+
+func diffValues(path string, prev reflect.Value, next reflect.Value, add func(c Change)) {
+	if prev.Kind() != reflect.Struct {
+		if !reflect.DeepEqual(prev.Interface(), next.Interface()) {
+			add(Change{Field: path, Old: redact(path, prev.Interface()), New: redact(path, next.Interface())})
+		}
+		return
+	}
+
+	for i := 0; i < prev.NumField(); i++ {
+		name, _, _ := strings.Cut(prev.Type().Field(i).Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue // параметры времени выполнения (AppID, etcd, список сервисов) не сравниваются
+		}
+		if path != "" {
+			name = path + "." + name
+		}
+		diffValues(name, prev.Field(i), next.Field(i), add)
+	}
+}
+
+// WithReloadable копия base со значениями параметров ReloadableFields из next
+// (новый конфиг из etcd проверяется поверх запущенного, с его флагами и переменными окружения)
+func WithReloadable(base Config, next Config) Config {
+	result := base
+	setReloadable("", reflect.ValueOf(&result).Elem(), reflect.ValueOf(next))
+
+	return result
+}
+
+func setReloadable(path string, dst reflect.Value, src reflect.Value) {
+	if dst.Kind() != reflect.Struct {
+		if ReloadableFields[path] {
+			dst.Set(src)
+		}
+		return
+	}
+
+	for i := 0; i < dst.NumField(); i++ {
+		name, _, _ := strings.Cut(dst.Type().Field(i).Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		if path != "" {
+			name = path + "." + name
+		}
+		setReloadable(name, dst.Field(i), src.Field(i))
+	}
+}
+
+// redact значение параметра для лога, пароли и секреты скрываются
+func redact(path string, value any) string {
+	if strings.Contains(path, "password") || strings.Contains(path, "secret") {
+		return "***"
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/constants"
+)
+
+// Режимы запуска (App.Mode)
+const (
+	ModeAll    = "all"    // все компоненты
+	ModeIngest = "ingest" // только чтение шар из Кафки, без API
+	ModeAPI    = "api"    // только REST и gRPC API, без чтения Кафки
+)
+
+// LogLevels допустимые уровни логирования (App.LogLevel)
+var LogLevels = []string{"debug", "info", "warn", "error"}
+
+// Defaults значения по умолчанию, поверх которых читаются config.yaml, окружение и флаги
+// брокеры Кафки, адреса ClickHouse и JWT секрет умолчаний не имеют и должны быть заданы явно
+func Defaults() Config {
+	return Config{
+		App: App{
+			Name:                 "Shares processor",
+			Mode:                 ModeAll,
+			LogLevel:             "info",
+			ShutdownTimeout:      10,
+			ServiceDiscoveryList: make(map[string]string),
+		},
+		KafkaShareReader: KafkaShareReaderConfig{
+			Group:              constants.KafkaSharesGroup,
+			Topic:              constants.KafkaSharesTopic,
+			AutoCommitEnable:   true,
+			AutoCommitInterval: constants.KafkaSharesAutocommitInterval,
+			ReadBatchSize:      10000,
+			ReadFlushInterval:  1,
+			NormalizeWorkers:   8,
+			RetryBackoffMin:    1,
+			RetryBackoffMax:    60,
+			FlushTimeout:       10,
+			UnknownRetries:     5,
+		},
+		GRPC: GRPCConfig{
+			CoinTarget:  "miners_processor:grpc",
+			MinerTarget: "miners_processor:grpc",
+		},
+		Auth: AuthConfig{
+			JWTServiceName: "normalizer",
+		},
+		Otel: OtelConfig{
+			Endpoint:           "localhost:4317",
+			BatchTimeout:       1,
+			MaxExportBatchSize: 100,
+			MaxQueueSize:       500,
+		},
+		Clickhouse: ClickhouseConfig{
+			Database: "mpmhouse",
+			Cluster:  "clickhouse_cluster",
+		},
+		Analitics: AnaliticsConfig{
+			HashrateCurrentWindow:  600,
+			HashrateAverageWindows: []time.Duration{3600, 86400},
+		},
+		Spill: SpillConfig{
+			DrainInterval: 5,
+		},
+		NonceReplay: NonceReplayConfig{
+			Window: 600,
+		},
+	}
+}
+
+// Needs внешние системы, которые использует подкоманда: их обязательные параметры проверяются в ValidateFor
+type Needs struct {
+	Kafka      bool // топик шар: брокеры, топик, группа
+	Clickhouse bool // адреса, база, кластер
+	Services   bool // gRPC сервисы монет и майнеров и JWT секрет (нормализация шар, gRPC и REST API)
+}
+
+// ServeNeeds системы, нужные сервису в режиме mode (в режиме api Кафка не читается)
+func ServeNeeds(mode string) Needs {
+	return Needs{Kafka: mode != ModeAPI, Clickhouse: true, Services: true}
+}
+
+// Validate проверка конфига для запуска сервиса в его режиме (ServeNeeds), возвращаются все найденные ошибки
+func (c Config) Validate() error {
+	return c.ValidateFor(ServeNeeds(c.App.Mode))
+}
+
+// ValidateFor проверка допустимых значений и обязательных параметров систем needs, возвращаются все найденные ошибки
+// служебные подкоманды проверяют только то, что используют (offsets - Кафку, migrate и inspect - ClickHouse)
+func (c Config) ValidateFor(needs Needs) error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.App.Mode == ModeAll || c.App.Mode == ModeIngest || c.App.Mode == ModeAPI,
+		"application.mode: %q is not one of %s, %s, %s", c.App.Mode, ModeAll, ModeIngest, ModeAPI)
+	check(c.App.LogLevel == "" || contains(LogLevels, c.App.LogLevel), "application.log_level: %q is not one of %v", c.App.LogLevel, LogLevels)
+	check(c.App.ShutdownTimeout >= 0, "application.shutdown_timeout: must not be negative")
+
+	reader := c.KafkaShareReader
+	if needs.Kafka {
+		check(len(reader.Brokers) > 0, "kafka_share_reader.brokers: required")
+		check(reader.Topic != "", "kafka_share_reader.topic: required")
+		check(reader.Group != "", "kafka_share_reader.group: required")
+	}
+	check(reader.ReadBatchSize > 0, "kafka_share_reader.read_batch_size: must be > 0")
+	check(reader.ReadFlushInterval > 0, "kafka_share_reader.read_flush_interval: must be > 0")
+	check(reader.NormalizeWorkers >= 0, "kafka_share_reader.normalize_workers: must not be negative")
+	check(reader.DedupSize >= 0, "kafka_share_reader.dedup_size: must not be negative")
+	check(reader.RetryBackoffMin >= 0 && reader.RetryBackoffMax >= 0, "kafka_share_reader.retry_backoff_min/max: must not be negative")
+	check(reader.RetryBackoffMax == 0 || reader.RetryBackoffMin <= reader.RetryBackoffMax, "kafka_share_reader.retry_backoff_min: must not exceed retry_backoff_max")
+	check(reader.FlushTimeout >= 0, "kafka_share_reader.flush_timeout: must not be negative")
+	check(reader.UnknownRetries >= 0, "kafka_share_reader.unknown_retries: must not be negative")
+
+	check(c.KafkaDeadLetter.Topic == "" || len(c.KafkaDeadLetter.Brokers) > 0, "kafka_dead_letter_writer.brokers: required when topic is set")
+
+	if needs.Services {
+		check(c.GRPC.CoinTarget != "" && c.GRPC.MinerTarget != "", "grpc.coin_target, grpc.miner_target: required")
+		if c.App.Standalone {
+			check(c.GRPC.CoinAddr != "" && c.GRPC.MinerAddr != "", "grpc.coin_addr, grpc.miner_addr: required in standalone mode")
+		}
+		check(c.Auth.JWTSecret != "", "auth.jwt_secret: required")
+	}
+	check(!c.GRPC.Insecure || c.App.Standalone, "grpc.insecure: allowed only in standalone mode")
+
+	if needs.Clickhouse {
+		check(len(c.Clickhouse.Addr) > 0 && c.Clickhouse.Addr[0] != "", "clickhouse.addr: required")
+		check(c.Clickhouse.Database != "", "clickhouse.database: required")
+		check(c.Clickhouse.Cluster != "", "clickhouse.cluster: required")
+	}
+
+	check(c.Analitics.HashrateCurrentWindow >= 0, "analitics.hashrate_current_window: must not be negative")
+	for _, w := range c.Analitics.HashrateAverageWindows {
+		check(w > 0, "analitics.hashrate_average_windows: %d must be > 0", w)
+	}
+
+	if c.Spill.Dir != "" {
+		check(c.Spill.MaxSizeMB >= 0, "spill.max_size_mb: must not be negative")
+		check(c.Spill.DrainInterval > 0, "spill.drain_interval: must be > 0")
+	}
+	if c.NonceReplay.Enabled {
+		check(c.NonceReplay.Window > 0, "nonce_replay.window: must be > 0")
+	}
+
+	return errors.Join(errs...)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
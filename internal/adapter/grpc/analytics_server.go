@@ -0,0 +1,175 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/adapter/grpc/proto"
+	"github.com/dnsoftware/mpm-shares-processor/internal/usecase/analitics"
+)
+
+// GRPCAnalyticsServer gRPC API аналитики (хешрейты, история хешрейта, суммы сложностей раундов)
+type GRPCAnalyticsServer struct {
+	proto.UnimplementedAnalyticsServiceServer
+	analitics *analitics.AnaliticsUsecase
+}
+
+func NewAnalyticsServer(analitics *analitics.AnaliticsUsecase) (*GRPCAnalyticsServer, error) {
+	s := &GRPCAnalyticsServer{
+		analitics: analitics,
+	}
+
+	return s, nil
+}
+
+func (s *GRPCAnalyticsServer) GetCoinHashrate(ctx context.Context, req *proto.GetCoinHashrateRequest) (*proto.HashrateResponse, error) {
+	if req.Coin == "" {
+		return nil, status.Error(codes.InvalidArgument, "coin is empty")
+	}
+
+	hr, err := s.analitics.CoinHashrate(ctx, strings.ToUpper(req.Coin))
+	if err != nil {
+		return nil, statusError("GetCoinHashrate", err)
+	}
+
+	return hashrateToProto(hr), nil
+}
+
+func (s *GRPCAnalyticsServer) GetWalletHashrate(ctx context.Context, req *proto.GetWalletHashrateRequest) (*proto.HashrateResponse, error) {
+	hr, err := s.analitics.MinerHashrate(ctx, req.WalletId)
+	if err != nil {
+		return nil, statusError("GetWalletHashrate", err)
+	}
+
+	return hashrateToProto(hr), nil
+}
+
+func (s *GRPCAnalyticsServer) GetWorkerHashrate(ctx context.Context, req *proto.GetWorkerHashrateRequest) (*proto.HashrateResponse, error) {
+	hr, err := s.analitics.WorkerHashrate(ctx, req.WorkerId)
+	if err != nil {
+		return nil, statusError("GetWorkerHashrate", err)
+	}
+
+	return hashrateToProto(hr), nil
+}
+
+func (s *GRPCAnalyticsServer) GetCoinHashrateHistory(ctx context.Context, req *proto.GetCoinHashrateHistoryRequest) (*proto.HashrateHistoryResponse, error) {
+	if req.Coin == "" {
+		return nil, status.Error(codes.InvalidArgument, "coin is empty")
+	}
+
+	h, err := s.analitics.CoinHashrateHistory(ctx, strings.ToUpper(req.Coin), historyQueryFromProto(req.Params))
+	if err != nil {
+		return nil, statusError("GetCoinHashrateHistory", err)
+	}
+
+	return historyToProto(h), nil
+}
+
+func (s *GRPCAnalyticsServer) GetWalletHashrateHistory(ctx context.Context, req *proto.GetWalletHashrateHistoryRequest) (*proto.HashrateHistoryResponse, error) {
+	h, err := s.analitics.MinerHashrateHistory(ctx, req.WalletId, historyQueryFromProto(req.Params))
+	if err != nil {
+		return nil, statusError("GetWalletHashrateHistory", err)
+	}
+
+	return historyToProto(h), nil
+}
+
+func (s *GRPCAnalyticsServer) GetWorkerHashrateHistory(ctx context.Context, req *proto.GetWorkerHashrateHistoryRequest) (*proto.HashrateHistoryResponse, error) {
+	h, err := s.analitics.WorkerHashrateHistory(ctx, req.WorkerId, historyQueryFromProto(req.Params))
+	if err != nil {
+		return nil, statusError("GetWorkerHashrateHistory", err)
+	}
+
+	return historyToProto(h), nil
+}
+
+func (s *GRPCAnalyticsServer) GetRoundDifficultySum(ctx context.Context, req *proto.GetRoundDifficultySumRequest) (*proto.GetRoundDifficultySumResponse, error) {
+	if req.Coin == "" || req.RewardMethod == "" {
+		return nil, status.Error(codes.InvalidArgument, "coin and reward_method are required")
+	}
+
+	sum, cnt, err := s.analitics.RoundDifficultySum(ctx, strings.ToUpper(req.Coin), time.UnixMilli(req.DateStart), time.UnixMilli(req.DateEnd), req.RewardMethod)
+	if err != nil {
+		return nil, statusError("GetRoundDifficultySum", err)
+	}
+
+	return &proto.GetRoundDifficultySumResponse{
+		DifficultySum: sum,
+		SharesCount:   cnt,
+	}, nil
+}
+
+// statusError ошибка клиенту с деталями о методе, код по классу ошибки (statusCode)
+func statusError(method string, err error) error {
+	st := status.New(statusCode(err), err.Error())
+	detail := &proto.MPError{
+		Method:      method,
+		Description: err.Error(),
+	}
+	if stDetails, e := st.WithDetails(detail); e == nil {
+		st = stDetails
+	}
+
+	return st.Err()
+}
+
+func hashrateToProto(hr analitics.Hashrate) *proto.HashrateResponse {
+	resp := &proto.HashrateResponse{
+		Current: &proto.HashrateWindow{
+			Window:   int64(hr.Current.Window.Seconds()),
+			Hashrate: hr.Current.Hashrate,
+		},
+		Averages: make([]*proto.HashrateWindow, 0, len(hr.Averages)),
+	}
+	for _, avg := range hr.Averages {
+		resp.Averages = append(resp.Averages, &proto.HashrateWindow{
+			Window:   int64(avg.Window.Seconds()),
+			Hashrate: avg.Hashrate,
+		})
+	}
+
+	return resp
+}
+
+func historyQueryFromProto(p *proto.HistoryParams) analitics.HistoryQuery {
+	var q analitics.HistoryQuery
+	if p == nil {
+		return q
+	}
+
+	if p.From > 0 {
+		q.From = time.Unix(p.From, 0)
+	}
+	if p.To > 0 {
+		q.To = time.Unix(p.To, 0)
+	}
+	q.Interval = time.Duration(p.Interval) * time.Second
+	q.RewardMethod = p.RewardMethod
+
+	return q
+}
+
+func historyToProto(h analitics.HashrateHistory) *proto.HashrateHistoryResponse {
+	resp := &proto.HashrateHistoryResponse{
+		Params: &proto.HistoryParams{
+			From:         h.From.Unix(),
+			To:           h.To.Unix(),
+			Interval:     int64(h.Interval.Seconds()),
+			RewardMethod: h.RewardMethod,
+		},
+		Points: make([]*proto.HashratePoint, 0, len(h.Points)),
+	}
+	for _, p := range h.Points {
+		resp.Points = append(resp.Points, &proto.HashratePoint{
+			Time:     p.Time.Unix(),
+			Hashrate: p.Hashrate,
+		})
+	}
+
+	return resp
+}
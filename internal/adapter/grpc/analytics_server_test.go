@@ -0,0 +1,108 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/adapter/grpc/proto"
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+	"github.com/dnsoftware/mpm-shares-processor/internal/usecase/analitics"
+)
+
+type testShareStorage struct{}
+
+func (s *testShareStorage) CoinDifficultyWindowSums(ctx context.Context, coinID int64, periodEnd time.Time, windows []time.Duration) ([]float64, error) {
+	sums := make([]float64, len(windows))
+	for i, w := range windows {
+		sums[i] = w.Seconds()
+	}
+	return sums, nil
+}
+
+func (s *testShareStorage) WalletDifficultyWindowSums(ctx context.Context, walletID int64, periodEnd time.Time, windows []time.Duration) (int64, []float64, error) {
+	return 0, make([]float64, len(windows)), nil
+}
+
+func (s *testShareStorage) WorkerDifficultyWindowSums(ctx context.Context, workerID int64, periodEnd time.Time, windows []time.Duration) (int64, []float64, error) {
+	return 0, make([]float64, len(windows)), nil
+}
+
+func (s *testShareStorage) DifficultyIntervalGroupGlobal(ctx context.Context, periodStart time.Time, periodEnd time.Time, interval int, coinID int64, rewardMethod string) ([]time.Time, []float64, error) {
+	return []time.Time{periodStart}, []float64{float64(interval)}, nil
+}
+
+func (s *testShareStorage) DifficultyIntervalGroupWallet(ctx context.Context, periodStart time.Time, periodEnd time.Time, interval int, coinID int64, walletID int64, rewardMethod string) ([]time.Time, []float64, error) {
+	return nil, nil, nil
+}
+
+func (s *testShareStorage) DifficultyIntervalGroupWorker(ctx context.Context, periodStart time.Time, periodEnd time.Time, interval int, coinID int64, workerID int64, rewardMethod string) ([]time.Time, []float64, error) {
+	return nil, nil, nil
+}
+
+func (s *testShareStorage) RangeDifficultySum(ctx context.Context, dateStart time.Time, dateEnd time.Time, coinID int64, rewardMethod string) (float64, uint64, error) {
+	return 12.5, 3, nil
+}
+
+func (s *testShareStorage) WalletNonceReplayCounts(ctx context.Context, walletID int64, periodStart time.Time, periodEnd time.Time) ([]entity.NonceReplayCount, error) {
+	return nil, nil
+}
+
+type testCoinStorage struct{}
+
+func (c testCoinStorage) GetCoinIDByName(ctx context.Context, coin string) (int64, error) {
+	if coin == "ALPH" {
+		return 4, nil
+	}
+	return 0, nil
+}
+
+func (c testCoinStorage) GetCoinAlgorithm(ctx context.Context, coinID int64) (string, error) {
+	if coinID == 4 {
+		return "Blake 3", nil
+	}
+	return "", nil
+}
+
+func TestAnalyticsServer(t *testing.T) {
+	cfg := analitics.Config{AlgorithmMultipliers: map[string]float64{"blake3": 1}}
+	server, err := NewAnalyticsServer(analitics.NewAnaliticsUsecase(cfg, &testShareStorage{}, testCoinStorage{}))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	hr, err := server.GetCoinHashrate(ctx, &proto.GetCoinHashrateRequest{Coin: "alph"})
+	require.NoError(t, err)
+	require.Equal(t, int64(600), hr.Current.Window)
+	require.Equal(t, float64(1), hr.Current.Hashrate)
+	require.Len(t, hr.Averages, 2)
+
+	_, err = server.GetCoinHashrate(ctx, &proto.GetCoinHashrateRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// неизвестная монета - ошибка клиента
+	_, err = server.GetCoinHashrate(ctx, &proto.GetCoinHashrateRequest{Coin: "UNKNOWN"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = server.GetCoinHashrateHistory(ctx, &proto.GetCoinHashrateHistoryRequest{Coin: "UNKNOWN"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = server.GetRoundDifficultySum(ctx, &proto.GetRoundDifficultySumRequest{Coin: "UNKNOWN", DateStart: 1000, DateEnd: 2000})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	history, err := server.GetCoinHashrateHistory(ctx, &proto.GetCoinHashrateHistoryRequest{
+		Coin:   "ALPH",
+		Params: &proto.HistoryParams{From: 600, To: 1200, Interval: 60},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "PPLNS", history.Params.RewardMethod)
+	require.Len(t, history.Points, 10)
+	require.Equal(t, float64(1), history.Points[0].Hashrate)
+	require.Equal(t, float64(0), history.Points[1].Hashrate)
+
+	round, err := server.GetRoundDifficultySum(ctx, &proto.GetRoundDifficultySumRequest{Coin: "ALPH", DateStart: 1000, DateEnd: 2000, RewardMethod: "pplns"})
+	require.NoError(t, err)
+	require.Equal(t, 12.5, round.DifficultySum)
+	require.Equal(t, uint64(3), round.SharesCount)
+}
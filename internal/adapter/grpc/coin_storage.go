@@ -30,8 +30,20 @@ func (g *GRPCCoinStorage) GetCoinIDByName(ctx context.Context, coin string) (int
 	})
 
 	if err != nil {
-		return 0, err
+		return 0, clientError(err)
 	}
 
 	return resp.Id, err
 }
+
+// GetCoinAlgorithm алгоритм майнинга монеты из справочника (пусто - алгоритм в справочнике не задан)
+func (g *GRPCCoinStorage) GetCoinAlgorithm(ctx context.Context, coinID int64) (string, error) {
+	resp, err := g.client.GetCoinAlgorithm(ctx, &proto.GetCoinAlgorithmRequest{
+		CoinId: coinID,
+	})
+	if err != nil {
+		return "", clientError(err)
+	}
+
+	return resp.Algo, nil
+}
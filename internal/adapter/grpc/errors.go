@@ -0,0 +1,35 @@
+package grpc
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+	"github.com/dnsoftware/mpm-shares-processor/internal/usecase/analitics"
+)
+
+// clientError классификация ошибки вызова gRPC сервиса
+// недоступность сервиса и таймауты - временные ошибки (entity.ErrServiceUnavailable)
+func clientError(err error) error {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return fmt.Errorf("%w: %w", entity.ErrServiceUnavailable, err)
+	}
+
+	return err
+}
+
+// statusCode код ответа клиенту по классу ошибки
+func statusCode(err error) codes.Code {
+	switch {
+	case entity.IsTransient(err):
+		return codes.Unavailable
+	case entity.IsPermanent(err), errors.Is(err, analitics.ErrInvalidQuery):
+		return codes.InvalidArgument
+	default:
+		return codes.Internal
+	}
+}
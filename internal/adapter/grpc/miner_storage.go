@@ -2,16 +2,25 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/dnsoftware/mpm-shares-processor/internal/adapter/grpc/proto"
 	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
 )
 
+// ErrBatchNotSupported сервис майнеров не поддерживает пакетное разрешение кошельков и воркеров
+var ErrBatchNotSupported = errors.New("miners service does not support ResolveIdentitiesBatch")
+
 type GRPCMinerStorage struct {
-	client proto.MinersServiceClient
-	conn   *grpc.ClientConn
+	client           proto.MinersServiceClient
+	conn             *grpc.ClientConn
+	batchUnsupported atomic.Bool // сервис майнеров не поддерживает ResolveIdentitiesBatch
 }
 
 func NewMinerStorage(conn *grpc.ClientConn) (*GRPCMinerStorage, error) {
@@ -32,7 +41,7 @@ func (g *GRPCMinerStorage) CreateWallet(ctx context.Context, wallet entity.Walle
 	})
 
 	if err != nil {
-		return 0, err
+		return 0, clientError(err)
 	}
 
 	return resp.Id, err
@@ -52,7 +61,7 @@ func (g *GRPCMinerStorage) CreateWorker(ctx context.Context, worker entity.Worke
 	})
 
 	if err != nil {
-		return 0, err
+		return 0, clientError(err)
 	}
 
 	return resp.Id, err
@@ -66,7 +75,7 @@ func (g *GRPCMinerStorage) GetWalletIDByName(ctx context.Context, wallet string,
 	})
 
 	if err != nil {
-		return 0, err
+		return 0, clientError(err)
 	}
 
 	return resp.Id, err
@@ -80,8 +89,56 @@ func (g *GRPCMinerStorage) GetWorkerIDByName(ctx context.Context, worker string,
 	})
 
 	if err != nil {
-		return 0, err
+		return 0, clientError(err)
 	}
 
 	return resp.Id, err
 }
+
+// ResolveIdentitiesBatch получение (создание при отсутствии) кошельков и воркеров одним запросом
+// Возвращает identities с заполненными WalletID и WorkerID (0 - если для записи не удалось)
+func (g *GRPCMinerStorage) ResolveIdentitiesBatch(ctx context.Context, identities []entity.Identity) ([]entity.Identity, error) {
+	if g.batchUnsupported.Load() {
+		return nil, ErrBatchNotSupported
+	}
+
+	req := &proto.ResolveIdentitiesBatchRequest{
+		Identities: make([]*proto.Identity, 0, len(identities)),
+	}
+	for _, ident := range identities {
+		req.Identities = append(req.Identities, &proto.Identity{
+			CoinId:       ident.CoinID,
+			Workerfull:   ident.Workerfull,
+			Wallet:       ident.Wallet,
+			Worker:       ident.Worker,
+			ServerId:     ident.ServerID,
+			Ip:           ident.IP,
+			IsSolo:       ident.IsSolo,
+			RewardMethod: ident.RewardMethod,
+		})
+	}
+
+	resp, err := g.client.ResolveIdentitiesBatch(ctx, req)
+	if err != nil {
+		if status.Code(err) == codes.Unimplemented {
+			g.batchUnsupported.Store(true)
+			return nil, ErrBatchNotSupported
+		}
+		return nil, clientError(err)
+	}
+	if len(resp.Identities) != len(identities) {
+		return nil, fmt.Errorf("ResolveIdentitiesBatch: got %d identities, want %d", len(resp.Identities), len(identities))
+	}
+
+	resolved := make([]entity.Identity, len(identities))
+	for i, ident := range identities {
+		resolved[i] = ident
+		if resp.Identities[i].Error != "" {
+			continue
+		}
+		resolved[i].WalletID = resp.Identities[i].WalletId
+		resolved[i].WorkerID = resp.Identities[i].WorkerId
+	}
+
+	return resolved, nil
+}
@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.34.1
+// 	protoc-gen-go v1.36.5
 // 	protoc        v3.12.4
 // source: proto/miners.proto
 
@@ -11,6 +11,7 @@ import (
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
 	sync "sync"
+	unsafe "unsafe"
 )
 
 const (
@@ -21,20 +22,17 @@ const (
 )
 
 type GetCoinIDByNameRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Coin          string                 `protobuf:"bytes,1,opt,name=coin,proto3" json:"coin,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Coin string `protobuf:"bytes,1,opt,name=coin,proto3" json:"coin,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetCoinIDByNameRequest) Reset() {
 	*x = GetCoinIDByNameRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_miners_proto_msgTypes[0]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_proto_miners_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *GetCoinIDByNameRequest) String() string {
@@ -45,7 +43,7 @@ func (*GetCoinIDByNameRequest) ProtoMessage() {}
 
 func (x *GetCoinIDByNameRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_miners_proto_msgTypes[0]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -68,20 +66,17 @@ func (x *GetCoinIDByNameRequest) GetCoin() string {
 }
 
 type GetCoinIDByNameResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetCoinIDByNameResponse) Reset() {
 	*x = GetCoinIDByNameResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_miners_proto_msgTypes[1]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_proto_miners_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *GetCoinIDByNameResponse) String() string {
@@ -92,7 +87,7 @@ func (*GetCoinIDByNameResponse) ProtoMessage() {}
 
 func (x *GetCoinIDByNameResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_miners_proto_msgTypes[1]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -115,24 +110,21 @@ func (x *GetCoinIDByNameResponse) GetId() int64 {
 }
 
 type CreateWalletRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	CoinId        int64                  `protobuf:"varint,2,opt,name=coin_id,json=coinId,proto3" json:"coin_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	IsSolo        bool                   `protobuf:"varint,4,opt,name=is_solo,json=isSolo,proto3" json:"is_solo,omitempty"`
+	RewardMethod  string                 `protobuf:"bytes,5,opt,name=reward_method,json=rewardMethod,proto3" json:"reward_method,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Id           int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	CoinId       int64  `protobuf:"varint,2,opt,name=coin_id,json=coinId,proto3" json:"coin_id,omitempty"`
-	Name         string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
-	IsSolo       bool   `protobuf:"varint,4,opt,name=is_solo,json=isSolo,proto3" json:"is_solo,omitempty"`
-	RewardMethod string `protobuf:"bytes,5,opt,name=reward_method,json=rewardMethod,proto3" json:"reward_method,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateWalletRequest) Reset() {
 	*x = CreateWalletRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_miners_proto_msgTypes[2]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_proto_miners_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *CreateWalletRequest) String() string {
@@ -143,7 +135,7 @@ func (*CreateWalletRequest) ProtoMessage() {}
 
 func (x *CreateWalletRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_miners_proto_msgTypes[2]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -194,20 +186,17 @@ func (x *CreateWalletRequest) GetRewardMethod() string {
 }
 
 type CreateWalletResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateWalletResponse) Reset() {
 	*x = CreateWalletResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_miners_proto_msgTypes[3]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_proto_miners_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *CreateWalletResponse) String() string {
@@ -218,7 +207,7 @@ func (*CreateWalletResponse) ProtoMessage() {}
 
 func (x *CreateWalletResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_miners_proto_msgTypes[3]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -241,28 +230,25 @@ func (x *CreateWalletResponse) GetId() int64 {
 }
 
 type CreateWorkerRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	CoinId        int64                  `protobuf:"varint,2,opt,name=coin_id,json=coinId,proto3" json:"coin_id,omitempty"`
+	Workerfull    string                 `protobuf:"bytes,3,opt,name=workerfull,proto3" json:"workerfull,omitempty"`
+	Wallet        string                 `protobuf:"bytes,4,opt,name=wallet,proto3" json:"wallet,omitempty"`
+	Worker        string                 `protobuf:"bytes,5,opt,name=worker,proto3" json:"worker,omitempty"`
+	ServerId      string                 `protobuf:"bytes,6,opt,name=server_id,json=serverId,proto3" json:"server_id,omitempty"`
+	Ip            string                 `protobuf:"bytes,7,opt,name=ip,proto3" json:"ip,omitempty"`
+	IsSolo        bool                   `protobuf:"varint,8,opt,name=is_solo,json=isSolo,proto3" json:"is_solo,omitempty"`
+	RewardMethod  string                 `protobuf:"bytes,9,opt,name=reward_method,json=rewardMethod,proto3" json:"reward_method,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Id           int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	CoinId       int64  `protobuf:"varint,2,opt,name=coin_id,json=coinId,proto3" json:"coin_id,omitempty"`
-	Workerfull   string `protobuf:"bytes,3,opt,name=workerfull,proto3" json:"workerfull,omitempty"`
-	Wallet       string `protobuf:"bytes,4,opt,name=wallet,proto3" json:"wallet,omitempty"`
-	Worker       string `protobuf:"bytes,5,opt,name=worker,proto3" json:"worker,omitempty"`
-	ServerId     string `protobuf:"bytes,6,opt,name=server_id,json=serverId,proto3" json:"server_id,omitempty"`
-	Ip           string `protobuf:"bytes,7,opt,name=ip,proto3" json:"ip,omitempty"`
-	IsSolo       bool   `protobuf:"varint,8,opt,name=is_solo,json=isSolo,proto3" json:"is_solo,omitempty"`
-	RewardMethod string `protobuf:"bytes,9,opt,name=reward_method,json=rewardMethod,proto3" json:"reward_method,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateWorkerRequest) Reset() {
 	*x = CreateWorkerRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_miners_proto_msgTypes[4]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_proto_miners_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *CreateWorkerRequest) String() string {
@@ -273,7 +259,7 @@ func (*CreateWorkerRequest) ProtoMessage() {}
 
 func (x *CreateWorkerRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_miners_proto_msgTypes[4]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -352,20 +338,17 @@ func (x *CreateWorkerRequest) GetRewardMethod() string {
 }
 
 type CreateWorkerResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateWorkerResponse) Reset() {
 	*x = CreateWorkerResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_miners_proto_msgTypes[5]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_proto_miners_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *CreateWorkerResponse) String() string {
@@ -376,7 +359,7 @@ func (*CreateWorkerResponse) ProtoMessage() {}
 
 func (x *CreateWorkerResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_miners_proto_msgTypes[5]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -399,22 +382,19 @@ func (x *CreateWorkerResponse) GetId() int64 {
 }
 
 type GetWalletIDByNameRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Wallet        string                 `protobuf:"bytes,1,opt,name=wallet,proto3" json:"wallet,omitempty"`
+	CoinId        int64                  `protobuf:"varint,2,opt,name=coin_id,json=coinId,proto3" json:"coin_id,omitempty"`
+	RewardMethod  string                 `protobuf:"bytes,3,opt,name=reward_method,json=rewardMethod,proto3" json:"reward_method,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Wallet       string `protobuf:"bytes,1,opt,name=wallet,proto3" json:"wallet,omitempty"`
-	CoinId       int64  `protobuf:"varint,2,opt,name=coin_id,json=coinId,proto3" json:"coin_id,omitempty"`
-	RewardMethod string `protobuf:"bytes,3,opt,name=reward_method,json=rewardMethod,proto3" json:"reward_method,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetWalletIDByNameRequest) Reset() {
 	*x = GetWalletIDByNameRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_miners_proto_msgTypes[6]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_proto_miners_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *GetWalletIDByNameRequest) String() string {
@@ -425,7 +405,7 @@ func (*GetWalletIDByNameRequest) ProtoMessage() {}
 
 func (x *GetWalletIDByNameRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_miners_proto_msgTypes[6]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -462,20 +442,17 @@ func (x *GetWalletIDByNameRequest) GetRewardMethod() string {
 }
 
 type GetWalletIDByNameResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetWalletIDByNameResponse) Reset() {
 	*x = GetWalletIDByNameResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_miners_proto_msgTypes[7]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_proto_miners_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *GetWalletIDByNameResponse) String() string {
@@ -486,7 +463,7 @@ func (*GetWalletIDByNameResponse) ProtoMessage() {}
 
 func (x *GetWalletIDByNameResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_miners_proto_msgTypes[7]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -509,22 +486,19 @@ func (x *GetWalletIDByNameResponse) GetId() int64 {
 }
 
 type GetWorkerIDByNameRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Workerfull    string                 `protobuf:"bytes,1,opt,name=workerfull,proto3" json:"workerfull,omitempty"`
+	CoinId        int64                  `protobuf:"varint,2,opt,name=coin_id,json=coinId,proto3" json:"coin_id,omitempty"`
+	RewardMethod  string                 `protobuf:"bytes,3,opt,name=reward_method,json=rewardMethod,proto3" json:"reward_method,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Workerfull   string `protobuf:"bytes,1,opt,name=workerfull,proto3" json:"workerfull,omitempty"`
-	CoinId       int64  `protobuf:"varint,2,opt,name=coin_id,json=coinId,proto3" json:"coin_id,omitempty"`
-	RewardMethod string `protobuf:"bytes,3,opt,name=reward_method,json=rewardMethod,proto3" json:"reward_method,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetWorkerIDByNameRequest) Reset() {
 	*x = GetWorkerIDByNameRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_miners_proto_msgTypes[8]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_proto_miners_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *GetWorkerIDByNameRequest) String() string {
@@ -535,7 +509,7 @@ func (*GetWorkerIDByNameRequest) ProtoMessage() {}
 
 func (x *GetWorkerIDByNameRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_miners_proto_msgTypes[8]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -572,20 +546,17 @@ func (x *GetWorkerIDByNameRequest) GetRewardMethod() string {
 }
 
 type GetWorkerIDByNameResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetWorkerIDByNameResponse) Reset() {
 	*x = GetWorkerIDByNameResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_miners_proto_msgTypes[9]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_proto_miners_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *GetWorkerIDByNameResponse) String() string {
@@ -596,7 +567,7 @@ func (*GetWorkerIDByNameResponse) ProtoMessage() {}
 
 func (x *GetWorkerIDByNameResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_miners_proto_msgTypes[9]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -618,23 +589,358 @@ func (x *GetWorkerIDByNameResponse) GetId() int64 {
 	return 0
 }
 
-// Сообщение для деталей ошибки
-type MPError struct {
-	state         protoimpl.MessageState
+// Кошелек и воркер шары (создаются, если их еще нет)
+type Identity struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CoinId        int64                  `protobuf:"varint,1,opt,name=coin_id,json=coinId,proto3" json:"coin_id,omitempty"`
+	Workerfull    string                 `protobuf:"bytes,2,opt,name=workerfull,proto3" json:"workerfull,omitempty"`
+	Wallet        string                 `protobuf:"bytes,3,opt,name=wallet,proto3" json:"wallet,omitempty"`
+	Worker        string                 `protobuf:"bytes,4,opt,name=worker,proto3" json:"worker,omitempty"`
+	ServerId      string                 `protobuf:"bytes,5,opt,name=server_id,json=serverId,proto3" json:"server_id,omitempty"`
+	Ip            string                 `protobuf:"bytes,6,opt,name=ip,proto3" json:"ip,omitempty"`
+	IsSolo        bool                   `protobuf:"varint,7,opt,name=is_solo,json=isSolo,proto3" json:"is_solo,omitempty"`
+	RewardMethod  string                 `protobuf:"bytes,8,opt,name=reward_method,json=rewardMethod,proto3" json:"reward_method,omitempty"`
+	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Identity) Reset() {
+	*x = Identity{}
+	mi := &file_proto_miners_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Identity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Identity) ProtoMessage() {}
+
+func (x *Identity) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_miners_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Identity.ProtoReflect.Descriptor instead.
+func (*Identity) Descriptor() ([]byte, []int) {
+	return file_proto_miners_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Identity) GetCoinId() int64 {
+	if x != nil {
+		return x.CoinId
+	}
+	return 0
+}
+
+func (x *Identity) GetWorkerfull() string {
+	if x != nil {
+		return x.Workerfull
+	}
+	return ""
+}
+
+func (x *Identity) GetWallet() string {
+	if x != nil {
+		return x.Wallet
+	}
+	return ""
+}
+
+func (x *Identity) GetWorker() string {
+	if x != nil {
+		return x.Worker
+	}
+	return ""
+}
+
+func (x *Identity) GetServerId() string {
+	if x != nil {
+		return x.ServerId
+	}
+	return ""
+}
+
+func (x *Identity) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *Identity) GetIsSolo() bool {
+	if x != nil {
+		return x.IsSolo
+	}
+	return false
+}
+
+func (x *Identity) GetRewardMethod() string {
+	if x != nil {
+		return x.RewardMethod
+	}
+	return ""
+}
+
+type ResolveIdentitiesBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Identities    []*Identity            `protobuf:"bytes,1,rep,name=identities,proto3" json:"identities,omitempty"`
 	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-	Method      string `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`           // метод, где возникла ошибка
-	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"` // описание
+func (x *ResolveIdentitiesBatchRequest) Reset() {
+	*x = ResolveIdentitiesBatchRequest{}
+	mi := &file_proto_miners_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *MPError) Reset() {
-	*x = MPError{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_miners_proto_msgTypes[10]
+func (x *ResolveIdentitiesBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveIdentitiesBatchRequest) ProtoMessage() {}
+
+func (x *ResolveIdentitiesBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_miners_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveIdentitiesBatchRequest.ProtoReflect.Descriptor instead.
+func (*ResolveIdentitiesBatchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_miners_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ResolveIdentitiesBatchRequest) GetIdentities() []*Identity {
+	if x != nil {
+		return x.Identities
+	}
+	return nil
+}
+
+type ResolvedIdentity struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WalletId      int64                  `protobuf:"varint,1,opt,name=wallet_id,json=walletId,proto3" json:"wallet_id,omitempty"`
+	WorkerId      int64                  `protobuf:"varint,2,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"` // не пусто, если не удалось получить/создать кошелек или воркер
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolvedIdentity) Reset() {
+	*x = ResolvedIdentity{}
+	mi := &file_proto_miners_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolvedIdentity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolvedIdentity) ProtoMessage() {}
+
+func (x *ResolvedIdentity) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_miners_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolvedIdentity.ProtoReflect.Descriptor instead.
+func (*ResolvedIdentity) Descriptor() ([]byte, []int) {
+	return file_proto_miners_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ResolvedIdentity) GetWalletId() int64 {
+	if x != nil {
+		return x.WalletId
+	}
+	return 0
+}
+
+func (x *ResolvedIdentity) GetWorkerId() int64 {
+	if x != nil {
+		return x.WorkerId
+	}
+	return 0
+}
+
+func (x *ResolvedIdentity) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ResolveIdentitiesBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Identities    []*ResolvedIdentity    `protobuf:"bytes,1,rep,name=identities,proto3" json:"identities,omitempty"` // в порядке запроса
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveIdentitiesBatchResponse) Reset() {
+	*x = ResolveIdentitiesBatchResponse{}
+	mi := &file_proto_miners_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveIdentitiesBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveIdentitiesBatchResponse) ProtoMessage() {}
+
+func (x *ResolveIdentitiesBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_miners_proto_msgTypes[13]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveIdentitiesBatchResponse.ProtoReflect.Descriptor instead.
+func (*ResolveIdentitiesBatchResponse) Descriptor() ([]byte, []int) {
+	return file_proto_miners_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ResolveIdentitiesBatchResponse) GetIdentities() []*ResolvedIdentity {
+	if x != nil {
+		return x.Identities
+	}
+	return nil
+}
+
+// Алгоритм майнинга монеты из справочника монет (поле coins.algo)
+type GetCoinAlgorithmRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CoinId        int64                  `protobuf:"varint,1,opt,name=coin_id,json=coinId,proto3" json:"coin_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCoinAlgorithmRequest) Reset() {
+	*x = GetCoinAlgorithmRequest{}
+	mi := &file_proto_miners_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCoinAlgorithmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCoinAlgorithmRequest) ProtoMessage() {}
+
+func (x *GetCoinAlgorithmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_miners_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCoinAlgorithmRequest.ProtoReflect.Descriptor instead.
+func (*GetCoinAlgorithmRequest) Descriptor() ([]byte, []int) {
+	return file_proto_miners_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetCoinAlgorithmRequest) GetCoinId() int64 {
+	if x != nil {
+		return x.CoinId
+	}
+	return 0
+}
+
+type GetCoinAlgorithmResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Algo          string                 `protobuf:"bytes,1,opt,name=algo,proto3" json:"algo,omitempty"` // название алгоритма, как в справочнике ("Blake 3", "HeavyHash"), пусто - не задан
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCoinAlgorithmResponse) Reset() {
+	*x = GetCoinAlgorithmResponse{}
+	mi := &file_proto_miners_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCoinAlgorithmResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCoinAlgorithmResponse) ProtoMessage() {}
+
+func (x *GetCoinAlgorithmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_miners_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCoinAlgorithmResponse.ProtoReflect.Descriptor instead.
+func (*GetCoinAlgorithmResponse) Descriptor() ([]byte, []int) {
+	return file_proto_miners_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetCoinAlgorithmResponse) GetAlgo() string {
+	if x != nil {
+		return x.Algo
+	}
+	return ""
+}
+
+// Сообщение для деталей ошибки
+type MPError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Method        string                 `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`           // метод, где возникла ошибка
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"` // описание
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MPError) Reset() {
+	*x = MPError{}
+	mi := &file_proto_miners_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *MPError) String() string {
@@ -644,8 +950,8 @@ func (x *MPError) String() string {
 func (*MPError) ProtoMessage() {}
 
 func (x *MPError) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_miners_proto_msgTypes[10]
-	if protoimpl.UnsafeEnabled && x != nil {
+	mi := &file_proto_miners_proto_msgTypes[16]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -657,7 +963,7 @@ func (x *MPError) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MPError.ProtoReflect.Descriptor instead.
 func (*MPError) Descriptor() ([]byte, []int) {
-	return file_proto_miners_proto_rawDescGZIP(), []int{10}
+	return file_proto_miners_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *MPError) GetMethod() string {
@@ -676,7 +982,7 @@ func (x *MPError) GetDescription() string {
 
 var File_proto_miners_proto protoreflect.FileDescriptor
 
-var file_proto_miners_proto_rawDesc = []byte{
+var file_proto_miners_proto_rawDesc = string([]byte{
 	0x0a, 0x12, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x73, 0x2e, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x67, 0x72, 0x70, 0x63, 0x22, 0x2c, 0x0a, 0x16, 0x47, 0x65,
 	0x74, 0x43, 0x6f, 0x69, 0x6e, 0x49, 0x44, 0x42, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71,
@@ -734,83 +1040,144 @@ var file_proto_miners_proto_rawDesc = []byte{
 	0x65, 0x77, 0x61, 0x72, 0x64, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x22, 0x2b, 0x0a, 0x19, 0x47,
 	0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x49, 0x44, 0x42, 0x79, 0x4e, 0x61, 0x6d, 0x65,
 	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x22, 0x43, 0x0a, 0x07, 0x4d, 0x50, 0x45, 0x72,
-	0x72, 0x6f, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x64,
-	0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x32, 0x99, 0x03,
-	0x0a, 0x0d, 0x4d, 0x69, 0x6e, 0x65, 0x72, 0x73, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12,
-	0x4e, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x69, 0x6e, 0x49, 0x44, 0x42, 0x79, 0x4e, 0x61,
-	0x6d, 0x65, 0x12, 0x1c, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x69,
-	0x6e, 0x49, 0x44, 0x42, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x1d, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x69, 0x6e, 0x49,
-	0x44, 0x42, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x45, 0x0a, 0x0c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x12,
-	0x19, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x57, 0x61, 0x6c,
-	0x6c, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x67, 0x72, 0x70,
-	0x63, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x57, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a, 0x0c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
-	0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x12, 0x19, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x72,
-	0x65, 0x61, 0x74, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x1a, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x57,
-	0x6f, 0x72, 0x6b, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x54, 0x0a,
-	0x11, 0x47, 0x65, 0x74, 0x57, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x49, 0x44, 0x42, 0x79, 0x4e, 0x61,
-	0x6d, 0x65, 0x12, 0x1e, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x57, 0x61, 0x6c,
-	0x6c, 0x65, 0x74, 0x49, 0x44, 0x42, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x57, 0x61, 0x6c,
-	0x6c, 0x65, 0x74, 0x49, 0x44, 0x42, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x54, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72,
-	0x49, 0x44, 0x42, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1e, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
-	0x47, 0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x49, 0x44, 0x42, 0x79, 0x4e, 0x61, 0x6d,
-	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
-	0x47, 0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x49, 0x44, 0x42, 0x79, 0x4e, 0x61, 0x6d,
-	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x1d, 0x5a, 0x1b, 0x69, 0x6e, 0x74,
-	0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x61, 0x64, 0x61, 0x70, 0x74, 0x65, 0x72, 0x2f, 0x67, 0x72,
-	0x70, 0x63, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
-}
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x22, 0xde, 0x01, 0x0a, 0x08, 0x49, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x6f, 0x69, 0x6e, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x63, 0x6f, 0x69, 0x6e, 0x49, 0x64, 0x12, 0x1e,
+	0x0a, 0x0a, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x66, 0x75, 0x6c, 0x6c, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x66, 0x75, 0x6c, 0x6c, 0x12, 0x16,
+	0x0a, 0x06, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x12, 0x1b,
+	0x0a, 0x09, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x49, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x70, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x12, 0x17, 0x0a, 0x07, 0x69,
+	0x73, 0x5f, 0x73, 0x6f, 0x6c, 0x6f, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x69, 0x73,
+	0x53, 0x6f, 0x6c, 0x6f, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x5f, 0x6d,
+	0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x65, 0x77,
+	0x61, 0x72, 0x64, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x22, 0x4f, 0x0a, 0x1d, 0x52, 0x65, 0x73,
+	0x6f, 0x6c, 0x76, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2e, 0x0a, 0x0a, 0x69, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x0a,
+	0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0x62, 0x0a, 0x10, 0x52, 0x65,
+	0x73, 0x6f, 0x6c, 0x76, 0x65, 0x64, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x1b,
+	0x0a, 0x09, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x08, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x77,
+	0x6f, 0x72, 0x6b, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08,
+	0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x58,
+	0x0a, 0x1e, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74,
+	0x69, 0x65, 0x73, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x36, 0x0a, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x73, 0x6f,
+	0x6c, 0x76, 0x65, 0x64, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x0a, 0x69, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0x32, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x43,
+	0x6f, 0x69, 0x6e, 0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x6f, 0x69, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x63, 0x6f, 0x69, 0x6e, 0x49, 0x64, 0x22, 0x2e, 0x0a, 0x18,
+	0x47, 0x65, 0x74, 0x43, 0x6f, 0x69, 0x6e, 0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x6c, 0x67, 0x6f,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x61, 0x6c, 0x67, 0x6f, 0x22, 0x43, 0x0a, 0x07,
+	0x4d, 0x50, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12,
+	0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x32, 0xd1, 0x04, 0x0a, 0x0d, 0x4d, 0x69, 0x6e, 0x65, 0x72, 0x73, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x12, 0x4e, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x69, 0x6e, 0x49, 0x44,
+	0x42, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65,
+	0x74, 0x43, 0x6f, 0x69, 0x6e, 0x49, 0x44, 0x42, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x43,
+	0x6f, 0x69, 0x6e, 0x49, 0x44, 0x42, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a, 0x0c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x57, 0x61, 0x6c,
+	0x6c, 0x65, 0x74, 0x12, 0x19, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x57, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x57, 0x61, 0x6c, 0x6c,
+	0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a, 0x0c, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x12, 0x19, 0x2e, 0x67, 0x72, 0x70,
+	0x63, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x54, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x57, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x49, 0x44,
+	0x42, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1e, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65,
+	0x74, 0x57, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x49, 0x44, 0x42, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65,
+	0x74, 0x57, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x49, 0x44, 0x42, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x54, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x57, 0x6f,
+	0x72, 0x6b, 0x65, 0x72, 0x49, 0x44, 0x42, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1e, 0x2e, 0x67,
+	0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x49, 0x44, 0x42,
+	0x79, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x67,
+	0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x49, 0x44, 0x42,
+	0x79, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x63, 0x0a,
+	0x16, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69,
+	0x65, 0x73, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x23, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x52,
+	0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x67,
+	0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74,
+	0x69, 0x74, 0x69, 0x65, 0x73, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x51, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x69, 0x6e, 0x41, 0x6c, 0x67,
+	0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x12, 0x1d, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65,
+	0x74, 0x43, 0x6f, 0x69, 0x6e, 0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74,
+	0x43, 0x6f, 0x69, 0x6e, 0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x1d, 0x5a, 0x1b, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61,
+	0x6c, 0x2f, 0x61, 0x64, 0x61, 0x70, 0x74, 0x65, 0x72, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+})
 
 var (
 	file_proto_miners_proto_rawDescOnce sync.Once
-	file_proto_miners_proto_rawDescData = file_proto_miners_proto_rawDesc
+	file_proto_miners_proto_rawDescData []byte
 )
 
 func file_proto_miners_proto_rawDescGZIP() []byte {
 	file_proto_miners_proto_rawDescOnce.Do(func() {
-		file_proto_miners_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_miners_proto_rawDescData)
+		file_proto_miners_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_miners_proto_rawDesc), len(file_proto_miners_proto_rawDesc)))
 	})
 	return file_proto_miners_proto_rawDescData
 }
 
-var file_proto_miners_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
-var file_proto_miners_proto_goTypes = []interface{}{
-	(*GetCoinIDByNameRequest)(nil),    // 0: grpc.GetCoinIDByNameRequest
-	(*GetCoinIDByNameResponse)(nil),   // 1: grpc.GetCoinIDByNameResponse
-	(*CreateWalletRequest)(nil),       // 2: grpc.CreateWalletRequest
-	(*CreateWalletResponse)(nil),      // 3: grpc.CreateWalletResponse
-	(*CreateWorkerRequest)(nil),       // 4: grpc.CreateWorkerRequest
-	(*CreateWorkerResponse)(nil),      // 5: grpc.CreateWorkerResponse
-	(*GetWalletIDByNameRequest)(nil),  // 6: grpc.GetWalletIDByNameRequest
-	(*GetWalletIDByNameResponse)(nil), // 7: grpc.GetWalletIDByNameResponse
-	(*GetWorkerIDByNameRequest)(nil),  // 8: grpc.GetWorkerIDByNameRequest
-	(*GetWorkerIDByNameResponse)(nil), // 9: grpc.GetWorkerIDByNameResponse
-	(*MPError)(nil),                   // 10: grpc.MPError
+var file_proto_miners_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_proto_miners_proto_goTypes = []any{
+	(*GetCoinIDByNameRequest)(nil),         // 0: grpc.GetCoinIDByNameRequest
+	(*GetCoinIDByNameResponse)(nil),        // 1: grpc.GetCoinIDByNameResponse
+	(*CreateWalletRequest)(nil),            // 2: grpc.CreateWalletRequest
+	(*CreateWalletResponse)(nil),           // 3: grpc.CreateWalletResponse
+	(*CreateWorkerRequest)(nil),            // 4: grpc.CreateWorkerRequest
+	(*CreateWorkerResponse)(nil),           // 5: grpc.CreateWorkerResponse
+	(*GetWalletIDByNameRequest)(nil),       // 6: grpc.GetWalletIDByNameRequest
+	(*GetWalletIDByNameResponse)(nil),      // 7: grpc.GetWalletIDByNameResponse
+	(*GetWorkerIDByNameRequest)(nil),       // 8: grpc.GetWorkerIDByNameRequest
+	(*GetWorkerIDByNameResponse)(nil),      // 9: grpc.GetWorkerIDByNameResponse
+	(*Identity)(nil),                       // 10: grpc.Identity
+	(*ResolveIdentitiesBatchRequest)(nil),  // 11: grpc.ResolveIdentitiesBatchRequest
+	(*ResolvedIdentity)(nil),               // 12: grpc.ResolvedIdentity
+	(*ResolveIdentitiesBatchResponse)(nil), // 13: grpc.ResolveIdentitiesBatchResponse
+	(*GetCoinAlgorithmRequest)(nil),        // 14: grpc.GetCoinAlgorithmRequest
+	(*GetCoinAlgorithmResponse)(nil),       // 15: grpc.GetCoinAlgorithmResponse
+	(*MPError)(nil),                        // 16: grpc.MPError
 }
 var file_proto_miners_proto_depIdxs = []int32{
-	0, // 0: grpc.MinersService.GetCoinIDByName:input_type -> grpc.GetCoinIDByNameRequest
-	2, // 1: grpc.MinersService.CreateWallet:input_type -> grpc.CreateWalletRequest
-	4, // 2: grpc.MinersService.CreateWorker:input_type -> grpc.CreateWorkerRequest
-	6, // 3: grpc.MinersService.GetWalletIDByName:input_type -> grpc.GetWalletIDByNameRequest
-	8, // 4: grpc.MinersService.GetWorkerIDByName:input_type -> grpc.GetWorkerIDByNameRequest
-	1, // 5: grpc.MinersService.GetCoinIDByName:output_type -> grpc.GetCoinIDByNameResponse
-	3, // 6: grpc.MinersService.CreateWallet:output_type -> grpc.CreateWalletResponse
-	5, // 7: grpc.MinersService.CreateWorker:output_type -> grpc.CreateWorkerResponse
-	7, // 8: grpc.MinersService.GetWalletIDByName:output_type -> grpc.GetWalletIDByNameResponse
-	9, // 9: grpc.MinersService.GetWorkerIDByName:output_type -> grpc.GetWorkerIDByNameResponse
-	5, // [5:10] is the sub-list for method output_type
-	0, // [0:5] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	10, // 0: grpc.ResolveIdentitiesBatchRequest.identities:type_name -> grpc.Identity
+	12, // 1: grpc.ResolveIdentitiesBatchResponse.identities:type_name -> grpc.ResolvedIdentity
+	0,  // 2: grpc.MinersService.GetCoinIDByName:input_type -> grpc.GetCoinIDByNameRequest
+	2,  // 3: grpc.MinersService.CreateWallet:input_type -> grpc.CreateWalletRequest
+	4,  // 4: grpc.MinersService.CreateWorker:input_type -> grpc.CreateWorkerRequest
+	6,  // 5: grpc.MinersService.GetWalletIDByName:input_type -> grpc.GetWalletIDByNameRequest
+	8,  // 6: grpc.MinersService.GetWorkerIDByName:input_type -> grpc.GetWorkerIDByNameRequest
+	11, // 7: grpc.MinersService.ResolveIdentitiesBatch:input_type -> grpc.ResolveIdentitiesBatchRequest
+	14, // 8: grpc.MinersService.GetCoinAlgorithm:input_type -> grpc.GetCoinAlgorithmRequest
+	1,  // 9: grpc.MinersService.GetCoinIDByName:output_type -> grpc.GetCoinIDByNameResponse
+	3,  // 10: grpc.MinersService.CreateWallet:output_type -> grpc.CreateWalletResponse
+	5,  // 11: grpc.MinersService.CreateWorker:output_type -> grpc.CreateWorkerResponse
+	7,  // 12: grpc.MinersService.GetWalletIDByName:output_type -> grpc.GetWalletIDByNameResponse
+	9,  // 13: grpc.MinersService.GetWorkerIDByName:output_type -> grpc.GetWorkerIDByNameResponse
+	13, // 14: grpc.MinersService.ResolveIdentitiesBatch:output_type -> grpc.ResolveIdentitiesBatchResponse
+	15, // 15: grpc.MinersService.GetCoinAlgorithm:output_type -> grpc.GetCoinAlgorithmResponse
+	9,  // [9:16] is the sub-list for method output_type
+	2,  // [2:9] is the sub-list for method input_type
+	2,  // [2:2] is the sub-list for extension type_name
+	2,  // [2:2] is the sub-list for extension extendee
+	0,  // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_proto_miners_proto_init() }
@@ -818,147 +1185,13 @@ func file_proto_miners_proto_init() {
 	if File_proto_miners_proto != nil {
 		return
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_proto_miners_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetCoinIDByNameRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_proto_miners_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetCoinIDByNameResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_proto_miners_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateWalletRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_proto_miners_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateWalletResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_proto_miners_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateWorkerRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_proto_miners_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateWorkerResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_proto_miners_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetWalletIDByNameRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_proto_miners_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetWalletIDByNameResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_proto_miners_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetWorkerIDByNameRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_proto_miners_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetWorkerIDByNameResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_proto_miners_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MPError); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_proto_miners_proto_rawDesc,
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_miners_proto_rawDesc), len(file_proto_miners_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   11,
+			NumMessages:   17,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -967,7 +1200,6 @@ func file_proto_miners_proto_init() {
 		MessageInfos:      file_proto_miners_proto_msgTypes,
 	}.Build()
 	File_proto_miners_proto = out.File
-	file_proto_miners_proto_rawDesc = nil
 	file_proto_miners_proto_goTypes = nil
 	file_proto_miners_proto_depIdxs = nil
 }
@@ -19,11 +19,13 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	MinersService_GetCoinIDByName_FullMethodName   = "/grpc.MinersService/GetCoinIDByName"
-	MinersService_CreateWallet_FullMethodName      = "/grpc.MinersService/CreateWallet"
-	MinersService_CreateWorker_FullMethodName      = "/grpc.MinersService/CreateWorker"
-	MinersService_GetWalletIDByName_FullMethodName = "/grpc.MinersService/GetWalletIDByName"
-	MinersService_GetWorkerIDByName_FullMethodName = "/grpc.MinersService/GetWorkerIDByName"
+	MinersService_GetCoinIDByName_FullMethodName        = "/grpc.MinersService/GetCoinIDByName"
+	MinersService_CreateWallet_FullMethodName           = "/grpc.MinersService/CreateWallet"
+	MinersService_CreateWorker_FullMethodName           = "/grpc.MinersService/CreateWorker"
+	MinersService_GetWalletIDByName_FullMethodName      = "/grpc.MinersService/GetWalletIDByName"
+	MinersService_GetWorkerIDByName_FullMethodName      = "/grpc.MinersService/GetWorkerIDByName"
+	MinersService_ResolveIdentitiesBatch_FullMethodName = "/grpc.MinersService/ResolveIdentitiesBatch"
+	MinersService_GetCoinAlgorithm_FullMethodName       = "/grpc.MinersService/GetCoinAlgorithm"
 )
 
 // MinersServiceClient is the client API for MinersService service.
@@ -35,6 +37,8 @@ type MinersServiceClient interface {
 	CreateWorker(ctx context.Context, in *CreateWorkerRequest, opts ...grpc.CallOption) (*CreateWorkerResponse, error)
 	GetWalletIDByName(ctx context.Context, in *GetWalletIDByNameRequest, opts ...grpc.CallOption) (*GetWalletIDByNameResponse, error)
 	GetWorkerIDByName(ctx context.Context, in *GetWorkerIDByNameRequest, opts ...grpc.CallOption) (*GetWorkerIDByNameResponse, error)
+	ResolveIdentitiesBatch(ctx context.Context, in *ResolveIdentitiesBatchRequest, opts ...grpc.CallOption) (*ResolveIdentitiesBatchResponse, error)
+	GetCoinAlgorithm(ctx context.Context, in *GetCoinAlgorithmRequest, opts ...grpc.CallOption) (*GetCoinAlgorithmResponse, error)
 }
 
 type minersServiceClient struct {
@@ -90,6 +94,24 @@ func (c *minersServiceClient) GetWorkerIDByName(ctx context.Context, in *GetWork
 	return out, nil
 }
 
+func (c *minersServiceClient) ResolveIdentitiesBatch(ctx context.Context, in *ResolveIdentitiesBatchRequest, opts ...grpc.CallOption) (*ResolveIdentitiesBatchResponse, error) {
+	out := new(ResolveIdentitiesBatchResponse)
+	err := c.cc.Invoke(ctx, MinersService_ResolveIdentitiesBatch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *minersServiceClient) GetCoinAlgorithm(ctx context.Context, in *GetCoinAlgorithmRequest, opts ...grpc.CallOption) (*GetCoinAlgorithmResponse, error) {
+	out := new(GetCoinAlgorithmResponse)
+	err := c.cc.Invoke(ctx, MinersService_GetCoinAlgorithm_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MinersServiceServer is the server API for MinersService service.
 // All implementations must embed UnimplementedMinersServiceServer
 // for forward compatibility
@@ -99,6 +121,8 @@ type MinersServiceServer interface {
 	CreateWorker(context.Context, *CreateWorkerRequest) (*CreateWorkerResponse, error)
 	GetWalletIDByName(context.Context, *GetWalletIDByNameRequest) (*GetWalletIDByNameResponse, error)
 	GetWorkerIDByName(context.Context, *GetWorkerIDByNameRequest) (*GetWorkerIDByNameResponse, error)
+	ResolveIdentitiesBatch(context.Context, *ResolveIdentitiesBatchRequest) (*ResolveIdentitiesBatchResponse, error)
+	GetCoinAlgorithm(context.Context, *GetCoinAlgorithmRequest) (*GetCoinAlgorithmResponse, error)
 	mustEmbedUnimplementedMinersServiceServer()
 }
 
@@ -121,6 +145,12 @@ func (UnimplementedMinersServiceServer) GetWalletIDByName(context.Context, *GetW
 func (UnimplementedMinersServiceServer) GetWorkerIDByName(context.Context, *GetWorkerIDByNameRequest) (*GetWorkerIDByNameResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetWorkerIDByName not implemented")
 }
+func (UnimplementedMinersServiceServer) ResolveIdentitiesBatch(context.Context, *ResolveIdentitiesBatchRequest) (*ResolveIdentitiesBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveIdentitiesBatch not implemented")
+}
+func (UnimplementedMinersServiceServer) GetCoinAlgorithm(context.Context, *GetCoinAlgorithmRequest) (*GetCoinAlgorithmResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCoinAlgorithm not implemented")
+}
 func (UnimplementedMinersServiceServer) mustEmbedUnimplementedMinersServiceServer() {}
 
 // UnsafeMinersServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -224,6 +254,42 @@ func _MinersService_GetWorkerIDByName_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MinersService_ResolveIdentitiesBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveIdentitiesBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MinersServiceServer).ResolveIdentitiesBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MinersService_ResolveIdentitiesBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MinersServiceServer).ResolveIdentitiesBatch(ctx, req.(*ResolveIdentitiesBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MinersService_GetCoinAlgorithm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCoinAlgorithmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MinersServiceServer).GetCoinAlgorithm(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MinersService_GetCoinAlgorithm_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MinersServiceServer).GetCoinAlgorithm(ctx, req.(*GetCoinAlgorithmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MinersService_ServiceDesc is the grpc.ServiceDesc for MinersService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -251,6 +317,14 @@ var MinersService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetWorkerIDByName",
 			Handler:    _MinersService_GetWorkerIDByName_Handler,
 		},
+		{
+			MethodName: "ResolveIdentitiesBatch",
+			Handler:    _MinersService_ResolveIdentitiesBatch_Handler,
+		},
+		{
+			MethodName: "GetCoinAlgorithm",
+			Handler:    _MinersService_GetCoinAlgorithm_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/miners.proto",
@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.34.1
+// 	protoc-gen-go v1.36.5
 // 	protoc        v3.12.4
 // source: proto/shares.proto
 
@@ -11,6 +11,7 @@ import (
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
 	sync "sync"
+	unsafe "unsafe"
 )
 
 const (
@@ -21,31 +22,28 @@ const (
 )
 
 type Share struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	ServerId      string                 `protobuf:"bytes,2,opt,name=server_id,json=serverId,proto3" json:"server_id,omitempty"`
+	CoinId        int64                  `protobuf:"varint,3,opt,name=coin_id,json=coinId,proto3" json:"coin_id,omitempty"`
+	WorkerId      int64                  `protobuf:"varint,4,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	WalletId      int64                  `protobuf:"varint,5,opt,name=wallet_id,json=walletId,proto3" json:"wallet_id,omitempty"`
+	ShareDate     int64                  `protobuf:"varint,6,opt,name=share_date,json=shareDate,proto3" json:"share_date,omitempty"`
+	Difficulty    string                 `protobuf:"bytes,7,opt,name=difficulty,proto3" json:"difficulty,omitempty"`
+	ShareDif      string                 `protobuf:"bytes,8,opt,name=share_dif,json=shareDif,proto3" json:"share_dif,omitempty"`
+	Nonce         string                 `protobuf:"bytes,9,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	IsSolo        bool                   `protobuf:"varint,10,opt,name=is_solo,json=isSolo,proto3" json:"is_solo,omitempty"`
+	RewardMethod  string                 `protobuf:"bytes,11,opt,name=reward_method,json=rewardMethod,proto3" json:"reward_method,omitempty"`
+	Cost          string                 `protobuf:"bytes,12,opt,name=cost,proto3" json:"cost,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Uuid         string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
-	ServerId     string `protobuf:"bytes,2,opt,name=server_id,json=serverId,proto3" json:"server_id,omitempty"`
-	CoinId       int64  `protobuf:"varint,3,opt,name=coin_id,json=coinId,proto3" json:"coin_id,omitempty"`
-	WorkerId     int64  `protobuf:"varint,4,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
-	WalletId     int64  `protobuf:"varint,5,opt,name=wallet_id,json=walletId,proto3" json:"wallet_id,omitempty"`
-	ShareDate    int64  `protobuf:"varint,6,opt,name=share_date,json=shareDate,proto3" json:"share_date,omitempty"`
-	Difficulty   string `protobuf:"bytes,7,opt,name=difficulty,proto3" json:"difficulty,omitempty"`
-	ShareDif     string `protobuf:"bytes,8,opt,name=share_dif,json=shareDif,proto3" json:"share_dif,omitempty"`
-	Nonce        string `protobuf:"bytes,9,opt,name=nonce,proto3" json:"nonce,omitempty"`
-	IsSolo       bool   `protobuf:"varint,10,opt,name=is_solo,json=isSolo,proto3" json:"is_solo,omitempty"`
-	RewardMethod string `protobuf:"bytes,11,opt,name=reward_method,json=rewardMethod,proto3" json:"reward_method,omitempty"`
-	Cost         string `protobuf:"bytes,12,opt,name=cost,proto3" json:"cost,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Share) Reset() {
 	*x = Share{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_shares_proto_msgTypes[0]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_proto_shares_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *Share) String() string {
@@ -56,7 +54,7 @@ func (*Share) ProtoMessage() {}
 
 func (x *Share) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_shares_proto_msgTypes[0]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -155,21 +153,176 @@ func (x *Share) GetCost() string {
 	return ""
 }
 
-type AddSharesBatchRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+// Шара в исходном виде (как в топике Кафки), нормализуется на стороне сервера
+type ShareFound struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	BlockType     string                 `protobuf:"bytes,2,opt,name=block_type,json=blockType,proto3" json:"block_type,omitempty"`
+	ServerId      string                 `protobuf:"bytes,3,opt,name=server_id,json=serverId,proto3" json:"server_id,omitempty"`
+	CoinSymbol    string                 `protobuf:"bytes,4,opt,name=coin_symbol,json=coinSymbol,proto3" json:"coin_symbol,omitempty"`
+	Workerfull    string                 `protobuf:"bytes,5,opt,name=workerfull,proto3" json:"workerfull,omitempty"`
+	ShareDate     int64                  `protobuf:"varint,6,opt,name=share_date,json=shareDate,proto3" json:"share_date,omitempty"`
+	CHrate        int64                  `protobuf:"varint,7,opt,name=c_hrate,json=cHrate,proto3" json:"c_hrate,omitempty"`
+	AHrate        int64                  `protobuf:"varint,8,opt,name=a_hrate,json=aHrate,proto3" json:"a_hrate,omitempty"`
+	Difficulty    string                 `protobuf:"bytes,9,opt,name=difficulty,proto3" json:"difficulty,omitempty"`
+	Sharedif      string                 `protobuf:"bytes,10,opt,name=sharedif,proto3" json:"sharedif,omitempty"`
+	Nonce         string                 `protobuf:"bytes,11,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	MinerIp       string                 `protobuf:"bytes,12,opt,name=miner_ip,json=minerIp,proto3" json:"miner_ip,omitempty"`
+	IsSolo        bool                   `protobuf:"varint,13,opt,name=is_solo,json=isSolo,proto3" json:"is_solo,omitempty"`
+	RewardMethod  string                 `protobuf:"bytes,14,opt,name=reward_method,json=rewardMethod,proto3" json:"reward_method,omitempty"`
+	Cost          string                 `protobuf:"bytes,15,opt,name=cost,proto3" json:"cost,omitempty"`
 	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShareFound) Reset() {
+	*x = ShareFound{}
+	mi := &file_proto_shares_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
 
-	Shares []*Share `protobuf:"bytes,1,rep,name=shares,proto3" json:"shares,omitempty"`
+func (x *ShareFound) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *AddSharesBatchRequest) Reset() {
-	*x = AddSharesBatchRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_shares_proto_msgTypes[1]
+func (*ShareFound) ProtoMessage() {}
+
+func (x *ShareFound) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shares_proto_msgTypes[1]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShareFound.ProtoReflect.Descriptor instead.
+func (*ShareFound) Descriptor() ([]byte, []int) {
+	return file_proto_shares_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ShareFound) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *ShareFound) GetBlockType() string {
+	if x != nil {
+		return x.BlockType
+	}
+	return ""
+}
+
+func (x *ShareFound) GetServerId() string {
+	if x != nil {
+		return x.ServerId
+	}
+	return ""
+}
+
+func (x *ShareFound) GetCoinSymbol() string {
+	if x != nil {
+		return x.CoinSymbol
+	}
+	return ""
+}
+
+func (x *ShareFound) GetWorkerfull() string {
+	if x != nil {
+		return x.Workerfull
+	}
+	return ""
+}
+
+func (x *ShareFound) GetShareDate() int64 {
+	if x != nil {
+		return x.ShareDate
+	}
+	return 0
+}
+
+func (x *ShareFound) GetCHrate() int64 {
+	if x != nil {
+		return x.CHrate
+	}
+	return 0
+}
+
+func (x *ShareFound) GetAHrate() int64 {
+	if x != nil {
+		return x.AHrate
+	}
+	return 0
+}
+
+func (x *ShareFound) GetDifficulty() string {
+	if x != nil {
+		return x.Difficulty
+	}
+	return ""
+}
+
+func (x *ShareFound) GetSharedif() string {
+	if x != nil {
+		return x.Sharedif
+	}
+	return ""
+}
+
+func (x *ShareFound) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+func (x *ShareFound) GetMinerIp() string {
+	if x != nil {
+		return x.MinerIp
+	}
+	return ""
+}
+
+func (x *ShareFound) GetIsSolo() bool {
+	if x != nil {
+		return x.IsSolo
+	}
+	return false
+}
+
+func (x *ShareFound) GetRewardMethod() string {
+	if x != nil {
+		return x.RewardMethod
+	}
+	return ""
+}
+
+func (x *ShareFound) GetCost() string {
+	if x != nil {
+		return x.Cost
+	}
+	return ""
+}
+
+type AddSharesBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Shares        []*Share               `protobuf:"bytes,1,rep,name=shares,proto3" json:"shares,omitempty"`                              // уже нормализованные шары (проверяются, коды монеты, кошелька и воркера - больше нуля)
+	FoundShares   []*ShareFound          `protobuf:"bytes,2,rep,name=found_shares,json=foundShares,proto3" json:"found_shares,omitempty"` // шары, требующие нормализации
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddSharesBatchRequest) Reset() {
+	*x = AddSharesBatchRequest{}
+	mi := &file_proto_shares_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *AddSharesBatchRequest) String() string {
@@ -179,8 +332,8 @@ func (x *AddSharesBatchRequest) String() string {
 func (*AddSharesBatchRequest) ProtoMessage() {}
 
 func (x *AddSharesBatchRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_shares_proto_msgTypes[1]
-	if protoimpl.UnsafeEnabled && x != nil {
+	mi := &file_proto_shares_proto_msgTypes[2]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -192,7 +345,7 @@ func (x *AddSharesBatchRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddSharesBatchRequest.ProtoReflect.Descriptor instead.
 func (*AddSharesBatchRequest) Descriptor() ([]byte, []int) {
-	return file_proto_shares_proto_rawDescGZIP(), []int{1}
+	return file_proto_shares_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *AddSharesBatchRequest) GetShares() []*Share {
@@ -202,21 +355,87 @@ func (x *AddSharesBatchRequest) GetShares() []*Share {
 	return nil
 }
 
-type AddSharesBatchResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+func (x *AddSharesBatchRequest) GetFoundShares() []*ShareFound {
+	if x != nil {
+		return x.FoundShares
+	}
+	return nil
+}
+
+// Причина отклонения шары
+type ShareRejection struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Duplicate     bool                   `protobuf:"varint,3,opt,name=duplicate,proto3" json:"duplicate,omitempty"` // шара с таким UUID уже сохранена (повторная отправка), не ошибка
 	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-	AddedCount int64 `protobuf:"varint,1,opt,name=added_count,json=addedCount,proto3" json:"added_count,omitempty"`
+func (x *ShareRejection) Reset() {
+	*x = ShareRejection{}
+	mi := &file_proto_shares_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *AddSharesBatchResponse) Reset() {
-	*x = AddSharesBatchResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_shares_proto_msgTypes[2]
+func (x *ShareRejection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShareRejection) ProtoMessage() {}
+
+func (x *ShareRejection) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shares_proto_msgTypes[3]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShareRejection.ProtoReflect.Descriptor instead.
+func (*ShareRejection) Descriptor() ([]byte, []int) {
+	return file_proto_shares_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ShareRejection) GetUuid() string {
+	if x != nil {
+		return x.Uuid
 	}
+	return ""
+}
+
+func (x *ShareRejection) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ShareRejection) GetDuplicate() bool {
+	if x != nil {
+		return x.Duplicate
+	}
+	return false
+}
+
+type AddSharesBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AddedCount    int64                  `protobuf:"varint,1,opt,name=added_count,json=addedCount,proto3" json:"added_count,omitempty"` // сохранено шар (без отклоненных, в том числе дубликатов)
+	Rejected      []*ShareRejection      `protobuf:"bytes,2,rep,name=rejected,proto3" json:"rejected,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddSharesBatchResponse) Reset() {
+	*x = AddSharesBatchResponse{}
+	mi := &file_proto_shares_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *AddSharesBatchResponse) String() string {
@@ -226,8 +445,8 @@ func (x *AddSharesBatchResponse) String() string {
 func (*AddSharesBatchResponse) ProtoMessage() {}
 
 func (x *AddSharesBatchResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_shares_proto_msgTypes[2]
-	if protoimpl.UnsafeEnabled && x != nil {
+	mi := &file_proto_shares_proto_msgTypes[4]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -239,7 +458,7 @@ func (x *AddSharesBatchResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddSharesBatchResponse.ProtoReflect.Descriptor instead.
 func (*AddSharesBatchResponse) Descriptor() ([]byte, []int) {
-	return file_proto_shares_proto_rawDescGZIP(), []int{2}
+	return file_proto_shares_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *AddSharesBatchResponse) GetAddedCount() int64 {
@@ -249,9 +468,16 @@ func (x *AddSharesBatchResponse) GetAddedCount() int64 {
 	return 0
 }
 
+func (x *AddSharesBatchResponse) GetRejected() []*ShareRejection {
+	if x != nil {
+		return x.Rejected
+	}
+	return nil
+}
+
 var File_proto_shares_proto protoreflect.FileDescriptor
 
-var file_proto_shares_proto_rawDesc = []byte{
+var file_proto_shares_proto_rawDesc = string([]byte{
 	0x0a, 0x12, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x68, 0x61, 0x72, 0x65, 0x73, 0x2e, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x67, 0x72, 0x70, 0x63, 0x22, 0xcf, 0x02, 0x0a, 0x05, 0x53,
 	0x68, 0x61, 0x72, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
@@ -274,52 +500,95 @@ var file_proto_shares_proto_rawDesc = []byte{
 	0x6f, 0x6c, 0x6f, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x5f, 0x6d, 0x65,
 	0x74, 0x68, 0x6f, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x65, 0x77, 0x61,
 	0x72, 0x64, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x73, 0x74,
-	0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x73, 0x74, 0x22, 0x3c, 0x0a, 0x15,
+	0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x73, 0x74, 0x22, 0xad, 0x03, 0x0a,
+	0x0a, 0x53, 0x68, 0x61, 0x72, 0x65, 0x46, 0x6f, 0x75, 0x6e, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x75,
+	0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12,
+	0x1d, 0x0a, 0x0a, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1b,
+	0x0a, 0x09, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x63,
+	0x6f, 0x69, 0x6e, 0x5f, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x63, 0x6f, 0x69, 0x6e, 0x53, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x12, 0x1e, 0x0a, 0x0a,
+	0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x66, 0x75, 0x6c, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x66, 0x75, 0x6c, 0x6c, 0x12, 0x1d, 0x0a, 0x0a,
+	0x73, 0x68, 0x61, 0x72, 0x65, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x09, 0x73, 0x68, 0x61, 0x72, 0x65, 0x44, 0x61, 0x74, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x63,
+	0x5f, 0x68, 0x72, 0x61, 0x74, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x63, 0x48,
+	0x72, 0x61, 0x74, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x61, 0x5f, 0x68, 0x72, 0x61, 0x74, 0x65, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x61, 0x48, 0x72, 0x61, 0x74, 0x65, 0x12, 0x1e, 0x0a,
+	0x0a, 0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x12, 0x1a, 0x0a,
+	0x08, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x69, 0x66, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x69, 0x66, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e,
+	0x63, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12,
+	0x19, 0x0a, 0x08, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x5f, 0x69, 0x70, 0x18, 0x0c, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x49, 0x70, 0x12, 0x17, 0x0a, 0x07, 0x69, 0x73,
+	0x5f, 0x73, 0x6f, 0x6c, 0x6f, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x69, 0x73, 0x53,
+	0x6f, 0x6c, 0x6f, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x5f, 0x6d, 0x65,
+	0x74, 0x68, 0x6f, 0x64, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x65, 0x77, 0x61,
+	0x72, 0x64, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x73, 0x74,
+	0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x73, 0x74, 0x22, 0x71, 0x0a, 0x15,
 	0x41, 0x64, 0x64, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65,
 	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x06, 0x73, 0x68, 0x61, 0x72, 0x65, 0x73, 0x18,
 	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x68, 0x61,
-	0x72, 0x65, 0x52, 0x06, 0x73, 0x68, 0x61, 0x72, 0x65, 0x73, 0x22, 0x39, 0x0a, 0x16, 0x41, 0x64,
-	0x64, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x64, 0x64, 0x65, 0x64, 0x5f, 0x63, 0x6f,
-	0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x61, 0x64, 0x64, 0x65, 0x64,
-	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x32, 0x5c, 0x0a, 0x0d, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x53,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4b, 0x0a, 0x0e, 0x41, 0x64, 0x64, 0x53, 0x68, 0x61,
-	0x72, 0x65, 0x73, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x1b, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x72, 0x65, 0x52, 0x06, 0x73, 0x68, 0x61, 0x72, 0x65, 0x73, 0x12, 0x33, 0x0a, 0x0c, 0x66, 0x6f,
+	0x75, 0x6e, 0x64, 0x5f, 0x73, 0x68, 0x61, 0x72, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x10, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x68, 0x61, 0x72, 0x65, 0x46, 0x6f, 0x75,
+	0x6e, 0x64, 0x52, 0x0b, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x22,
+	0x5a, 0x0a, 0x0e, 0x53, 0x68, 0x61, 0x72, 0x65, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x1c, 0x0a,
+	0x09, 0x64, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x09, 0x64, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x22, 0x6b, 0x0a, 0x16, 0x41,
+	0x64, 0x64, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x64, 0x64, 0x65, 0x64, 0x5f, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x61, 0x64, 0x64, 0x65,
+	0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x30, 0x0a, 0x08, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74,
+	0x65, 0x64, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x53, 0x68, 0x61, 0x72, 0x65, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08,
+	0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x32, 0x5c, 0x0a, 0x0d, 0x53, 0x68, 0x61, 0x72,
+	0x65, 0x73, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4b, 0x0a, 0x0e, 0x41, 0x64, 0x64,
+	0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x1b, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x41, 0x64, 0x64, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
 	0x41, 0x64, 0x64, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x64, 0x64,
-	0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x42, 0x1d, 0x5a, 0x1b, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f,
-	0x61, 0x64, 0x61, 0x70, 0x74, 0x65, 0x72, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
-}
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x1d, 0x5a, 0x1b, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e,
+	0x61, 0x6c, 0x2f, 0x61, 0x64, 0x61, 0x70, 0x74, 0x65, 0x72, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+})
 
 var (
 	file_proto_shares_proto_rawDescOnce sync.Once
-	file_proto_shares_proto_rawDescData = file_proto_shares_proto_rawDesc
+	file_proto_shares_proto_rawDescData []byte
 )
 
 func file_proto_shares_proto_rawDescGZIP() []byte {
 	file_proto_shares_proto_rawDescOnce.Do(func() {
-		file_proto_shares_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_shares_proto_rawDescData)
+		file_proto_shares_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_shares_proto_rawDesc), len(file_proto_shares_proto_rawDesc)))
 	})
 	return file_proto_shares_proto_rawDescData
 }
 
-var file_proto_shares_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
-var file_proto_shares_proto_goTypes = []interface{}{
+var file_proto_shares_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_proto_shares_proto_goTypes = []any{
 	(*Share)(nil),                  // 0: grpc.Share
-	(*AddSharesBatchRequest)(nil),  // 1: grpc.AddSharesBatchRequest
-	(*AddSharesBatchResponse)(nil), // 2: grpc.AddSharesBatchResponse
+	(*ShareFound)(nil),             // 1: grpc.ShareFound
+	(*AddSharesBatchRequest)(nil),  // 2: grpc.AddSharesBatchRequest
+	(*ShareRejection)(nil),         // 3: grpc.ShareRejection
+	(*AddSharesBatchResponse)(nil), // 4: grpc.AddSharesBatchResponse
 }
 var file_proto_shares_proto_depIdxs = []int32{
 	0, // 0: grpc.AddSharesBatchRequest.shares:type_name -> grpc.Share
-	1, // 1: grpc.SharesService.AddSharesBatch:input_type -> grpc.AddSharesBatchRequest
-	2, // 2: grpc.SharesService.AddSharesBatch:output_type -> grpc.AddSharesBatchResponse
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	1, // 1: grpc.AddSharesBatchRequest.found_shares:type_name -> grpc.ShareFound
+	3, // 2: grpc.AddSharesBatchResponse.rejected:type_name -> grpc.ShareRejection
+	2, // 3: grpc.SharesService.AddSharesBatch:input_type -> grpc.AddSharesBatchRequest
+	4, // 4: grpc.SharesService.AddSharesBatch:output_type -> grpc.AddSharesBatchResponse
+	4, // [4:5] is the sub-list for method output_type
+	3, // [3:4] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_proto_shares_proto_init() }
@@ -327,51 +596,13 @@ func file_proto_shares_proto_init() {
 	if File_proto_shares_proto != nil {
 		return
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_proto_shares_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Share); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_proto_shares_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddSharesBatchRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_proto_shares_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddSharesBatchResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_proto_shares_proto_rawDesc,
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_shares_proto_rawDesc), len(file_proto_shares_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -380,7 +611,6 @@ func file_proto_shares_proto_init() {
 		MessageInfos:      file_proto_shares_proto_msgTypes,
 	}.Build()
 	File_proto_shares_proto = out.File
-	file_proto_shares_proto_rawDesc = nil
 	file_proto_shares_proto_goTypes = nil
 	file_proto_shares_proto_depIdxs = nil
 }
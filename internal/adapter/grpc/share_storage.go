@@ -2,6 +2,7 @@ package grpc
 
 import (
 	"context"
+	"fmt"
 
 	"google.golang.org/grpc"
 
@@ -23,6 +24,9 @@ func NewShareStorage(conn *grpc.ClientConn) (*GRPCShareStorage, error) {
 	}, nil
 }
 
+// AddSharesBatch сохранение пакета шар сервисом шар
+// если сервис отклонил часть шар, остальные уже сохранены: возвращается *entity.PartialSaveError с отклоненными шарами
+// (дубликаты - с entity.ErrDuplicateShare)
 func (s *GRPCShareStorage) AddSharesBatch(ctx context.Context, shares []entity.Share) error {
 
 	batch := make([]*proto.Share, 0, len(shares))
@@ -48,9 +52,19 @@ func (s *GRPCShareStorage) AddSharesBatch(ctx context.Context, shares []entity.S
 
 	resp, err := s.client.AddSharesBatch(ctx, &proto.AddSharesBatchRequest{Shares: batch})
 	if err != nil {
-		return err
+		return clientError(err)
+	}
+	if len(resp.Rejected) > 0 {
+		partial := &entity.PartialSaveError{Rejected: make([]*entity.ShareError, 0, len(resp.Rejected))}
+		for _, r := range resp.Rejected {
+			reason := entity.ErrInvalidShare
+			if r.Duplicate {
+				reason = entity.ErrDuplicateShare
+			}
+			partial.Rejected = append(partial.Rejected, &entity.ShareError{UUID: r.Uuid, Err: fmt.Errorf("%w: %s", reason, r.Reason)})
+		}
+		return partial
 	}
-	_ = resp
 
 	return nil
 }
@@ -0,0 +1,140 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/adapter/grpc/proto"
+	"github.com/dnsoftware/mpm-shares-processor/internal/dto"
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+)
+
+// ShareProcessor нормализация и сохранение шар (тот же конвейер, что и у обработчика Кафки)
+type ShareProcessor interface {
+	ValidateShare(shareFound dto.ShareFound) error
+	ValidateNormalized(share entity.Share) error
+	ResolveIdentities(ctx context.Context, shares []dto.ShareFound) error
+	NormalizeShare(ctxTask context.Context, shareFound dto.ShareFound) (entity.Share, error)
+	AddSharesBatch(ctx context.Context, shares []entity.Share) error
+}
+
+// GRPCSharesServer прием шар по gRPC (для пул-серверов без доступа к Кафке)
+type GRPCSharesServer struct {
+	proto.UnimplementedSharesServiceServer
+	processor ShareProcessor
+}
+
+func NewSharesServer(processor ShareProcessor) (*GRPCSharesServer, error) {
+	s := &GRPCSharesServer{
+		processor: processor,
+	}
+
+	return s, nil
+}
+
+// AddSharesBatch сохранение пакета шар
+// шары проходят тот же конвейер, что и у обработчика Кафки: проверка, нормализация, отсев дубликатов при сохранении
+// шары, не прошедшие проверку или нормализацию, отклоняются с указанием причины, остальные сохраняются одним пакетом
+// дубликаты ранее сохраненных шар тоже возвращаются в Rejected (с признаком Duplicate) и не входят в AddedCount
+// уже нормализованные шары (req.Shares) проверяются так же, как и найденные, коды монеты, кошелька и воркера - на больше нуля
+// временные ошибки нормализации и сохранения - ошибка всего запроса (codes.Unavailable), клиент повторяет запрос
+func (s *GRPCSharesServer) AddSharesBatch(ctx context.Context, req *proto.AddSharesBatchRequest) (*proto.AddSharesBatchResponse, error) {
+	resp := &proto.AddSharesBatchResponse{}
+	batch := make([]entity.Share, 0, len(req.Shares)+len(req.FoundShares))
+
+	// уже нормализованные шары
+	for _, sh := range req.Shares {
+		share := shareFromProto(sh)
+		if err := s.processor.ValidateNormalized(share); err != nil {
+			resp.Rejected = append(resp.Rejected, &proto.ShareRejection{Uuid: sh.Uuid, Reason: "validate: " + err.Error()})
+			continue
+		}
+		batch = append(batch, share)
+	}
+
+	// шары, требующие нормализации (кошельки и воркеры сначала разрешаем пакетом)
+	found := make([]dto.ShareFound, 0, len(req.FoundShares))
+	for _, sf := range req.FoundShares {
+		shareFound := shareFoundFromProto(sf)
+		if err := s.processor.ValidateShare(shareFound); err != nil {
+			resp.Rejected = append(resp.Rejected, &proto.ShareRejection{Uuid: sf.Uuid, Reason: "validate: " + err.Error()})
+			continue
+		}
+		found = append(found, shareFound)
+	}
+	if err := s.processor.ResolveIdentities(ctx, found); err != nil {
+		logger.Log().Warn("ResolveIdentities error: " + err.Error())
+	}
+
+	for _, shareFound := range found {
+		share, err := s.processor.NormalizeShare(ctx, shareFound)
+		if err != nil {
+			if entity.IsTransient(err) {
+				return nil, statusError("NormalizeShare", err)
+			}
+			resp.Rejected = append(resp.Rejected, &proto.ShareRejection{Uuid: shareFound.Uuid, Reason: "normalize: " + err.Error()})
+			continue
+		}
+		batch = append(batch, share)
+	}
+
+	added := len(batch)
+	if len(batch) > 0 {
+		err := s.processor.AddSharesBatch(ctx, batch)
+		var partial *entity.PartialSaveError
+		switch {
+		case errors.As(err, &partial):
+			for _, r := range partial.Rejected {
+				resp.Rejected = append(resp.Rejected, &proto.ShareRejection{
+					Uuid:      r.UUID,
+					Reason:    "save: " + r.Err.Error(),
+					Duplicate: errors.Is(r.Err, entity.ErrDuplicateShare),
+				})
+			}
+			added -= len(partial.Rejected)
+		case err != nil:
+			return nil, statusError("AddSharesBatch", err)
+		}
+	}
+	resp.AddedCount = int64(added)
+
+	return resp, nil
+}
+
+func shareFromProto(sh *proto.Share) entity.Share {
+	return entity.Share{
+		UUID:         sh.Uuid,
+		ServerID:     sh.ServerId,
+		CoinID:       sh.CoinId,
+		WorkerID:     sh.WorkerId,
+		WalletID:     sh.WalletId,
+		ShareDate:    sh.ShareDate,
+		Difficulty:   sh.Difficulty,
+		Sharedif:     sh.ShareDif,
+		Nonce:        sh.Nonce,
+		IsSolo:       sh.IsSolo,
+		RewardMethod: sh.RewardMethod,
+		Cost:         sh.Cost,
+	}
+}
+
+func shareFoundFromProto(sf *proto.ShareFound) dto.ShareFound {
+	return dto.ShareFound{
+		Uuid:         sf.Uuid,
+		BlockType:    sf.BlockType,
+		ServerID:     sf.ServerId,
+		CoinSymbol:   sf.CoinSymbol,
+		Workerfull:   sf.Workerfull,
+		ShareDate:    sf.ShareDate,
+		CHrate:       sf.CHrate,
+		AHrate:       sf.AHrate,
+		Difficulty:   sf.Difficulty,
+		Sharedif:     sf.Sharedif,
+		Nonce:        sf.Nonce,
+		MinerIp:      sf.MinerIp,
+		IsSolo:       sf.IsSolo,
+		RewardMethod: sf.RewardMethod,
+		Cost:         sf.Cost,
+	}
+}
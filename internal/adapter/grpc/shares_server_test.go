@@ -0,0 +1,191 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/adapter/grpc/proto"
+	"github.com/dnsoftware/mpm-shares-processor/internal/dto"
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+)
+
+type testProcessor struct {
+	added        []entity.Share
+	saveErr      error
+	normalizeErr error  // ошибка нормализации всех шар
+	rejectUUID   string // шара, отклоняемая при сохранении (остальные сохраняются)
+	duplicate    string // шара, уже сохраненная ранее
+}
+
+func (p *testProcessor) ValidateShare(shareFound dto.ShareFound) error {
+	if shareFound.Uuid == "" {
+		return fmt.Errorf("%w: uuid is empty", entity.ErrInvalidShare)
+	}
+	return nil
+}
+
+func (p *testProcessor) ValidateNormalized(share entity.Share) error {
+	if share.CoinID <= 0 || share.WalletID <= 0 || share.WorkerID <= 0 {
+		return fmt.Errorf("%w: coin_id, wallet_id and worker_id must be greater than 0", entity.ErrInvalidShare)
+	}
+	return nil
+}
+
+func (p *testProcessor) ResolveIdentities(ctx context.Context, shares []dto.ShareFound) error {
+	return nil
+}
+
+func (p *testProcessor) NormalizeShare(ctx context.Context, shareFound dto.ShareFound) (entity.Share, error) {
+	if p.normalizeErr != nil {
+		return entity.Share{}, p.normalizeErr
+	}
+	if shareFound.CoinSymbol != "ALPH" {
+		return entity.Share{}, fmt.Errorf("%w %s", entity.ErrUnknownCoin, shareFound.CoinSymbol)
+	}
+	share := shareFound.ToShare()
+	share.CoinID, share.WalletID, share.WorkerID = 4, 1, 1
+
+	return share, nil
+}
+
+func (p *testProcessor) AddSharesBatch(ctx context.Context, shares []entity.Share) error {
+	if p.saveErr != nil {
+		return p.saveErr
+	}
+	var partial entity.PartialSaveError
+	for _, sh := range shares {
+		if sh.UUID == p.rejectUUID {
+			partial.Rejected = append(partial.Rejected, &entity.ShareError{UUID: sh.UUID, Err: entity.ErrInvalidDecimal})
+			continue
+		}
+		if sh.UUID == p.duplicate {
+			partial.Rejected = append(partial.Rejected, &entity.ShareError{UUID: sh.UUID, Err: entity.ErrDuplicateShare})
+			continue
+		}
+		p.added = append(p.added, sh)
+	}
+	if len(partial.Rejected) > 0 {
+		return &partial
+	}
+
+	return nil
+}
+
+func TestSharesServerAddSharesBatch(t *testing.T) {
+	processor := &testProcessor{}
+	server, err := NewSharesServer(processor)
+	require.NoError(t, err)
+
+	req := &proto.AddSharesBatchRequest{
+		Shares: []*proto.Share{
+			{Uuid: "n1", CoinId: 4, WalletId: 1, WorkerId: 1},
+			{Uuid: "n2", CoinId: 4}, // нет кошелька и воркера
+		},
+		FoundShares: []*proto.ShareFound{
+			{Uuid: "f1", CoinSymbol: "ALPH", Workerfull: "wallet.worker"},
+			{Uuid: "f2", CoinSymbol: "UNKNOWN", Workerfull: "wallet.worker"},
+			{CoinSymbol: "ALPH", Workerfull: "wallet.worker"}, // не проходит проверку
+		},
+	}
+
+	resp, err := server.AddSharesBatch(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), resp.AddedCount)
+	require.Len(t, processor.added, 2)
+	require.Equal(t, "n1", processor.added[0].UUID)
+	require.Equal(t, "f1", processor.added[1].UUID)
+
+	require.Len(t, resp.Rejected, 3)
+	require.Equal(t, "n2", resp.Rejected[0].Uuid)
+	require.Contains(t, resp.Rejected[0].Reason, "validate: invalid share")
+	require.Contains(t, resp.Rejected[1].Reason, "validate: invalid share")
+	require.Equal(t, "f2", resp.Rejected[2].Uuid)
+	require.Contains(t, resp.Rejected[2].Reason, "unknown coin")
+
+	// недоступность сервиса монет и майнеров - ошибка всего запроса, шары не отклоняются
+	processor.normalizeErr = fmt.Errorf("miners: %w", entity.ErrServiceUnavailable)
+	_, err = server.AddSharesBatch(context.Background(), req)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	processor.normalizeErr = nil
+
+	// часть шар отклонена при сохранении - остальные сохранены
+	processor.added = nil
+	// дубликаты ранее сохраненных шар в AddedCount не входят
+	processor.rejectUUID = "f1"
+	processor.duplicate = "f4"
+	resp, err = server.AddSharesBatch(context.Background(), &proto.AddSharesBatchRequest{FoundShares: []*proto.ShareFound{
+		{Uuid: "f1", CoinSymbol: "ALPH", Workerfull: "wallet.worker"},
+		{Uuid: "f3", CoinSymbol: "ALPH", Workerfull: "wallet.worker"},
+		{Uuid: "f4", CoinSymbol: "ALPH", Workerfull: "wallet.worker"},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), resp.AddedCount)
+	require.Len(t, resp.Rejected, 2)
+	require.Equal(t, "f1", resp.Rejected[0].Uuid)
+	require.Contains(t, resp.Rejected[0].Reason, "save: ")
+	require.False(t, resp.Rejected[0].Duplicate)
+	require.Equal(t, "f4", resp.Rejected[1].Uuid)
+	require.True(t, resp.Rejected[1].Duplicate)
+	processor.rejectUUID, processor.duplicate = "", ""
+
+	// ошибка сохранения пакета - ошибка всего запроса
+	processor.saveErr = errors.New("clickhouse unavailable")
+	_, err = server.AddSharesBatch(context.Background(), req)
+	require.Equal(t, codes.Internal, status.Code(err))
+
+	// недоступность хранилища - временная ошибка, клиент может повторить запрос
+	processor.saveErr = fmt.Errorf("clickhouse: %w", entity.ErrStorageUnavailable)
+	_, err = server.AddSharesBatch(context.Background(), req)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestClientError(t *testing.T) {
+	err := clientError(status.Error(codes.Unavailable, "connection refused"))
+	require.ErrorIs(t, err, entity.ErrServiceUnavailable)
+	require.True(t, entity.IsTransient(err))
+
+	err = clientError(status.Error(codes.NotFound, "wallet not found"))
+	require.Equal(t, entity.ErrorClassUnknown, entity.ErrorClass(err))
+}
+
+// testSharesClient сервис шар, отклоняющий шары из rejected и отбрасывающий дубликаты из duplicates
+type testSharesClient struct {
+	rejected   map[string]string
+	duplicates map[string]bool
+}
+
+func (c testSharesClient) AddSharesBatch(ctx context.Context, in *proto.AddSharesBatchRequest, opts ...grpc.CallOption) (*proto.AddSharesBatchResponse, error) {
+	resp := &proto.AddSharesBatchResponse{}
+	for _, sh := range in.Shares {
+		if reason, ok := c.rejected[sh.Uuid]; ok {
+			resp.Rejected = append(resp.Rejected, &proto.ShareRejection{Uuid: sh.Uuid, Reason: reason, Duplicate: c.duplicates[sh.Uuid]})
+			continue
+		}
+		resp.AddedCount++
+	}
+	return resp, nil
+}
+
+func TestShareStoragePartialSave(t *testing.T) {
+	storage := &GRPCShareStorage{client: testSharesClient{
+		rejected:   map[string]string{"2": "validate: invalid share", "3": "save: duplicate share"},
+		duplicates: map[string]bool{"3": true},
+	}}
+
+	err := storage.AddSharesBatch(context.Background(), []entity.Share{{UUID: "1"}, {UUID: "2"}, {UUID: "3"}})
+	var partial *entity.PartialSaveError
+	require.ErrorAs(t, err, &partial)
+	require.Equal(t, []string{"2", "3"}, partial.RejectedUUIDs())
+	require.ErrorIs(t, partial.Rejected[0], entity.ErrInvalidShare)
+	require.ErrorIs(t, partial.Rejected[1], entity.ErrDuplicateShare)
+	require.True(t, entity.IsPermanent(err))
+
+	require.NoError(t, storage.AddSharesBatch(context.Background(), []entity.Share{{UUID: "1"}}))
+}
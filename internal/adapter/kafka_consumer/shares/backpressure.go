@@ -0,0 +1,192 @@
+package shares
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+)
+
+// Паузы между повторами сохранения пакета по умолчанию
+const (
+	DefaultRetryBackoffMin = time.Second
+	DefaultRetryBackoffMax = time.Minute
+)
+
+// DefaultUnknownRetries попыток сохранения пакета при неклассифицированной ошибке по умолчанию
+const DefaultUnknownRetries = 5
+
+// PartitionPauser приостановка чтения партиций (реализуется kafka_reader.KafkaReader)
+type PartitionPauser interface {
+	Pause(partitions map[string][]int32)
+	Resume(partitions map[string][]int32)
+}
+
+// PausedPartition партиция, чтение которой приостановлено до успешного сохранения пакета
+type PausedPartition struct {
+	Topic     string    `json:"topic"`
+	Partition int32     `json:"partition"`
+	Since     time.Time `json:"since"`
+	Retries   uint64    `json:"retries"`    // повторов сохранения текущего пакета
+	LastError string    `json:"last_error"` // последняя ошибка сохранения
+}
+
+// BackpressureState состояние приостановки чтения из-за ошибок хранилища
+type BackpressureState struct {
+	Paused  []PausedPartition `json:"paused"`
+	Retries uint64            `json:"retries"` // всего повторов сохранения с момента запуска
+}
+
+type topicPartition struct {
+	topic     string
+	partition int32
+}
+
+// backpressure учет приостановленных партиций
+type backpressure struct {
+	mu      sync.Mutex
+	paused  map[topicPartition]*PausedPartition
+	retries uint64
+}
+
+// withBackpressure выполнение операции пакета (нормализация, сохранение) с повторами до успеха
+// на время повторов чтение партиции приостанавливается (без выхода из ConsumeClaim и ребалансировки группы),
+// паузы между повторами растут экспоненциально со случайным разбросом
+// постоянные ошибки не повторяются (неклассифицированные ошибки сохранения savePending после UnknownRetries попыток
+// считает постоянными); иначе ошибка возвращается только при завершении сессии, пакет тогда будет перечитан
+func (consumer *ShareConsumer) withBackpressure(session sarama.ConsumerGroupSession, topic string, partition int32, op func() error) error {
+	err := op()
+	if err == nil || entity.IsPermanent(err) {
+		return err
+	}
+	consumer.countError(err)
+
+	tp := topicPartition{topic: topic, partition: partition}
+	partitions := map[string][]int32{topic: {partition}}
+	if consumer.pauser != nil {
+		consumer.pauser.Pause(partitions)
+	}
+	consumer.setPaused(tp, err)
+	logger.Log().Warn(fmt.Sprintf("Partition %s/%d paused, %s error: %s", topic, partition, entity.ErrorClass(err), err.Error()))
+
+	defer func() {
+		consumer.clearPaused(tp)
+		if consumer.pauser != nil {
+			consumer.pauser.Resume(partitions)
+		}
+	}()
+
+	for attempt := 0; ; attempt++ {
+		select {
+		case <-session.Context().Done():
+			return session.Context().Err()
+		case <-time.After(consumer.retryBackoff(attempt)):
+		}
+
+		err = op()
+		consumer.addRetry(tp, err)
+		if err == nil {
+			logger.Log().Info(fmt.Sprintf("Partition %s/%d resumed after %d retries", topic, partition, attempt+1))
+			return nil
+		}
+		if entity.IsPermanent(err) {
+			return err
+		}
+		consumer.countError(err)
+		logger.Log().Warn(fmt.Sprintf("Partition %s/%d retry %d, %s error: %s", topic, partition, attempt+1, entity.ErrorClass(err), err.Error()))
+	}
+}
+
+// retryOnce выполнение операции без повторов
+func retryOnce(op func() error) error {
+	return op()
+}
+
+// unknownRetries попыток сохранения пакета при неклассифицированной ошибке
+func (consumer *ShareConsumer) unknownRetries() int {
+	if consumer.cfg.UnknownRetries <= 0 {
+		return DefaultUnknownRetries
+	}
+	return consumer.cfg.UnknownRetries
+}
+
+// retryBackoff пауза перед повтором attempt: RetryBackoffMin * 2^attempt, не более RetryBackoffMax, со случайным разбросом [d/2, d]
+func (consumer *ShareConsumer) retryBackoff(attempt int) time.Duration {
+	minBackoff, maxBackoff := consumer.cfg.RetryBackoffMin, consumer.cfg.RetryBackoffMax
+	if minBackoff <= 0 {
+		minBackoff = DefaultRetryBackoffMin
+	}
+	if maxBackoff < minBackoff {
+		maxBackoff = max(DefaultRetryBackoffMax, minBackoff)
+	}
+
+	d := maxBackoff
+	if attempt < 32 && minBackoff<<attempt < maxBackoff {
+		d = minBackoff << attempt
+	}
+
+	return d/2 + rand.N(d/2+1)
+}
+
+func (consumer *ShareConsumer) setPaused(tp topicPartition, err error) {
+	consumer.backpressure.mu.Lock()
+	defer consumer.backpressure.mu.Unlock()
+
+	if consumer.backpressure.paused == nil {
+		consumer.backpressure.paused = make(map[topicPartition]*PausedPartition)
+	}
+	consumer.backpressure.paused[tp] = &PausedPartition{
+		Topic:     tp.topic,
+		Partition: tp.partition,
+		Since:     time.Now(),
+		LastError: err.Error(),
+	}
+}
+
+func (consumer *ShareConsumer) addRetry(tp topicPartition, err error) {
+	consumer.backpressure.mu.Lock()
+	defer consumer.backpressure.mu.Unlock()
+
+	consumer.backpressure.retries++
+	if p, ok := consumer.backpressure.paused[tp]; ok {
+		p.Retries++
+		if err != nil {
+			p.LastError = err.Error()
+		}
+	}
+}
+
+func (consumer *ShareConsumer) clearPaused(tp topicPartition) {
+	consumer.backpressure.mu.Lock()
+	defer consumer.backpressure.mu.Unlock()
+
+	delete(consumer.backpressure.paused, tp)
+}
+
+// Backpressure приостановленные партиции и кол-во повторов сохранения
+func (consumer *ShareConsumer) Backpressure() BackpressureState {
+	consumer.backpressure.mu.Lock()
+	defer consumer.backpressure.mu.Unlock()
+
+	state := BackpressureState{
+		Paused:  make([]PausedPartition, 0, len(consumer.backpressure.paused)),
+		Retries: consumer.backpressure.retries,
+	}
+	for _, p := range consumer.backpressure.paused {
+		state.Paused = append(state.Paused, *p)
+	}
+	sort.Slice(state.Paused, func(i, j int) bool {
+		if state.Paused[i].Topic != state.Paused[j].Topic {
+			return state.Paused[i].Topic < state.Paused[j].Topic
+		}
+		return state.Paused[i].Partition < state.Paused[j].Partition
+	})
+
+	return state
+}
@@ -0,0 +1,136 @@
+package shares
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/dto"
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+)
+
+type testPauser struct {
+	calls []string
+}
+
+func (p *testPauser) Pause(partitions map[string][]int32) {
+	p.calls = append(p.calls, fmt.Sprintf("pause %v", partitions))
+}
+
+func (p *testPauser) Resume(partitions map[string][]int32) {
+	p.calls = append(p.calls, fmt.Sprintf("resume %v", partitions))
+}
+
+// failingProcessor хранилище, недоступное первые failures вызовов
+type failingProcessor struct {
+	testProcessor
+	failures int
+	onFail   func()
+}
+
+func (p *failingProcessor) AddSharesBatch(ctx context.Context, shares []entity.Share) error {
+	if p.failures > 0 {
+		p.failures--
+		if p.onFail != nil {
+			p.onFail()
+		}
+		return fmt.Errorf("clickhouse: %w", entity.ErrStorageUnavailable)
+	}
+	return p.testProcessor.AddSharesBatch(ctx, shares)
+}
+
+func TestWithBackpressure(t *testing.T) {
+	logger.InitLogger(logger.LogLevelProduction, t.TempDir()+"/test.log")
+
+	processor := &failingProcessor{failures: 3}
+	consumer, err := NewShareConsumer(Config{RetryBackoffMin: time.Millisecond, RetryBackoffMax: 4 * time.Millisecond}, nil, processor, nil, nil)
+	require.NoError(t, err)
+	pauser := &testPauser{}
+	consumer.pauser = pauser
+
+	var states []BackpressureState
+	processor.onFail = func() { states = append(states, consumer.Backpressure()) }
+
+	session := &testSession{}
+	err = consumer.withBackpressure(session, "shares", 1, func() error { return consumer.AddSharesBatch(context.Background(), []entity.Share{{UUID: "1"}}) })
+	require.NoError(t, err)
+	require.Len(t, processor.saved, 1)
+
+	// на время повторов партиция приостановлена
+	require.Equal(t, []string{"pause map[shares:[1]]", "resume map[shares:[1]]"}, pauser.calls)
+	require.Empty(t, states[0].Paused) // первая ошибка - до приостановки
+	require.Len(t, states[2].Paused, 1)
+	require.Equal(t, int32(1), states[2].Paused[0].Partition)
+	require.Equal(t, uint64(1), states[2].Paused[0].Retries)
+
+	state := consumer.Backpressure()
+	require.Empty(t, state.Paused)
+	require.Equal(t, uint64(3), state.Retries) // две неудачные попытки и одна успешная
+
+	// завершение сессии прерывает повторы, партиция возобновляется
+	processor.failures = 100
+	ctx, cancel := context.WithCancel(context.Background())
+	processor.onFail = cancel
+	err = consumer.withBackpressure(&testSession{ctx: ctx}, "shares", 2, func() error { return consumer.AddSharesBatch(context.Background(), []entity.Share{{UUID: "2"}}) })
+	require.ErrorIs(t, err, context.Canceled)
+	require.Empty(t, consumer.Backpressure().Paused)
+	require.Equal(t, "resume map[shares:[2]]", pauser.calls[len(pauser.calls)-1])
+
+	// постоянная ошибка не повторяется и партиция не приостанавливается
+	calls := len(pauser.calls)
+	attempts := 0
+	err = consumer.withBackpressure(session, "shares", 3, func() error {
+		attempts++
+		return entity.ErrInvalidShare
+	})
+	require.ErrorIs(t, err, entity.ErrInvalidShare)
+	require.Equal(t, 1, attempts)
+	require.Len(t, pauser.calls, calls)
+}
+
+func TestRetryBackoff(t *testing.T) {
+	consumer := &ShareConsumer{cfg: Config{RetryBackoffMin: 100 * time.Millisecond, RetryBackoffMax: time.Second}}
+
+	for attempt, want := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		d := consumer.retryBackoff(attempt)
+		require.GreaterOrEqual(t, d, want/2)
+		require.LessOrEqual(t, d, want)
+	}
+	require.LessOrEqual(t, consumer.retryBackoff(1000), time.Second)
+}
+
+// unknownErrorProcessor хранилище, всегда отвечающее неклассифицированной ошибкой
+type unknownErrorProcessor struct {
+	testProcessor
+	calls int
+}
+
+func (p *unknownErrorProcessor) AddSharesBatch(ctx context.Context, shares []entity.Share) error {
+	p.calls++
+	return fmt.Errorf("clickhouse: code: 999, message: unexpected")
+}
+
+func TestUnknownSaveErrorRetriesCapped(t *testing.T) {
+	logger.InitLogger(logger.LogLevelProduction, t.TempDir()+"/test.log")
+
+	processor := &unknownErrorProcessor{testProcessor: testProcessor{byWallet: make(map[string][]string)}}
+	writer := &testDeadLetterWriter{}
+	consumer, err := NewShareConsumer(Config{RetryBackoffMin: time.Millisecond, RetryBackoffMax: time.Millisecond, UnknownRetries: 3}, nil, processor, writer, nil)
+	require.NoError(t, err)
+
+	// неклассифицированная ошибка повторяется UnknownRetries раз, затем шары уходят в dead-letter, пакет не блокируется
+	retry := func(op func() error) error { return consumer.retryUntil(context.Background(), op) }
+	err = consumer.processBatch(context.Background(), []*sarama.ConsumerMessage{
+		testMessage(t, 1, dto.ShareFound{Uuid: "uuid-1", CoinSymbol: "ALPH", Workerfull: "a.w"}),
+		testMessage(t, 2, dto.ShareFound{Uuid: "uuid-2", CoinSymbol: "ALPH", Workerfull: "a.w"}),
+	}, retry)
+	require.NoError(t, err)
+	require.Equal(t, 3, processor.calls)
+	require.Equal(t, []int64{1, 2}, writer.sent)
+	require.Equal(t, uint64(3), consumer.ErrorCounts()[entity.ErrorClassUnknown])
+}
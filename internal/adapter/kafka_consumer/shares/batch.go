@@ -0,0 +1,311 @@
+package shares
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/dto"
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+	"github.com/dnsoftware/mpm-shares-processor/internal/usecase/share"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+)
+
+// batchItem декодированное сообщение пакета
+type batchItem struct {
+	msg   *sarama.ConsumerMessage
+	ctx   context.Context // контекст трассировки из заголовков сообщения
+	share dto.ShareFound
+}
+
+// normalizeResult результат нормализации шары пакета
+type normalizeResult struct {
+	share entity.Share
+	err   error
+}
+
+// processBatch декодирование, проверка, нормализация и сохранение пакета сообщений одной партиции
+// временные ошибки нормализации и сохранения повторяются через retry (в ConsumeClaim - с приостановкой партиции),
+// неклассифицированные ошибки сохранения - тоже, но не более UnknownRetries попыток;
+// сообщения с постоянными ошибками, неклассифицированными ошибками нормализации и исчерпавшие попытки сохранения
+// уходят в dead-letter топик (с подтверждением брокера)
+// ctx ограничивает разрешение кошельков и сохранение (при сбросе пакетов - FlushTimeout)
+// ошибка возвращается только если пакет так и не удалось обработать
+func (consumer *ShareConsumer) processBatch(ctx context.Context, batch []*sarama.ConsumerMessage, retry func(op func() error) error) error {
+	start := time.Now()
+
+	var rejected []rejectedMessage
+	items := consumer.decodeBatch(batch, &rejected)
+	items = consumer.validateBatch(items, &rejected)
+
+	// кошельки и воркеры пакета разрешаем одним запросом, не разрешенные получим в NormalizeShare по одному
+	found := make([]dto.ShareFound, len(items))
+	for i, item := range items {
+		found[i] = item.share
+	}
+	if err := consumer.ResolveIdentities(ctx, found); err != nil {
+		logger.Log().Warn("ResolveIdentities error: " + err.Error())
+	}
+
+	results := consumer.normalizeBatch(items)
+
+	// шары с временными ошибками нормализуем повторно, пока сервисы не станут доступны
+	if hasTransient(results) {
+		if err := retry(func() error { return consumer.renormalizeTransient(items, results) }); err != nil {
+			return err
+		}
+	}
+
+	// результаты разбираем в порядке смещений
+	save := pendingSave{shares: make([]pendingShare, 0, len(items))}
+	for i, res := range results {
+		if res.err != nil {
+			consumer.deadLetter(items[i].ctx, &rejected, items[i].msg, ErrorClassNormalize, res.err)
+			continue
+		}
+		save.shares = append(save.shares, pendingShare{item: items[i], share: withKafkaPosition(res.share, items[i].msg)})
+	}
+	save.rejected = rejected
+
+	if len(save.shares) > 0 || len(save.rejected) > 0 {
+		if err := retry(func() error { return consumer.savePending(ctx, &save) }); err != nil {
+			return err
+		}
+	}
+
+	logger.Log().Info(fmt.Sprintf("Processed batch of %d messages (%d accepted, %d rejected) in %v",
+		len(batch), len(save.shares), len(batch)-len(save.shares), time.Since(start)))
+
+	return nil
+}
+
+// pendingShare нормализованная шара пакета, ожидающая сохранения
+type pendingShare struct {
+	item  batchItem
+	share entity.Share
+}
+
+// pendingSave шары пакета, ожидающие сохранения, и сообщения, ожидающие отправки в dead-letter топик
+type pendingSave struct {
+	shares   []pendingShare
+	rejected []rejectedMessage
+	saved    bool // шары сохранены (повторяется только отправка в dead-letter)
+	unknown  int  // неудачных попыток сохранения с неклассифицированной ошибкой
+}
+
+// renormalizeTransient повторная нормализация шар с временными ошибками
+// возвращает временную ошибку, если такие шары остались
+func (consumer *ShareConsumer) renormalizeTransient(items []batchItem, results []normalizeResult) error {
+	var lastErr error
+	failed := 0
+	for i := range results {
+		if results[i].err == nil || !entity.IsTransient(results[i].err) {
+			continue
+		}
+
+		results[i].share, results[i].err = consumer.NormalizeShare(items[i].ctx, items[i].share)
+		if results[i].err != nil && entity.IsTransient(results[i].err) {
+			failed++
+			lastErr = results[i].err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("normalize %d shares: %w", failed, lastErr)
+	}
+
+	return nil
+}
+
+func hasTransient(results []normalizeResult) bool {
+	for _, res := range results {
+		if res.err != nil && entity.IsTransient(res.err) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// savePending отправка отклоненных сообщений в dead-letter топик и сохранение шар пакета
+// при постоянной ошибке шара с ошибкой уходит в dead-letter, остальные сохраняются заново;
+// если шару определить нельзя - в dead-letter уходит весь пакет;
+// при частичном сохранении (entity.PartialSaveError) в dead-letter уходят только отклоненные шары (кроме дубликатов);
+// неклассифицированная ошибка возвращается для повтора, после UnknownRetries попыток обрабатывается как постоянная
+// dead-letter отправляется до сохранения: смещения сохраненных шар сдвигают чтение партиции (seekStoredOffsets),
+// и не подтвержденное брокером сообщение было бы потеряно
+func (consumer *ShareConsumer) savePending(ctx context.Context, save *pendingSave) error {
+	for {
+		if err := consumer.sendDeadLetters(&save.rejected); err != nil {
+			return err
+		}
+		if save.saved || len(save.shares) == 0 {
+			return nil
+		}
+
+		sharesBatch := make([]entity.Share, len(save.shares))
+		for i, p := range save.shares {
+			sharesBatch[i] = p.share
+		}
+
+		err := consumer.AddSharesBatch(ctx, sharesBatch)
+		if err == nil {
+			save.saved = true
+			continue
+		}
+
+		var partial *entity.PartialSaveError
+		if errors.As(err, &partial) {
+			consumer.rejectPartial(save, partial)
+			save.saved = true
+			continue
+		}
+		if entity.IsTransient(err) {
+			return err
+		}
+		if !entity.IsPermanent(err) {
+			save.unknown++
+			if save.unknown < consumer.unknownRetries() {
+				return err
+			}
+			logger.Log().Error(fmt.Sprintf("Save failed %d times with unclassified error, rejecting: %s", save.unknown, err.Error()))
+		}
+		consumer.countError(err)
+
+		idx := -1
+		var shareErr *entity.ShareError
+		if errors.As(err, &shareErr) {
+			for i, p := range save.shares {
+				if p.share.UUID == shareErr.UUID {
+					idx = i
+					break
+				}
+			}
+		}
+		if idx < 0 {
+			for _, p := range save.shares {
+				consumer.deadLetter(p.item.ctx, &save.rejected, p.item.msg, ErrorClassSave, err)
+			}
+			save.shares = nil
+			continue
+		}
+
+		p := save.shares[idx]
+		consumer.deadLetter(p.item.ctx, &save.rejected, p.item.msg, ErrorClassSave, err)
+		save.shares = append(save.shares[:idx], save.shares[idx+1:]...)
+	}
+}
+
+// rejectPartial отклоненные при частичном сохранении шары - в dead-letter, в пакете остаются сохраненные
+// дубликаты уже сохраненных шар остаются в пакете как сохраненные
+func (consumer *ShareConsumer) rejectPartial(save *pendingSave, partial *entity.PartialSaveError) {
+	reasons := make(map[string]error, len(partial.Rejected))
+	for _, r := range partial.Rejected {
+		if errors.Is(r, entity.ErrDuplicateShare) {
+			continue
+		}
+		reasons[r.UUID] = r
+	}
+
+	accepted := save.shares[:0]
+	for _, p := range save.shares {
+		if reason, ok := reasons[p.share.UUID]; ok {
+			consumer.countError(reason)
+			consumer.deadLetter(p.item.ctx, &save.rejected, p.item.msg, ErrorClassSave, reason)
+			continue
+		}
+		accepted = append(accepted, p)
+	}
+	save.shares = accepted
+}
+
+// decodeBatch декодирование сообщений пакета, сбойные сообщения ставятся в очередь dead-letter
+func (consumer *ShareConsumer) decodeBatch(batch []*sarama.ConsumerMessage, rejected *[]rejectedMessage) []batchItem {
+	items := make([]batchItem, 0, len(batch))
+
+	for _, msg := range batch {
+		// Извлекаем контекст трассировки из заголовков
+		carrier := propagation.MapCarrier{}
+		for _, header := range msg.Headers {
+			carrier[string(header.Key)] = string(header.Value)
+		}
+		ctx := otel.GetTextMapPropagator().Extract(context.Background(), carrier)
+
+		var item dto.ShareFound
+		err := json.Unmarshal(msg.Value, &item)
+		if err != nil {
+			consumer.deadLetter(ctx, rejected, msg, ErrorClassDecode, err)
+			continue
+		}
+
+		items = append(items, batchItem{msg: msg, ctx: ctx, share: item})
+	}
+
+	return items
+}
+
+// validateBatch проверка полей шар пакета, не прошедшие проверку шары ставятся в очередь dead-letter
+func (consumer *ShareConsumer) validateBatch(items []batchItem, rejected *[]rejectedMessage) []batchItem {
+	valid := items[:0]
+	for _, item := range items {
+		if err := consumer.ValidateShare(item.share); err != nil {
+			consumer.deadLetter(item.ctx, rejected, item.msg, ErrorClassValidate, err)
+			continue
+		}
+		valid = append(valid, item)
+	}
+
+	return valid
+}
+
+// normalizeBatch нормализация шар пакетом пулом из NormalizeWorkers горутин
+// шары одного кошелька обрабатывает одна горутина (без гонок при создании кошелька/воркера),
+// результаты возвращаются в порядке items
+func (consumer *ShareConsumer) normalizeBatch(items []batchItem) []normalizeResult {
+	results := make([]normalizeResult, len(items))
+
+	workers := consumer.cfg.NormalizeWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(items) {
+		workers = len(items)
+	}
+
+	// распределение по горутинам по имени кошелька
+	queues := make([][]int, workers)
+	for i, item := range items {
+		w := 0
+		if workers > 1 {
+			h := fnv.New32a()
+			h.Write([]byte(share.WalletFromWorkerfull(item.share.Workerfull)))
+			w = int(h.Sum32() % uint32(workers))
+		}
+		queues[w] = append(queues[w], i)
+	}
+
+	tracer := otel.Tracer("consume-share")
+
+	var wg sync.WaitGroup
+	for _, queue := range queues {
+		wg.Add(1)
+		go func(queue []int) {
+			defer wg.Done()
+			for _, i := range queue {
+				ctx, span := tracer.Start(items[i].ctx, "process")
+				results[i].share, results[i].err = consumer.NormalizeShare(ctx, items[i].share)
+				span.End()
+			}
+		}(queue)
+	}
+	wg.Wait()
+
+	return results
+}
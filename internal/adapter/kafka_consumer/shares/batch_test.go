@@ -0,0 +1,218 @@
+package shares
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/dto"
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+)
+
+type testProcessor struct {
+	mu       sync.Mutex
+	byWallet map[string][]string // порядок нормализации шар по кошелькам
+	saved    []entity.Share
+}
+
+func (p *testProcessor) ValidateShare(shareFound dto.ShareFound) error {
+	if shareFound.Uuid == "" {
+		return fmt.Errorf("%w: uuid is empty", entity.ErrInvalidShare)
+	}
+	return nil
+}
+
+func (p *testProcessor) ResolveIdentities(ctx context.Context, shares []dto.ShareFound) error {
+	return nil
+}
+
+func (p *testProcessor) NormalizeShare(ctx context.Context, shareFound dto.ShareFound) (entity.Share, error) {
+	if shareFound.CoinSymbol != "ALPH" {
+		return entity.Share{}, fmt.Errorf("%w %s", entity.ErrUnknownCoin, shareFound.CoinSymbol)
+	}
+
+	p.mu.Lock()
+	wallet := shareFound.Workerfull[:1]
+	p.byWallet[wallet] = append(p.byWallet[wallet], shareFound.Uuid)
+	p.mu.Unlock()
+
+	return shareFound.ToShare(), nil
+}
+
+func (p *testProcessor) AddSharesBatch(ctx context.Context, shares []entity.Share) error {
+	p.saved = append(p.saved, shares...)
+	return nil
+}
+
+func testMessage(t *testing.T, offset int64, share dto.ShareFound) *sarama.ConsumerMessage {
+	value, err := json.Marshal(share)
+	require.NoError(t, err)
+
+	return &sarama.ConsumerMessage{Topic: "shares", Partition: 1, Offset: offset, Value: value}
+}
+
+func TestProcessBatch(t *testing.T) {
+	logger.InitLogger(logger.LogLevelProduction, t.TempDir()+"/test.log")
+
+	processor := &testProcessor{byWallet: make(map[string][]string)}
+	consumer, err := NewShareConsumer(Config{NormalizeWorkers: 4}, nil, processor, nil, nil)
+	require.NoError(t, err)
+
+	var batch []*sarama.ConsumerMessage
+	var want []string
+	for i := 0; i < 100; i++ {
+		uuid := fmt.Sprintf("uuid-%03d", i)
+		wallet := string(rune('a' + i%5))
+		coin := "ALPH"
+		if i%10 == 3 {
+			coin = "UNKNOWN" // ошибка нормализации
+		} else {
+			want = append(want, uuid)
+		}
+		batch = append(batch, testMessage(t, int64(i), dto.ShareFound{Uuid: uuid, CoinSymbol: coin, Workerfull: wallet + ".w"}))
+	}
+	batch = append(batch, &sarama.ConsumerMessage{Topic: "shares", Partition: 1, Offset: 100, Value: []byte("{bad json")})
+	batch = append(batch, testMessage(t, 101, dto.ShareFound{CoinSymbol: "ALPH", Workerfull: "a.w"})) // не проходит проверку
+
+	err = consumer.processBatch(context.Background(), batch, retryOnce)
+	require.NoError(t, err)
+
+	// сохранены все корректные шары в порядке смещений
+	var saved []string
+	for _, sh := range processor.saved {
+		saved = append(saved, sh.UUID)
+		require.Equal(t, "shares", sh.KafkaTopic)
+		require.Equal(t, int32(1), sh.KafkaPartition)
+		require.Equal(t, sh.UUID, fmt.Sprintf("uuid-%03d", sh.KafkaOffset))
+	}
+	require.Equal(t, want, saved)
+	require.Equal(t, uint64(12), consumer.DeadLetteredCount())
+
+	// шары одного кошелька нормализуются последовательно в порядке смещений
+	for _, uuids := range processor.byWallet {
+		for i := 1; i < len(uuids); i++ {
+			require.Less(t, uuids[i-1], uuids[i])
+		}
+	}
+}
+
+// flakyProcessor первые normalizeFailures нормализаций - временная ошибка, шара badUUID не сохраняется
+type flakyProcessor struct {
+	testProcessor
+	normalizeFailures int
+	badUUID           string
+}
+
+func (p *flakyProcessor) NormalizeShare(ctx context.Context, shareFound dto.ShareFound) (entity.Share, error) {
+	p.mu.Lock()
+	if p.normalizeFailures > 0 {
+		p.normalizeFailures--
+		p.mu.Unlock()
+		return entity.Share{}, fmt.Errorf("miners: %w", entity.ErrServiceUnavailable)
+	}
+	p.mu.Unlock()
+
+	return p.testProcessor.NormalizeShare(ctx, shareFound)
+}
+
+func (p *flakyProcessor) AddSharesBatch(ctx context.Context, shares []entity.Share) error {
+	for _, sh := range shares {
+		if sh.UUID == p.badUUID {
+			return &entity.ShareError{UUID: sh.UUID, Err: entity.ErrInvalidDecimal}
+		}
+	}
+	return p.testProcessor.AddSharesBatch(ctx, shares)
+}
+
+func TestProcessBatchErrorClasses(t *testing.T) {
+	logger.InitLogger(logger.LogLevelProduction, t.TempDir()+"/test.log")
+
+	processor := &flakyProcessor{testProcessor: testProcessor{byWallet: make(map[string][]string)}, normalizeFailures: 5, badUUID: "uuid-2"}
+	consumer, err := NewShareConsumer(Config{}, nil, processor, nil, nil)
+	require.NoError(t, err)
+
+	var batch []*sarama.ConsumerMessage
+	for i := 1; i <= 3; i++ {
+		batch = append(batch, testMessage(t, int64(i), dto.ShareFound{Uuid: fmt.Sprintf("uuid-%d", i), CoinSymbol: "ALPH", Workerfull: "a.w"}))
+	}
+
+	// без повторов временная ошибка нормализации возвращается, пакет не сохраняется
+	err = consumer.processBatch(context.Background(), batch, retryOnce)
+	require.ErrorIs(t, err, entity.ErrServiceUnavailable)
+	require.Empty(t, processor.saved)
+	require.Zero(t, consumer.DeadLetteredCount())
+
+	// с повторами шары нормализуются, в dead-letter уходит только шара с постоянной ошибкой сохранения
+	processor.normalizeFailures = 4
+	retry := func(op func() error) error {
+		for {
+			err := op()
+			if err == nil || !entity.IsTransient(err) {
+				return err
+			}
+		}
+	}
+	err = consumer.processBatch(context.Background(), batch, retry)
+	require.NoError(t, err)
+	require.Len(t, processor.saved, 2)
+	require.Equal(t, "uuid-1", processor.saved[0].UUID)
+	require.Equal(t, "uuid-3", processor.saved[1].UUID)
+	require.Equal(t, uint64(1), consumer.DeadLetteredCount())
+	require.Equal(t, uint64(1), consumer.ErrorCounts()[entity.ErrorClassPermanent])
+}
+
+// partialProcessor хранилище, отклоняющее шару rejectUUID, отбрасывающее дубликат duplicateUUID и сохраняющее остальные
+type partialProcessor struct {
+	testProcessor
+	rejectUUID    string
+	duplicateUUID string
+	calls         int
+}
+
+func (p *partialProcessor) AddSharesBatch(ctx context.Context, shares []entity.Share) error {
+	p.calls++
+	var partial entity.PartialSaveError
+	for _, sh := range shares {
+		if sh.UUID == p.rejectUUID {
+			partial.Rejected = append(partial.Rejected, &entity.ShareError{UUID: sh.UUID, Err: entity.ErrInvalidShare})
+			continue
+		}
+		if sh.UUID == p.duplicateUUID {
+			partial.Rejected = append(partial.Rejected, &entity.ShareError{UUID: sh.UUID, Err: entity.ErrDuplicateShare})
+			continue
+		}
+		p.saved = append(p.saved, sh)
+	}
+	if len(partial.Rejected) > 0 {
+		return &partial
+	}
+	return nil
+}
+
+func TestProcessBatchPartialSave(t *testing.T) {
+	logger.InitLogger(logger.LogLevelProduction, t.TempDir()+"/test.log")
+
+	processor := &partialProcessor{testProcessor: testProcessor{byWallet: make(map[string][]string)}, rejectUUID: "uuid-2", duplicateUUID: "uuid-3"}
+	writer := &testDeadLetterWriter{}
+	consumer, err := NewShareConsumer(Config{}, nil, processor, writer, nil)
+	require.NoError(t, err)
+
+	var batch []*sarama.ConsumerMessage
+	for i := 1; i <= 3; i++ {
+		batch = append(batch, testMessage(t, int64(i), dto.ShareFound{Uuid: fmt.Sprintf("uuid-%d", i), CoinSymbol: "ALPH", Workerfull: "a.w"}))
+	}
+
+	// сохраненные шары не сохраняются повторно, в dead-letter уходит только отклоненная (дубликат - нет)
+	err = consumer.processBatch(context.Background(), batch, retryOnce)
+	require.NoError(t, err)
+	require.Equal(t, 1, processor.calls)
+	require.Len(t, processor.saved, 1)
+	require.Equal(t, []int64{2}, writer.sent)
+	require.Equal(t, uint64(1), consumer.ErrorCounts()[entity.ErrorClassPermanent])
+}
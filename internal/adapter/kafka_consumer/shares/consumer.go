@@ -3,13 +3,10 @@ package shares
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/IBM/sarama"
-	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/propagation"
 
 	"github.com/dnsoftware/mpm-shares-processor/pkg/kafka_reader"
 	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
@@ -19,30 +16,72 @@ import (
 )
 
 type Config struct {
-	BatchSize     int           // Размер буфера для пакетного чтения
-	FlushInterval time.Duration // Максимальное время ожидания для заполнения пакета в секундах
+	BatchSize        int           // Размер буфера для пакетного чтения
+	FlushInterval    time.Duration // Максимальное время ожидания для заполнения пакета в секундах
+	NormalizeWorkers int           // Размер пула параллельной нормализации шар пакета (<= 1 - последовательно)
+	RetryBackoffMin  time.Duration // Начальная пауза между повторами сохранения пакета (0 - DefaultRetryBackoffMin)
+	RetryBackoffMax  time.Duration // Максимальная пауза между повторами сохранения пакета (0 - DefaultRetryBackoffMax)
+	FlushTimeout     time.Duration // Время на сохранение незавершенных пакетов при отзыве партиций и остановке (0 - DefaultFlushTimeout)
+	UnknownRetries   int           // Попыток сохранения пакета при неклассифицированной ошибке, затем шары уходят в dead-letter (0 - DefaultUnknownRetries)
 }
 
 type Processor interface {
+	ValidateShare(shareFound dto.ShareFound) error                        // проверка полей шары до нормализации
+	ResolveIdentities(ctx context.Context, shares []dto.ShareFound) error // пакетное разрешение кошельков и воркеров перед нормализацией
 	NormalizeShare(ctxTask context.Context, shareFound dto.ShareFound) (entity.Share, error)
-	AddSharesBatch(shares []entity.Share) error
+	AddSharesBatch(ctx context.Context, shares []entity.Share) error
 }
 
 // ShareConsumer реализует интерфейс sarama.ConsumerGroupHandler
 type ShareConsumer struct {
-	cfg         Config
-	kafkaReader *kafka_reader.KafkaReader
-	msgChan     chan *sarama.ConsumerMessage
+	cfg              Config
+	kafkaReader      *kafka_reader.KafkaReader
+	msgChan          chan *sarama.ConsumerMessage
+	deadLetterWriter DeadLetterWriter // nil - dead-letter топик не используется
+	deadLettered     atomic.Uint64    // счетчик шар, отправленных в dead-letter топик
+	offsetStore      OffsetStore      // nil - смещения берутся только из Кафки
+	skipped          atomic.Uint64    // счетчик сообщений, пропущенных при повторной обработке как уже сохраненные
+	transientErrors  atomic.Uint64    // счетчик временных ошибок обработки
+	permanentErrors  atomic.Uint64    // счетчик постоянных ошибок обработки
+	unknownErrors    atomic.Uint64    // счетчик неклассифицированных ошибок обработки
+	pauser           PartitionPauser  // nil - партиции не приостанавливаются на время повторов
+	backpressure     backpressure
+	pending          pendingBatches // незавершенные пакеты партиций для сохранения в Cleanup
+	batchSize        atomic.Int64   // текущий размер пакета (изменяется на ходу SetBatching)
+	flushInterval    atomic.Int64   // текущий интервал сброса пакета в секундах (изменяется на ходу SetBatching)
 	Processor
 }
 
-func NewShareConsumer(cfg Config, kafkaReader *kafka_reader.KafkaReader, processor Processor) (*ShareConsumer, error) {
-	return &ShareConsumer{
-		cfg:         cfg,
-		kafkaReader: kafkaReader,
-		msgChan:     make(chan *sarama.ConsumerMessage),
-		Processor:   processor,
-	}, nil
+func NewShareConsumer(cfg Config, kafkaReader *kafka_reader.KafkaReader, processor Processor, deadLetterWriter DeadLetterWriter, offsetStore OffsetStore) (*ShareConsumer, error) {
+	var pauser PartitionPauser
+	if kafkaReader != nil {
+		pauser = kafkaReader
+	}
+
+	consumer := &ShareConsumer{
+		cfg:              cfg,
+		kafkaReader:      kafkaReader,
+		msgChan:          make(chan *sarama.ConsumerMessage),
+		deadLetterWriter: deadLetterWriter,
+		offsetStore:      offsetStore,
+		pauser:           pauser,
+		Processor:        processor,
+	}
+	consumer.SetBatching(cfg.BatchSize, cfg.FlushInterval)
+
+	return consumer, nil
+}
+
+// SetBatching изменение размера пакета и интервала сброса (в секундах) на ходу
+// новые значения действуют с очередного сброса пакета
+func (consumer *ShareConsumer) SetBatching(batchSize int, flushInterval time.Duration) {
+	consumer.batchSize.Store(int64(batchSize))
+	consumer.flushInterval.Store(int64(flushInterval))
+}
+
+// Batching текущие размер пакета и интервал сброса (в секундах)
+func (consumer *ShareConsumer) Batching() (int, time.Duration) {
+	return int(consumer.batchSize.Load()), time.Duration(consumer.flushInterval.Load())
 }
 
 // StartConsume Стартует чтение из Кафки
@@ -64,115 +103,82 @@ func (consumer *ShareConsumer) Close() {
 }
 
 // Setup вызывается перед началом обработки (интерфейс ConsumerGroupHandler)
+// сдвигает смещения полученных партиций за последние сохраненные вместе с шарами
 func (consumer *ShareConsumer) Setup(session sarama.ConsumerGroupSession) error {
-	return nil
+	return consumer.seekStoredOffsets(session)
 }
 
 // Cleanup вызывается после завершения обработки (интерфейс ConsumerGroupHandler)
+// при ребалансировке и остановке сохраняет незавершенные пакеты партиций и фиксирует их смещения
 func (consumer *ShareConsumer) Cleanup(session sarama.ConsumerGroupSession) error {
-	return nil
+	return consumer.flushPending(session)
 }
 
 // ConsumeClaim обрабатывает сообщения из партиций (интерфейс ConsumerGroupHandler)
-// если используем канал consumer.MsgChan (или какой-то подобный) - нужно его вычитывать снаружи
+// сообщения копятся в пакет до BatchSize или до истечения FlushInterval, смещение сдвигается только после сохранения пакета
+// при временных ошибках чтение партиции приостанавливается и пакет обрабатывается повторно (withBackpressure)
+// при завершении сессии незавершенный пакет передается на сохранение в Cleanup
 func (consumer *ShareConsumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
 
-	var item dto.ShareFound
 	var batch []*sarama.ConsumerMessage // Буфер для пакетного чтения
-	timer := time.NewTimer(consumer.cfg.FlushInterval * time.Second)
-
-	for msg := range claim.Messages() {
+	_, flushInterval := consumer.Batching()
+	timer := time.NewTimer(flushInterval * time.Second)
+	defer timer.Stop()
 
-		// Создаем carrier для извлечения заголовков
-		carrier := propagation.MapCarrier{}
-		// Извлекаем контекст трассировки из заголовков
-		// Преобразуем заголовки Kafka в формат map
-		for _, header := range msg.Headers {
-			carrier[string(header.Key)] = string(header.Value)
-		}
-		ctx := otel.GetTextMapPropagator().Extract(context.Background(), carrier)
-		tracer := otel.Tracer("consume-share")
-		ctx, span := tracer.Start(ctx, "process")
-
-		// Функция для обработки пакета
-		processBatch := func() error {
-			if len(batch) > 0 {
-				sharesBatch := make([]entity.Share, 0, len(batch))
-
-				start := time.Now().UnixMilli()
-				logger.Log().Info(fmt.Sprintf("Processing batch of %d messages", len(batch)))
-				for _, mess := range batch {
-					if mess == nil {
-						fmt.Println("batch message nil")
-						continue
-					}
-					err := json.Unmarshal(mess.Value, &item)
-					normShare, err := consumer.NormalizeShare(ctx, item)
-					if err != nil {
-						logger.Log().Error("NormalizeShare error: " + err.Error())
-						return fmt.Errorf("NormalizeShare error: %s", err.Error())
-					}
-					sharesBatch = append(sharesBatch, normShare)
-				}
-				end := time.Now().UnixMilli()
-				fmt.Println(fmt.Sprintf("Batch time: %v", end-start))
-
-				err := consumer.AddSharesBatch(sharesBatch)
-				if err != nil {
-					return err
-				}
-
-				// Помечаем смещения для пакета как прочитанные
-				session.MarkOffset(batch[len(batch)-1].Topic, batch[len(batch)-1].Partition, batch[len(batch)-1].Offset+1, "")
-				batch = nil // Очищаем пакет после обработки
+	retry := func(op func() error) error {
+		return consumer.withBackpressure(session, claim.Topic(), claim.Partition(), op)
+	}
 
+	// Функция для обработки пакета
+	processBatch := func() error {
+		if len(batch) > 0 {
+			// вставка не прерывается завершением сессии: начатый пакет дописывается, повторы прерывает withBackpressure
+			err := consumer.processBatch(context.Background(), batch, retry)
+			if err != nil {
+				return err
 			}
-			timer.Reset(consumer.cfg.FlushInterval * time.Second) // Сбрасываем таймер
 
-			return nil
+			// Помечаем смещения для пакета как прочитанные (все сообщения пакета сохранены или ушли в dead-letter)
+			last := batch[len(batch)-1]
+			session.MarkOffset(last.Topic, last.Partition, last.Offset+1, "")
+			batch = nil // Очищаем пакет после обработки
 		}
+		_, flushInterval := consumer.Batching()
+		timer.Reset(flushInterval * time.Second) // Сбрасываем таймер
 
-		for {
-			select {
-			case message, ok := <-claim.Messages():
-				if !ok {
-					fmt.Println("Channel claim.Messages() closed")
-					return nil
-				}
+		return nil
+	}
 
-				if message == nil {
-					fmt.Println("claim.Messages nil")
-					continue
-				}
-				batch = append(batch, message)
-				if len(batch) >= consumer.cfg.BatchSize {
-					err := processBatch()
-					if err != nil {
-						return err
-					}
-				}
-			case <-timer.C:
-				// Если сработал таймер, обрабатываем текущий пакет
+	for {
+		select {
+		case message, ok := <-claim.Messages():
+			if !ok {
+				logger.Log().Info("Channel claim.Messages() closed")
+				consumer.setPending(claim.Topic(), claim.Partition(), batch)
+				return nil
+			}
+
+			if message == nil {
+				continue
+			}
+			batch = append(batch, message)
+			if batchSize, _ := consumer.Batching(); len(batch) >= batchSize {
 				err := processBatch()
 				if err != nil {
 					return err
 				}
-
-			case <-session.Context().Done():
-				// Завершаем работу при остановке сессии
-				return nil
 			}
-		}
-
-		//consumer.msgChan <- msg
-
-		// Если сообщение успешно обработано - помечаем, как обработанное TODO
-		// session.MarkMessage(msg, "")
-		// иначе - логируем ошибку и делаем пометку "алерт" TODO
-		// ...
+		case <-timer.C:
+			// Если сработал таймер, обрабатываем текущий пакет
+			err := processBatch()
+			if err != nil {
+				return err
+			}
 
-		span.End()
+		case <-session.Context().Done():
+			// Завершаем работу при остановке сессии
+			consumer.setPending(claim.Topic(), claim.Partition(), batch)
+			return nil
+		}
 	}
-
-	return nil
 }
@@ -0,0 +1,124 @@
+package shares
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+)
+
+// Заголовки сообщения в dead-letter топике
+const (
+	HeaderErrorClass        = "dlq-error-class"        // этап обработки (ErrorClassDecode, ErrorClassValidate, ErrorClassNormalize, ErrorClassSave)
+	HeaderErrorKind         = "dlq-error-kind"         // класс ошибки (entity.ErrorClassPermanent, entity.ErrorClassUnknown)
+	HeaderError             = "dlq-error"              // текст ошибки
+	HeaderOriginalTopic     = "dlq-original-topic"     // исходный топик
+	HeaderOriginalPartition = "dlq-original-partition" // исходная партиция
+	HeaderOriginalOffset    = "dlq-original-offset"    // исходное смещение
+)
+
+// Классы ошибок, по которым шара отправляется в dead-letter топик
+const (
+	ErrorClassDecode    = "decode"    // не удалось разобрать JSON
+	ErrorClassValidate  = "validate"  // шара не прошла проверку полей (формат uuid, числовые поля и т.п.)
+	ErrorClassNormalize = "normalize" // ошибка NormalizeShare (неизвестная монета и т.п., кроме временных ошибок)
+	ErrorClassSave      = "save"      // постоянная ошибка сохранения (некорректные числовые поля и т.п.)
+)
+
+// DeadLetterSendTimeout ожидание подтверждения брокером одного сообщения dead-letter топика
+const DeadLetterSendTimeout = 10 * time.Second
+
+// DeadLetterWriter отправка сообщений в dead-letter топик с ожиданием подтверждения брокера (реализуется kafka_writer.KafkaWriter)
+type DeadLetterWriter interface {
+	SendMessageWait(ctx context.Context, key string, value []byte, headers map[string]string) error
+}
+
+// rejectedMessage сообщение пакета, ожидающее отправки в dead-letter топик
+type rejectedMessage struct {
+	ctx      context.Context // контекст трассировки из заголовков сообщения
+	msg      *sarama.ConsumerMessage
+	errClass string
+	err      error
+}
+
+// deadLetterHeaders заголовки для отправки сообщения в dead-letter топик
+// контекст трассировки добавляет DeadLetterWriter
+func deadLetterHeaders(msg *sarama.ConsumerMessage, errClass string, procErr error) map[string]string {
+	return map[string]string{
+		HeaderErrorClass:        errClass,
+		HeaderErrorKind:         entity.ErrorClass(procErr),
+		HeaderError:             procErr.Error(),
+		HeaderOriginalTopic:     msg.Topic,
+		HeaderOriginalPartition: strconv.FormatInt(int64(msg.Partition), 10),
+		HeaderOriginalOffset:    strconv.FormatInt(msg.Offset, 10),
+	}
+}
+
+// deadLetter учет сбойного сообщения и постановка его в очередь отправки в dead-letter топик
+// сообщения отправляет sendDeadLetters до фиксации смещения пакета
+func (consumer *ShareConsumer) deadLetter(ctx context.Context, rejected *[]rejectedMessage, msg *sarama.ConsumerMessage, errClass string, procErr error) {
+	if errClass != ErrorClassSave { // ошибки сохранения учтены в savePending
+		consumer.countError(procErr)
+	}
+	*rejected = append(*rejected, rejectedMessage{ctx: ctx, msg: msg, errClass: errClass, err: procErr})
+}
+
+// sendDeadLetters отправка сообщений в dead-letter топик с ожиданием подтверждения брокера
+// отправленные сообщения удаляются из rejected; если брокер не подтвердил отправку - временная ошибка,
+// пакет повторяется и его смещение не фиксируется, пока все сообщения не приняты
+// если dead-letter топик не настроен - сообщения только логируются
+func (consumer *ShareConsumer) sendDeadLetters(rejected *[]rejectedMessage) error {
+	for len(*rejected) > 0 {
+		r := (*rejected)[0]
+		logMsg := fmt.Sprintf("Share dead-lettered, class: %s, kind: %s, topic: %s, partition: %d, offset: %d: %s",
+			r.errClass, entity.ErrorClass(r.err), r.msg.Topic, r.msg.Partition, r.msg.Offset, r.err.Error())
+
+		if consumer.deadLetterWriter == nil {
+			logger.Log().Error("Dead-letter topic is not configured. " + logMsg)
+		} else {
+			ctx, cancel := context.WithTimeout(r.ctx, DeadLetterSendTimeout)
+			err := consumer.deadLetterWriter.SendMessageWait(ctx, string(r.msg.Key), r.msg.Value, deadLetterHeaders(r.msg, r.errClass, r.err))
+			cancel()
+			if err != nil {
+				return fmt.Errorf("dead-letter %s/%d offset %d: %w: %w", r.msg.Topic, r.msg.Partition, r.msg.Offset, entity.ErrQueueUnavailable, err)
+			}
+			logger.Log().Warn(logMsg)
+		}
+
+		consumer.deadLettered.Add(1)
+		*rejected = (*rejected)[1:]
+	}
+
+	return nil
+}
+
+// DeadLetteredCount количество шар, отправленных в dead-letter топик с момента запуска
+func (consumer *ShareConsumer) DeadLetteredCount() uint64 {
+	return consumer.deadLettered.Load()
+}
+
+// countError учет ошибки обработки по классу
+func (consumer *ShareConsumer) countError(err error) {
+	switch entity.ErrorClass(err) {
+	case entity.ErrorClassTransient:
+		consumer.transientErrors.Add(1)
+	case entity.ErrorClassPermanent:
+		consumer.permanentErrors.Add(1)
+	default:
+		consumer.unknownErrors.Add(1)
+	}
+}
+
+// ErrorCounts количество ошибок обработки по классам с момента запуска
+func (consumer *ShareConsumer) ErrorCounts() map[string]uint64 {
+	return map[string]uint64{
+		entity.ErrorClassTransient: consumer.transientErrors.Load(),
+		entity.ErrorClassPermanent: consumer.permanentErrors.Load(),
+		entity.ErrorClassUnknown:   consumer.unknownErrors.Load(),
+	}
+}
@@ -0,0 +1,89 @@
+package shares
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/dto"
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+)
+
+// testDeadLetterWriter брокер, не подтверждающий первые failures отправок
+type testDeadLetterWriter struct {
+	failures int
+	sent     []int64 // смещения отправленных сообщений
+}
+
+func (w *testDeadLetterWriter) SendMessageWait(ctx context.Context, key string, value []byte, headers map[string]string) error {
+	if w.failures > 0 {
+		w.failures--
+		return errors.New("kafka: not enough in-sync replicas")
+	}
+	var offset int64
+	fmt.Sscan(headers[HeaderOriginalOffset], &offset)
+	w.sent = append(w.sent, offset)
+
+	return nil
+}
+
+func TestDeadLetterHeaders(t *testing.T) {
+	msg := &sarama.ConsumerMessage{
+		Topic:     "shares",
+		Partition: 3,
+		Offset:    12345,
+	}
+
+	headers := deadLetterHeaders(msg, ErrorClassNormalize, fmt.Errorf("coinID must be greater then 0"))
+
+	require.Equal(t, ErrorClassNormalize, headers[HeaderErrorClass])
+	require.Equal(t, "coinID must be greater then 0", headers[HeaderError])
+	require.Equal(t, entity.ErrorClassUnknown, headers[HeaderErrorKind])
+	require.Equal(t, "shares", headers[HeaderOriginalTopic])
+	require.Equal(t, "3", headers[HeaderOriginalPartition])
+	require.Equal(t, "12345", headers[HeaderOriginalOffset])
+
+	headers = deadLetterHeaders(msg, ErrorClassSave, &entity.ShareError{UUID: "1", Err: entity.ErrInvalidDecimal})
+	require.Equal(t, ErrorClassSave, headers[HeaderErrorClass])
+	require.Equal(t, entity.ErrorClassPermanent, headers[HeaderErrorKind])
+}
+
+func TestDeadLetterAck(t *testing.T) {
+	logger.InitLogger(logger.LogLevelProduction, t.TempDir()+"/test.log")
+
+	processor := &testProcessor{byWallet: make(map[string][]string)}
+	writer := &testDeadLetterWriter{failures: 2}
+	consumer, err := NewShareConsumer(Config{}, nil, processor, writer, nil)
+	require.NoError(t, err)
+
+	batch := []*sarama.ConsumerMessage{
+		testMessage(t, 1, dto.ShareFound{Uuid: "uuid-1", CoinSymbol: "UNKNOWN", Workerfull: "a.w"}),
+		testMessage(t, 2, dto.ShareFound{Uuid: "uuid-2", CoinSymbol: "ALPH", Workerfull: "a.w"}),
+	}
+
+	// брокер не подтвердил отправку - временная ошибка, шары пакета не сохраняются (смещение не фиксируется)
+	err = consumer.processBatch(context.Background(), batch, retryOnce)
+	require.ErrorIs(t, err, entity.ErrQueueUnavailable)
+	require.True(t, entity.IsTransient(err))
+	require.Empty(t, processor.saved)
+	require.Zero(t, consumer.DeadLetteredCount())
+
+	retry := func(op func() error) error {
+		for {
+			err := op()
+			if err == nil || !entity.IsTransient(err) {
+				return err
+			}
+		}
+	}
+	err = consumer.processBatch(context.Background(), batch, retry)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1}, writer.sent)
+	require.Len(t, processor.saved, 1)
+	require.Equal(t, uint64(1), consumer.DeadLetteredCount())
+}
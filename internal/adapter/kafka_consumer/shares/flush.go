@@ -0,0 +1,100 @@
+package shares
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+)
+
+// DefaultFlushTimeout время на сохранение незавершенных пакетов при отзыве партиций и остановке
+const DefaultFlushTimeout = 10 * time.Second
+
+// pendingBatches незавершенные пакеты партиций, оставшиеся при завершении сессии
+type pendingBatches struct {
+	mu      sync.Mutex
+	batches map[topicPartition][]*sarama.ConsumerMessage
+}
+
+// setPending передача незавершенного пакета партиции на сохранение в Cleanup
+func (consumer *ShareConsumer) setPending(topic string, partition int32, batch []*sarama.ConsumerMessage) {
+	if len(batch) == 0 {
+		return
+	}
+
+	consumer.pending.mu.Lock()
+	defer consumer.pending.mu.Unlock()
+
+	if consumer.pending.batches == nil {
+		consumer.pending.batches = make(map[topicPartition][]*sarama.ConsumerMessage)
+	}
+	consumer.pending.batches[topicPartition{topic: topic, partition: partition}] = batch
+}
+
+func (consumer *ShareConsumer) takePending() map[topicPartition][]*sarama.ConsumerMessage {
+	consumer.pending.mu.Lock()
+	defer consumer.pending.mu.Unlock()
+
+	batches := consumer.pending.batches
+	consumer.pending.batches = nil
+
+	return batches
+}
+
+// flushPending сохранение незавершенных пакетов и фиксация их смещений (вызывается из Cleanup)
+// на все пакеты (и повторы, и сами вставки) отводится FlushTimeout, не сохраненные за это время пакеты будут перечитаны новым владельцем партиции
+func (consumer *ShareConsumer) flushPending(session sarama.ConsumerGroupSession) error {
+	batches := consumer.takePending()
+	if len(batches) == 0 {
+		return nil
+	}
+
+	timeout := consumer.cfg.FlushTimeout
+	if timeout <= 0 {
+		timeout = DefaultFlushTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	retry := func(op func() error) error {
+		return consumer.retryUntil(ctx, op)
+	}
+
+	var flushErr error
+	for tp, batch := range batches {
+		if err := consumer.processBatch(ctx, batch, retry); err != nil {
+			logger.Log().Error(fmt.Sprintf("Partition %s/%d revoked, %d messages not flushed: %s", tp.topic, tp.partition, len(batch), err.Error()))
+			flushErr = err
+			continue
+		}
+
+		last := batch[len(batch)-1]
+		session.MarkOffset(last.Topic, last.Partition, last.Offset+1, "")
+		logger.Log().Info(fmt.Sprintf("Partition %s/%d revoked, flushed %d messages", tp.topic, tp.partition, len(batch)))
+	}
+	session.Commit()
+
+	return flushErr
+}
+
+// retryUntil повтор операции при временных ошибках до успеха или завершения ctx
+func (consumer *ShareConsumer) retryUntil(ctx context.Context, op func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := op()
+		if err == nil || entity.IsPermanent(err) {
+			return err
+		}
+		consumer.countError(err)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ctx.Err(), err)
+		case <-time.After(consumer.retryBackoff(attempt)):
+		}
+	}
+}
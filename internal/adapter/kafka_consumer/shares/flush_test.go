@@ -0,0 +1,103 @@
+package shares
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/dto"
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+)
+
+// testClaim партиция с каналом сообщений
+type testClaim struct {
+	sarama.ConsumerGroupClaim
+	messages chan *sarama.ConsumerMessage
+}
+
+func (c *testClaim) Topic() string {
+	return "shares"
+}
+
+func (c *testClaim) Partition() int32 {
+	return 1
+}
+
+func (c *testClaim) Messages() <-chan *sarama.ConsumerMessage {
+	return c.messages
+}
+
+func TestCleanupFlushesPendingBatch(t *testing.T) {
+	logger.InitLogger(logger.LogLevelProduction, t.TempDir()+"/test.log")
+
+	processor := &failingProcessor{testProcessor: testProcessor{byWallet: make(map[string][]string)}}
+	consumer, err := NewShareConsumer(Config{BatchSize: 100, FlushInterval: 3600, RetryBackoffMin: time.Millisecond, FlushTimeout: 50 * time.Millisecond}, nil, processor, nil, nil)
+	require.NoError(t, err)
+
+	consume := func(offsets ...int64) *testSession {
+		ctx, cancel := context.WithCancel(context.Background())
+		session := &testSession{ctx: ctx, marked: make(map[int32]int64)}
+		claim := &testClaim{messages: make(chan *sarama.ConsumerMessage)}
+		done := make(chan error)
+		go func() { done <- consumer.ConsumeClaim(session, claim) }()
+		for _, offset := range offsets {
+			claim.messages <- testMessage(t, offset, dto.ShareFound{Uuid: fmt.Sprintf("uuid-%d", offset), CoinSymbol: "ALPH", Workerfull: "a.w"})
+		}
+		cancel()
+		require.NoError(t, <-done)
+
+		return session
+	}
+
+	// отзыв партиции: неполный пакет сохраняется и фиксируется в Cleanup
+	session := consume(10, 11, 12)
+	require.Empty(t, processor.saved)
+	require.NoError(t, consumer.Cleanup(session))
+	require.Len(t, processor.saved, 3)
+	require.Equal(t, map[int32]int64{1: 13}, session.marked)
+	require.Equal(t, 1, session.commits)
+
+	// хранилище недоступно дольше FlushTimeout: смещение не сдвигается, пакет перечитает новый владелец партиции
+	processor.failures = 1000
+	session = consume(13)
+	require.Error(t, consumer.Cleanup(session))
+	require.Len(t, processor.saved, 3)
+	require.Empty(t, session.marked)
+
+	// повторный Cleanup без незавершенных пакетов ничего не делает
+	require.NoError(t, consumer.Cleanup(session))
+}
+
+// hangingProcessor хранилище, вставка в которое не завершается до отмены ctx
+type hangingProcessor struct {
+	testProcessor
+}
+
+func (p *hangingProcessor) AddSharesBatch(ctx context.Context, shares []entity.Share) error {
+	<-ctx.Done()
+	return fmt.Errorf("%w: %w", entity.ErrStorageUnavailable, ctx.Err())
+}
+
+func TestCleanupFlushDeadlineBoundsInsert(t *testing.T) {
+	logger.InitLogger(logger.LogLevelProduction, t.TempDir()+"/test.log")
+
+	processor := &hangingProcessor{testProcessor: testProcessor{byWallet: make(map[string][]string)}}
+	consumer, err := NewShareConsumer(Config{RetryBackoffMin: time.Millisecond, FlushTimeout: 50 * time.Millisecond}, nil, processor, nil, nil)
+	require.NoError(t, err)
+
+	consumer.setPending("shares", 1, []*sarama.ConsumerMessage{
+		testMessage(t, 10, dto.ShareFound{Uuid: "uuid-10", CoinSymbol: "ALPH", Workerfull: "a.w"}),
+	})
+	session := &testSession{ctx: context.Background(), marked: make(map[int32]int64)}
+
+	// зависшая вставка прерывается по FlushTimeout, а не только ожидание между повторами
+	start := time.Now()
+	require.Error(t, consumer.Cleanup(session))
+	require.Less(t, time.Since(start), time.Second)
+	require.Empty(t, session.marked)
+}
@@ -0,0 +1,55 @@
+package shares
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/IBM/sarama"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/constants"
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+)
+
+// OffsetStore смещения, сохраненные в хранилище шар в той же вставке, что и шары (реализуется ClickhouseShareStorage)
+type OffsetStore interface {
+	LastOffset(ctx context.Context, topic string, partition int32) (int64, bool, error) // found = false - из партиции еще ничего не сохранялось
+}
+
+// seekStoredOffsets сдвиг смещений полученных партиций за последние сохраненные в хранилище шары
+// закрывает окно между вставкой пакета и фиксацией смещения в Кафке: уже сохраненные шары повторно не читаются
+// смещения только сдвигаются вперед (MarkOffset), сообщения, ушедшие в dead-letter, могут быть перечитаны
+func (consumer *ShareConsumer) seekStoredOffsets(session sarama.ConsumerGroupSession) error {
+	if consumer.offsetStore == nil {
+		return nil
+	}
+
+	for topic, partitions := range session.Claims() {
+		for _, partition := range partitions {
+			ctx, cancel := context.WithTimeout(session.Context(), constants.ContextTimeout*time.Second)
+			offset, found, err := consumer.offsetStore.LastOffset(ctx, topic, partition)
+			cancel()
+			if err != nil {
+				return fmt.Errorf("seekStoredOffsets: %w", err)
+			}
+			if !found {
+				continue
+			}
+
+			session.MarkOffset(topic, partition, offset+1, "")
+			logger.Log().Info(fmt.Sprintf("Partition %s/%d: stored offset %d, consuming from %d", topic, partition, offset, offset+1))
+		}
+	}
+
+	return nil
+}
+
+// withKafkaPosition позиция сообщения в Кафке для сохранения вместе с шарой
+func withKafkaPosition(share entity.Share, msg *sarama.ConsumerMessage) entity.Share {
+	share.KafkaTopic = msg.Topic
+	share.KafkaPartition = msg.Partition
+	share.KafkaOffset = msg.Offset
+
+	return share
+}
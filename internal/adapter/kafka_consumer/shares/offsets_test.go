@@ -0,0 +1,78 @@
+package shares
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+)
+
+type testOffsetStore struct {
+	offsets map[int32]int64
+	err     error
+}
+
+func (s *testOffsetStore) LastOffset(ctx context.Context, topic string, partition int32) (int64, bool, error) {
+	if s.err != nil {
+		return 0, false, s.err
+	}
+	offset, ok := s.offsets[partition]
+
+	return offset, ok, nil
+}
+
+// testSession сессия группы потребителей, запоминающая отмеченные смещения
+type testSession struct {
+	sarama.ConsumerGroupSession
+	ctx     context.Context // nil - context.Background()
+	claims  map[string][]int32
+	marked  map[int32]int64
+	commits int
+}
+
+func (s *testSession) Claims() map[string][]int32 {
+	return s.claims
+}
+
+func (s *testSession) Context() context.Context {
+	if s.ctx != nil {
+		return s.ctx
+	}
+	return context.Background()
+}
+
+func (s *testSession) MarkOffset(topic string, partition int32, offset int64, metadata string) {
+	s.marked[partition] = offset
+}
+
+func (s *testSession) Commit() {
+	s.commits++
+}
+
+func TestSetupSeekStoredOffsets(t *testing.T) {
+	logger.InitLogger(logger.LogLevelProduction, t.TempDir()+"/test.log")
+
+	store := &testOffsetStore{offsets: map[int32]int64{0: 41, 2: 7}}
+	consumer, err := NewShareConsumer(Config{}, nil, nil, nil, store)
+	require.NoError(t, err)
+
+	session := &testSession{claims: map[string][]int32{"shares": {0, 1, 2}}, marked: make(map[int32]int64)}
+	require.NoError(t, consumer.Setup(session))
+	require.Equal(t, map[int32]int64{0: 42, 2: 8}, session.marked) // партиция 1 без сохраненных шар не сдвигается
+
+	// ошибка хранилища не дает начать чтение с неверных смещений
+	store.err = fmt.Errorf("clickhouse unavailable")
+	session = &testSession{claims: map[string][]int32{"shares": {0}}, marked: make(map[int32]int64)}
+	require.Error(t, consumer.Setup(session))
+	require.Empty(t, session.marked)
+
+	// без хранилища смещения берутся из Кафки
+	consumer, err = NewShareConsumer(Config{}, nil, nil, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, consumer.Setup(session))
+	require.Empty(t, session.marked)
+}
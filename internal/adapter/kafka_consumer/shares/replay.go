@@ -0,0 +1,76 @@
+package shares
+
+import (
+	"context"
+	"time"
+
+	"github.com/IBM/sarama"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/constants"
+)
+
+// ProcessMessages обработка пакета сообщений одной партиции вне группы потребителей (повторная обработка диапазона)
+// тот же конвейер, что и в ConsumeClaim; временные ошибки повторяются до завершения ctx
+// сообщения, уже сохраненные в хранилище шар (смещение не больше сохраненного в OffsetStore), пропускаются
+func (consumer *ShareConsumer) ProcessMessages(ctx context.Context, batch []*sarama.ConsumerMessage) error {
+	retry := func(op func() error) error {
+		return consumer.retryUntil(ctx, op)
+	}
+
+	batch, err := consumer.skipStored(ctx, batch, retry)
+	if err != nil {
+		return err
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+
+	return consumer.processBatch(ctx, batch, retry)
+}
+
+// skipStored исключение из пакета сообщений, шары которых уже сохранены вместе со смещением
+func (consumer *ShareConsumer) skipStored(ctx context.Context, batch []*sarama.ConsumerMessage, retry func(op func() error) error) ([]*sarama.ConsumerMessage, error) {
+	if consumer.offsetStore == nil {
+		return batch, nil
+	}
+
+	type position struct {
+		topic     string
+		partition int32
+	}
+	stored := make(map[position]int64) // -1 - из партиции еще ничего не сохранялось
+	rest := batch[:0:0]
+	for _, msg := range batch {
+		pos := position{topic: msg.Topic, partition: msg.Partition}
+		last, ok := stored[pos]
+		if !ok {
+			err := retry(func() error {
+				ctx, cancel := context.WithTimeout(ctx, constants.ContextTimeout*time.Second)
+				defer cancel()
+				offset, found, err := consumer.offsetStore.LastOffset(ctx, msg.Topic, msg.Partition)
+				last = -1
+				if found {
+					last = offset
+				}
+				return err
+			})
+			if err != nil {
+				return nil, err
+			}
+			stored[pos] = last
+		}
+
+		if msg.Offset <= last {
+			consumer.skipped.Add(1)
+			continue
+		}
+		rest = append(rest, msg)
+	}
+
+	return rest, nil
+}
+
+// SkippedCount количество сообщений, пропущенных при повторной обработке как уже сохраненные
+func (consumer *ShareConsumer) SkippedCount() uint64 {
+	return consumer.skipped.Load()
+}
@@ -0,0 +1,73 @@
+package shares
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/dto"
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+)
+
+func TestProcessMessages(t *testing.T) {
+	logger.InitLogger(logger.LogLevelProduction, t.TempDir()+"/test.log")
+
+	processor := &failingProcessor{testProcessor: testProcessor{byWallet: make(map[string][]string)}, failures: 2}
+	consumer, err := NewShareConsumer(Config{RetryBackoffMin: time.Millisecond, RetryBackoffMax: time.Millisecond}, nil, processor, nil, nil)
+	require.NoError(t, err)
+
+	batch := []*sarama.ConsumerMessage{
+		testMessage(t, 5, dto.ShareFound{Uuid: "uuid-5", CoinSymbol: "ALPH", Workerfull: "a.w"}),
+		testMessage(t, 6, dto.ShareFound{Uuid: "uuid-6", CoinSymbol: "ALPH", Workerfull: "a.w"}),
+	}
+
+	// временные ошибки сохранения повторяются
+	require.NoError(t, consumer.ProcessMessages(context.Background(), batch))
+	require.Len(t, processor.saved, 2)
+
+	// повторы прекращаются по завершении ctx
+	processor.failures = 1000
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, consumer.ProcessMessages(ctx, batch), entity.ErrStorageUnavailable)
+}
+
+func TestProcessMessagesSkipsStored(t *testing.T) {
+	logger.InitLogger(logger.LogLevelProduction, t.TempDir()+"/test.log")
+
+	processor := &testProcessor{byWallet: make(map[string][]string)}
+	store := &testOffsetStore{offsets: map[int32]int64{1: 5}}
+	consumer, err := NewShareConsumer(Config{RetryBackoffMin: time.Millisecond, RetryBackoffMax: time.Millisecond}, nil, processor, nil, store)
+	require.NoError(t, err)
+
+	var batch []*sarama.ConsumerMessage
+	for offset := int64(4); offset <= 7; offset++ {
+		batch = append(batch, testMessage(t, offset, dto.ShareFound{Uuid: fmt.Sprintf("uuid-%d", offset), CoinSymbol: "ALPH", Workerfull: "a.w"}))
+	}
+
+	// сообщения до сохраненного смещения включительно повторно не вставляются
+	require.NoError(t, consumer.ProcessMessages(context.Background(), batch))
+	require.Len(t, processor.saved, 2)
+	require.Equal(t, "uuid-6", processor.saved[0].UUID)
+	require.Equal(t, "uuid-7", processor.saved[1].UUID)
+	require.Equal(t, uint64(2), consumer.SkippedCount())
+
+	// из партиции еще ничего не сохранялось - обрабатывается весь пакет
+	store.offsets = map[int32]int64{}
+	processor.saved = nil
+	require.NoError(t, consumer.ProcessMessages(context.Background(), batch))
+	require.Len(t, processor.saved, 4)
+
+	// смещение недоступно - повторы до завершения ctx, без сохранения
+	store.err = fmt.Errorf("clickhouse: %w", entity.ErrStorageUnavailable)
+	processor.saved = nil
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, consumer.ProcessMessages(ctx, batch), entity.ErrStorageUnavailable)
+	require.Empty(t, processor.saved)
+}
@@ -0,0 +1,37 @@
+package rest
+
+import (
+	"net/http"
+	"strings"
+
+	jwtauth "github.com/dnsoftware/mpm-miners-processor/pkg/jwt"
+)
+
+// TokenValidator проверка межсервисного JWT токена (реализуется jwt.ServiceSymmetric, тот же, что и у gRPC сервера)
+type TokenValidator interface {
+	GetClaims(tokenStr string) (*jwtauth.ClaimsSymmetric, error)
+	IsServiceValid(claims *jwtauth.ClaimsSymmetric) bool
+}
+
+// serviceAuth доступ только сервисам из auth.jwt_valid_services: токен в заголовке Authorization (с префиксом Bearer или без)
+func (s *Handler) serviceAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		if token == "" {
+			http.Error(w, "missing authorization token", http.StatusUnauthorized)
+			return
+		}
+
+		claims, err := s.auth.GetClaims(token)
+		if err != nil {
+			http.Error(w, "invalid token: "+err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if !s.auth.IsServiceValid(claims) {
+			http.Error(w, "service is not allowed", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,27 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+	"github.com/dnsoftware/mpm-shares-processor/internal/usecase/analitics"
+)
+
+// usecaseError ответ клиенту с кодом по классу ошибки
+func usecaseError(w http.ResponseWriter, err error) {
+	http.Error(w, err.Error(), statusCode(err))
+}
+
+// statusCode код ответа по классу ошибки: 400 - недопустимые параметры запроса,
+// 503 - хранилище или сервис недоступны (таймауты), 500 - остальные
+func statusCode(err error) int {
+	switch {
+	case errors.Is(err, analitics.ErrInvalidQuery), entity.IsPermanent(err):
+		return http.StatusBadRequest
+	case entity.IsTransient(err):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
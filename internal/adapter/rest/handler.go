@@ -17,14 +17,24 @@ import (
 
 // Handler представляет HTTP сервер
 type Handler struct {
-	analitics *analitics.AnaliticsUsecase
-	router    *chi.Mux
+	analitics     *analitics.AnaliticsUsecase
+	consumer      ConsumerStatus    // nil - обработчик Кафки не запущен, admin маршруты не подключаются
+	auth          TokenValidator    // проверка токена admin маршрутов, nil - admin маршруты не подключаются
+	duplicates    DuplicatesCounter // nil - дубликаты не считаются
+	healthChecker HealthChecker     // nil - маршрут /health не подключается
+	spill         SpillStatus       // nil - буфер на диске не используется
+	router        *chi.Mux
 }
 
-func NewHandler(analitics *analitics.AnaliticsUsecase) *Handler {
+func NewHandler(analitics *analitics.AnaliticsUsecase, consumer ConsumerStatus, auth TokenValidator, duplicates DuplicatesCounter, healthChecker HealthChecker, spill SpillStatus) *Handler {
 	s := &Handler{
-		analitics: analitics,
-		router:    chi.NewRouter(),
+		analitics:     analitics,
+		consumer:      consumer,
+		auth:          auth,
+		duplicates:    duplicates,
+		healthChecker: healthChecker,
+		spill:         spill,
+		router:        chi.NewRouter(),
 	}
 	s.router.Use(middleware.Logger)
 	s.routes()
@@ -36,31 +46,60 @@ func (s *Handler) Routes() *chi.Mux {
 	return s.router
 }
 
-func (s *Handler) coinHashrate(w http.ResponseWriter, r *http.Request) {
+// hashrateWindow хешрейт за скользящее окно
+type hashrateWindow struct {
+	Window   int64   `json:"window"`   // длительность окна в секундах
+	Hashrate float64 `json:"hashrate"` // хешей в секунду
+	Value    float64 `json:"value"`    // хешрейт в единицах unit
+	Unit     string  `json:"unit"`     // единица измерения (H/s, KH/s ... PH/s)
+}
+
+func newHashrateWindow(hw analitics.HashrateWindow) hashrateWindow {
+	value, unit := analitics.HumanHashrate(hw.Hashrate)
 
-	type Hashrate struct {
-		Hashrate uint64 `json:"hashrate"`
+	return hashrateWindow{
+		Window:   int64(hw.Window.Seconds()),
+		Hashrate: hw.Hashrate,
+		Value:    value,
+		Unit:     unit,
 	}
+}
+
+// hashrateResponse ответ с текущим и средними хешрейтами
+type hashrateResponse struct {
+	Hashrate float64          `json:"hashrate"` // текущий хешрейт (оставлено для совместимости)
+	Current  hashrateWindow   `json:"current"`
+	Averages []hashrateWindow `json:"averages"`
+}
+
+func newHashrateResponse(hr analitics.Hashrate) hashrateResponse {
+	resp := hashrateResponse{
+		Hashrate: hr.Current.Hashrate,
+		Current:  newHashrateWindow(hr.Current),
+		Averages: make([]hashrateWindow, 0, len(hr.Averages)),
+	}
+	for _, avg := range hr.Averages {
+		resp.Averages = append(resp.Averages, newHashrateWindow(avg))
+	}
+
+	return resp
+}
+
+func (s *Handler) coinHashrate(w http.ResponseWriter, r *http.Request) {
+
 	coinSymbol := strings.ToUpper(chi.URLParam(r, "coinSymbol"))
 
-	hr, err := s.analitics.CoinHashrate(coinSymbol)
+	hr, err := s.analitics.CoinHashrate(r.Context(), coinSymbol)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		usecaseError(w, err)
 		return
 	}
 
-	hashrate := Hashrate{
-		Hashrate: hr,
-	}
-
-	json.NewEncoder(w).Encode(hashrate)
+	json.NewEncoder(w).Encode(newHashrateResponse(hr))
 }
 
 func (s *Handler) walletHashrate(w http.ResponseWriter, r *http.Request) {
 
-	type Hashrate struct {
-		Hashrate uint64 `json:"hashrate"`
-	}
 	walletStr := chi.URLParam(r, "walletID")
 
 	walletID, err := strconv.ParseInt(walletStr, 10, 64)
@@ -69,17 +108,13 @@ func (s *Handler) walletHashrate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	hr, err := s.analitics.MinerHashrate(walletID)
+	hr, err := s.analitics.MinerHashrate(r.Context(), walletID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		usecaseError(w, err)
 		return
 	}
 
-	hashrate := Hashrate{
-		Hashrate: hr,
-	}
-
-	json.NewEncoder(w).Encode(hashrate)
+	json.NewEncoder(w).Encode(newHashrateResponse(hr))
 }
 
 // Создаем экземпляр WebSocket апгрейдера
@@ -0,0 +1,47 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/adapter/kafka_consumer/shares"
+)
+
+// ConsumerStatus состояние обработчика шар из Кафки (реализуется shares.ShareConsumer)
+type ConsumerStatus interface {
+	Backpressure() shares.BackpressureState
+	DeadLetteredCount() uint64
+	ErrorCounts() map[string]uint64
+}
+
+// DuplicatesCounter счетчик отброшенных дубликатов шар (реализуется share.ShareUseCase)
+type DuplicatesCounter interface {
+	DuplicatesCount() uint64
+}
+
+// consumerStatusResponse состояние обработчика шар
+type consumerStatusResponse struct {
+	Paused       bool                     `json:"paused"` // приостановлена хотя бы одна партиция
+	Backpressure shares.BackpressureState `json:"backpressure"`
+	DeadLettered uint64                   `json:"dead_lettered"` // шар отправлено в dead-letter топик
+	Duplicates   uint64                   `json:"duplicates"`    // отброшено дубликатов шар
+	Errors       map[string]uint64        `json:"errors"`        // ошибок обработки по классам (transient, permanent, unknown)
+}
+
+func (s *Handler) consumerStatus(w http.ResponseWriter, r *http.Request) {
+
+	bp := s.consumer.Backpressure()
+
+	var duplicates uint64
+	if s.duplicates != nil {
+		duplicates = s.duplicates.DuplicatesCount()
+	}
+
+	json.NewEncoder(w).Encode(consumerStatusResponse{
+		Paused:       len(bp.Paused) > 0,
+		Backpressure: bp,
+		DeadLettered: s.consumer.DeadLetteredCount(),
+		Duplicates:   duplicates,
+		Errors:       s.consumer.ErrorCounts(),
+	})
+}
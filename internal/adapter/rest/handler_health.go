@@ -0,0 +1,70 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// HealthChecker состояние компонентов сервиса (реализуется app.Registry)
+type HealthChecker interface {
+	Health(ctx context.Context) map[string]error // nil - компонент исправен
+}
+
+// SpillStatus состояние буфера шар на диске (реализуется spill.BufferedShareStorage)
+type SpillStatus interface {
+	SpilledCount() uint64
+	DrainedCount() uint64
+	SkippedCount() uint64
+	QuarantinedCount() uint64
+	BufferedBytes() int64
+}
+
+// healthResponse состояние компонентов сервиса
+type healthResponse struct {
+	Status     string               `json:"status"`          // ok - все компоненты исправны, иначе degraded
+	Components map[string]string    `json:"components"`      // ok или текст ошибки
+	Spill      *spillStatusResponse `json:"spill,omitempty"` // буфер на диске (если включен)
+}
+
+// spillStatusResponse состояние буфера шар на диске
+type spillStatusResponse struct {
+	Spilled       uint64 `json:"spilled"`        // пакетов записано в журнал
+	Drained       uint64 `json:"drained"`        // пакетов перенесено в хранилище
+	Skipped       uint64 `json:"skipped"`        // шар пропущено при вычитке как уже сохраненные
+	Quarantined   uint64 `json:"quarantined"`    // строк журнала в карантине (требуют разбора)
+	BufferedBytes int64  `json:"buffered_bytes"` // размер невычитанного журнала
+}
+
+func (s *Handler) health(w http.ResponseWriter, r *http.Request) {
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	resp := healthResponse{Status: "ok", Components: make(map[string]string)}
+	for name, err := range s.healthChecker.Health(ctx) {
+		if err != nil {
+			resp.Status = "degraded"
+			resp.Components[name] = err.Error()
+			continue
+		}
+		resp.Components[name] = "ok"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if resp.Status != "ok" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if s.spill != nil {
+		resp.Spill = &spillStatusResponse{
+			Spilled:       s.spill.SpilledCount(),
+			Drained:       s.spill.DrainedCount(),
+			Skipped:       s.spill.SkippedCount(),
+			Quarantined:   s.spill.QuarantinedCount(),
+			BufferedBytes: s.spill.BufferedBytes(),
+		}
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
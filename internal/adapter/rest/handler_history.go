@@ -0,0 +1,153 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/usecase/analitics"
+)
+
+// hashratePoint хешрейт за интервал истории
+type hashratePoint struct {
+	Time     int64   `json:"time"`     // начало интервала, unix timestamp в секундах
+	Hashrate float64 `json:"hashrate"` // хешей в секунду
+	Value    float64 `json:"value"`    // хешрейт в единицах unit
+	Unit     string  `json:"unit"`     // единица измерения (H/s, KH/s ... PH/s)
+}
+
+// hashrateHistoryResponse ответ с историей хешрейта
+type hashrateHistoryResponse struct {
+	From         int64           `json:"from"`     // unix timestamp в секундах
+	To           int64           `json:"to"`       // unix timestamp в секундах
+	Interval     int64           `json:"interval"` // интервал в секундах
+	RewardMethod string          `json:"reward_method"`
+	Points       []hashratePoint `json:"points"`
+}
+
+func newHashrateHistoryResponse(h analitics.HashrateHistory) hashrateHistoryResponse {
+	resp := hashrateHistoryResponse{
+		From:         h.From.Unix(),
+		To:           h.To.Unix(),
+		Interval:     int64(h.Interval.Seconds()),
+		RewardMethod: h.RewardMethod,
+		Points:       make([]hashratePoint, 0, len(h.Points)),
+	}
+	for _, p := range h.Points {
+		value, unit := analitics.HumanHashrate(p.Hashrate)
+		resp.Points = append(resp.Points, hashratePoint{
+			Time:     p.Time.Unix(),
+			Hashrate: p.Hashrate,
+			Value:    value,
+			Unit:     unit,
+		})
+	}
+
+	return resp
+}
+
+// parseHistoryQuery параметры запроса истории: from, to (unix timestamp или RFC3339), interval (в секундах), reward_method
+func parseHistoryQuery(r *http.Request) (analitics.HistoryQuery, error) {
+	var q analitics.HistoryQuery
+	var err error
+
+	values := r.URL.Query()
+
+	if q.From, err = parseHistoryTime(values.Get("from")); err != nil {
+		return q, fmt.Errorf("from: %w", err)
+	}
+	if q.To, err = parseHistoryTime(values.Get("to")); err != nil {
+		return q, fmt.Errorf("to: %w", err)
+	}
+	if interval := values.Get("interval"); interval != "" {
+		seconds, err := strconv.ParseInt(interval, 10, 64)
+		if err != nil || seconds <= 0 {
+			return q, fmt.Errorf("interval: must be a positive number of seconds")
+		}
+		q.Interval = time.Duration(seconds) * time.Second
+	}
+	q.RewardMethod = values.Get("reward_method")
+
+	return q, nil
+}
+
+func parseHistoryTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
+
+func (s *Handler) coinHashrateHistory(w http.ResponseWriter, r *http.Request) {
+
+	coinSymbol := strings.ToUpper(chi.URLParam(r, "coinSymbol"))
+
+	q, err := parseHistoryQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h, err := s.analitics.CoinHashrateHistory(r.Context(), coinSymbol, q)
+	if err != nil {
+		usecaseError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(newHashrateHistoryResponse(h))
+}
+
+func (s *Handler) walletHashrateHistory(w http.ResponseWriter, r *http.Request) {
+
+	walletID, err := strconv.ParseInt(chi.URLParam(r, "walletID"), 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	q, err := parseHistoryQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h, err := s.analitics.MinerHashrateHistory(r.Context(), walletID, q)
+	if err != nil {
+		usecaseError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(newHashrateHistoryResponse(h))
+}
+
+func (s *Handler) workerHashrateHistory(w http.ResponseWriter, r *http.Request) {
+
+	workerID, err := strconv.ParseInt(chi.URLParam(r, "workerID"), 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	q, err := parseHistoryQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h, err := s.analitics.WorkerHashrateHistory(r.Context(), workerID, q)
+	if err != nil {
+		usecaseError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(newHashrateHistoryResponse(h))
+}
@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// workerNonceReplays повторы nonce воркера
+type workerNonceReplays struct {
+	WorkerID int64  `json:"worker_id"`
+	Count    uint64 `json:"count"`    // всего повторов
+	Rejected uint64 `json:"rejected"` // из них отклонено
+}
+
+// nonceReplaysResponse повторы nonce по кошельку за период
+type nonceReplaysResponse struct {
+	WalletID int64                `json:"wallet_id"`
+	From     int64                `json:"from"` // unix timestamp в секундах
+	To       int64                `json:"to"`   // unix timestamp в секундах
+	Count    uint64               `json:"count"`
+	Rejected uint64               `json:"rejected"`
+	Workers  []workerNonceReplays `json:"workers"`
+}
+
+// walletNonceReplays количество повторов nonce по кошельку (from, to - unix timestamp или RFC3339, по умолчанию последние сутки)
+func (s *Handler) walletNonceReplays(w http.ResponseWriter, r *http.Request) {
+
+	walletID, err := strconv.ParseInt(chi.URLParam(r, "walletID"), 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	from, err := parseHistoryTime(r.URL.Query().Get("from"))
+	if err != nil {
+		http.Error(w, "from: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	to, err := parseHistoryTime(r.URL.Query().Get("to"))
+	if err != nil {
+		http.Error(w, "to: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	res, err := s.analitics.MinerNonceReplays(r.Context(), walletID, from, to)
+	if err != nil {
+		usecaseError(w, err)
+		return
+	}
+
+	resp := nonceReplaysResponse{
+		WalletID: res.WalletID,
+		From:     res.From.Unix(),
+		To:       res.To.Unix(),
+		Count:    res.Count,
+		Rejected: res.Rejected,
+		Workers:  make([]workerNonceReplays, 0, len(res.Workers)),
+	}
+	for _, c := range res.Workers {
+		resp.Workers = append(resp.Workers, workerNonceReplays{WorkerID: c.WorkerID, Count: c.Count, Rejected: c.Rejected})
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
@@ -4,6 +4,24 @@ func (s *Handler) routes() {
 	s.router.Get("/coin/{coinSymbol}/hashrate", s.coinHashrate)
 	s.router.Get("/wallet/{walletID}/hashrate", s.walletHashrate)
 
+	// История хешрейта (from, to, interval, reward_method)
+	s.router.Get("/coin/{coinSymbol}/hashrate/history", s.coinHashrateHistory)
+	s.router.Get("/wallet/{walletID}/hashrate/history", s.walletHashrateHistory)
+	s.router.Get("/worker/{workerID}/hashrate/history", s.workerHashrateHistory)
+
+	// Повторы nonce воркеров кошелька (from, to)
+	s.router.Get("/wallet/{walletID}/nonce-replays", s.walletNonceReplays)
+
+	// Состояние обработчика Кафки (только для сервисов с JWT токеном)
+	if s.consumer != nil && s.auth != nil {
+		s.router.With(s.serviceAuth).Get("/admin/consumer", s.consumerStatus)
+	}
+
+	// Состояние компонентов сервиса
+	if s.healthChecker != nil {
+		s.router.Get("/health", s.health)
+	}
+
 	// Маршрут для WebSocket
 	s.router.Get("/ws", s.websocketHandler)
 
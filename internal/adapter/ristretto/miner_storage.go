@@ -128,3 +128,27 @@ func (p *RistrettoMinerStorage) GetWorkerIDByName(workerName string, coinID int6
 
 	return newID, nil
 }
+
+// CreateIdentities закэшировать ID кошельков и воркеров пакетом
+// записи без WalletID или WorkerID пропускаются
+func (p *RistrettoMinerStorage) CreateIdentities(identities []entity.Identity) error {
+	for _, ident := range identities {
+		if ident.WalletID > 0 {
+			key, err := p.makeKey(entity.Wallet{CoinID: ident.CoinID, Name: ident.Wallet, RewardMethod: ident.RewardMethod})
+			if err != nil {
+				return err
+			}
+			p.cache.Set(key, ident.WalletID, 1)
+		}
+		if ident.WorkerID > 0 {
+			key, err := p.makeKey(entity.Worker{CoinID: ident.CoinID, Workerfull: ident.Workerfull, RewardMethod: ident.RewardMethod})
+			if err != nil {
+				return err
+			}
+			p.cache.Set(key, ident.WorkerID, 1)
+		}
+	}
+	p.cache.Wait()
+
+	return nil
+}
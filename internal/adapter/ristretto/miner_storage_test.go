@@ -61,3 +61,30 @@ func TestRistrettoMinerStorage(t *testing.T) {
 	require.Equal(t, id, int64(2))
 
 }
+
+func TestRistrettoMinerStorageCreateIdentities(t *testing.T) {
+	storage, err := NewRistrettoMinerStorage()
+	require.NoError(t, err)
+
+	err = storage.CreateIdentities([]entity.Identity{
+		{CoinID: 4, Workerfull: "wallet1.w1", Wallet: "wallet1", Worker: "w1", RewardMethod: "PPLNS", WalletID: 10, WorkerID: 20},
+		{CoinID: 4, Workerfull: "wallet2.w1", Wallet: "wallet2", Worker: "w1", RewardMethod: "PPLNS", WalletID: 11}, // воркер не получен
+	})
+	require.NoError(t, err)
+
+	id, err := storage.GetWalletIDByName("wallet1", 4, "PPLNS")
+	require.NoError(t, err)
+	require.Equal(t, int64(10), id)
+
+	id, err = storage.GetWorkerIDByName("wallet1.w1", 4, "PPLNS")
+	require.NoError(t, err)
+	require.Equal(t, int64(20), id)
+
+	id, err = storage.GetWalletIDByName("wallet2", 4, "PPLNS")
+	require.NoError(t, err)
+	require.Equal(t, int64(11), id)
+
+	id, err = storage.GetWorkerIDByName("wallet2.w1", 4, "PPLNS")
+	require.NoError(t, err)
+	require.Equal(t, int64(0), id)
+}
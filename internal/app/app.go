@@ -3,270 +3,109 @@ package app
 import (
 	"context"
 	"fmt"
-	"log"
-	"net/http"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 	"time"
 
-	"github.com/ClickHouse/clickhouse-go/v2"
-	"github.com/dnsoftware/mpm-miners-processor/pkg/certmanager"
-	jwtauth "github.com/dnsoftware/mpm-miners-processor/pkg/jwt"
-	"github.com/golang-migrate/migrate/v4"
-	"go.opentelemetry.io/otel"
-	"google.golang.org/grpc"
-
-	"github.com/dnsoftware/mpmslib/pkg/servicediscovery"
-
-	"github.com/dnsoftware/mpm-shares-processor/internal/adapter/rest"
-	"github.com/dnsoftware/mpm-shares-processor/internal/usecase/analitics"
-	"github.com/dnsoftware/mpm-shares-processor/pkg/kafka_reader"
+	"github.com/dnsoftware/mpm-shares-processor/config"
+	"github.com/dnsoftware/mpm-shares-processor/internal/constants"
 	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
-	otelpkg "github.com/dnsoftware/mpm-shares-processor/pkg/otel"
 	"github.com/dnsoftware/mpm-shares-processor/pkg/utils"
+)
 
-	"github.com/dnsoftware/mpm-shares-processor/config"
-
-	_ "github.com/golang-migrate/migrate/v4/database/clickhouse"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
-
-	pb "github.com/dnsoftware/mpm-shares-processor/internal/adapter/grpc"
-	"github.com/dnsoftware/mpm-shares-processor/internal/adapter/kafka_consumer/shares"
-	"github.com/dnsoftware/mpm-shares-processor/internal/adapter/ristretto"
-	"github.com/dnsoftware/mpm-shares-processor/internal/constants"
-	clickhouse2 "github.com/dnsoftware/mpm-shares-processor/internal/infrastructure/clickhouse"
-	"github.com/dnsoftware/mpm-shares-processor/internal/usecase/share"
+// Режимы запуска (config.App.Mode)
+const (
+	ModeAll    = config.ModeAll    // все компоненты
+	ModeIngest = config.ModeIngest // только чтение шар из Кафки, без API
+	ModeAPI    = config.ModeAPI    // только REST и gRPC API (аналитика и прием шар по gRPC), без чтения Кафки
 )
 
-type Dependencies struct {
+// ModeComponents компоненты режима запуска (пустой режим - ModeAll)
+func ModeComponents(mode string) ([]string, error) {
+	switch mode {
+	case "", ModeAll:
+		return Components, nil
+	case ModeIngest:
+		return []string{ComponentDiscovery, ComponentTracer, ComponentGRPCClients, ComponentCaches, ComponentClickhouse, ComponentUsecases, ComponentConsumer, ComponentConfigReload}, nil
+	case ModeAPI:
+		return []string{ComponentDiscovery, ComponentTracer, ComponentGRPCClients, ComponentCaches, ComponentClickhouse, ComponentUsecases, ComponentGRPCServer, ComponentREST, ComponentRegistration, ComponentConfigReload}, nil
+	default:
+		return nil, fmt.Errorf("unknown mode %q (expected %s, %s or %s)", mode, ModeIngest, ModeAPI, ModeAll)
+	}
 }
 
-func Run(ctx context.Context, cfg config.Config) (err error) {
-	var deps Dependencies
-	_ = deps
+// App сервис обработки шар, собранный из компонентов
+type App struct {
+	Deps     *Dependencies
+	Registry *Registry
+}
 
+// New сборка сервиса из компонентов names (пусто - все компоненты Components)
+// компоненты запускаются в порядке Components независимо от порядка names
+func New(cfg config.Config, names ...string) (*App, error) {
 	basePath, err := utils.GetProjectRoot(constants.ProjectRootAnchorFile)
 	if err != nil {
-		log.Fatalf("GetProjectRoot failed (app.Run): %s", err.Error())
-	}
-
-	etcdConf, err := servicediscovery.NewEtcdConfig(servicediscovery.EtcdConfig{
-		Nodes:       strings.Split(cfg.EtcdConfig.Endpoints, ","),
-		Username:    cfg.EtcdConfig.Username,
-		Password:    cfg.EtcdConfig.Password,
-		CertCaPath:  basePath + constants.CaPath,
-		CertPath:    basePath + constants.PublicPath,
-		CertKeyPath: basePath + constants.PrivatePath,
-	})
-	if err != nil {
-		log.Fatalf("NewEtcdConfig error: %s", err.Error())
-	}
-
-	serviceKey := cfg.App.AppID + ":" + constants.ApiBaseUrlGrpc
-	serviceAddr := cfg.ApiBaseUrls.Grps
-	sd, err := servicediscovery.NewServiceDiscovery(*etcdConf, constants.ServiceDiscoveryPath, serviceKey, serviceAddr, 5, 10)
-	if err != nil {
-		log.Fatalf("NewServiceDiscovery error: %s", err.Error())
-	}
-
-	err = sd.RegisterService(cfg.App.AppID+":"+constants.ApiBaseUrlRest, cfg.ApiBaseUrls.Rest)
-	if err != nil {
-		log.Fatalf("Rest service register error: %s", err.Error())
-	}
-
-	sd.WaitDependencies(cfg.App.Dependencies)
-	cfg.App.ServiceDiscoveryList, err = sd.DiscoverAllServices()
-	if err != nil {
-		log.Fatalf("DiscoverAllServices error: %s", err.Error())
-	}
-	logger.Log().Info("All services discovered")
-
-	// инициализируем BaseURLs доступа к API внешних сервисов
-	cfg.GRPC.CoinTarget = cfg.App.ServiceDiscoveryList[cfg.GRPC.CoinTarget]
-	cfg.GRPC.MinerTarget = cfg.App.ServiceDiscoveryList[cfg.GRPC.MinerTarget]
-
-	// Инициализация трассировщика
-	otelConfig := otelpkg.Config{
-		ServiceName:        cfg.App.Name,
-		CollectorEndpoint:  cfg.Otel.Endpoint,
-		BatchTimeout:       cfg.Otel.BatchTimeout * time.Second,
-		MaxExportBatchSize: cfg.Otel.MaxExportBatchSize,
-		MaxQueueSize:       cfg.Otel.MaxQueueSize,
-	}
-	_ = otelpkg.InitTracer(otelConfig)
-	//defer cleanup()
-	tracer := otel.Tracer("share-trace")
-	_ = tracer
-
-	// Для работы с JWT токенами
-	jwt := jwtauth.NewJWTServiceSymmetric(cfg.Auth.JWTServiceName, cfg.Auth.JWTValidServices, cfg.Auth.JWTSecret, 60)
-
-	// Полномочия для TLS соединения
-	certMan, err := certmanager.NewCertManager(basePath + "/certs")
-	clientCreds, err := certMan.GetClientCredentials()
-
-	// Клиентское GRPC соединение
-	conn, err := grpc.DialContext(ctx,
-		cfg.GRPC.CoinTarget, // Адрес:порт
-		//grpc.WithTransportCredentials(insecure.NewCredentials()), // Отключаем TLS
-		grpc.WithTransportCredentials(*clientCreds), // Включаем TLS
-		grpc.WithUnaryInterceptor(jwt.GetClientInterceptor()),
-	)
-	if err != nil {
-		logger.Log().Fatal("DialContext error: " + err.Error())
+		return nil, fmt.Errorf("GetProjectRoot: %w", err)
 	}
 
-	// Ristretto кэш
-	cacheCoin, err := ristretto.NewRistrettoCoinStorage()
-	if err != nil {
-		logger.Log().Fatal("NewRistrettoCoinStorage error: " + err.Error())
-	}
-	cacheMiner, err := ristretto.NewRistrettoMinerStorage()
-	if err != nil {
-		logger.Log().Fatal("NewRistrettoMinerStorage error: " + err.Error())
-	}
-
-	// remote API miners processor
-	coinStorage, err := pb.NewCoinStorage(conn)
-	if err != nil {
-		logger.Log().Fatal("NewCoinStorage error: " + err.Error())
+	if len(names) == 0 {
+		names = Components
 	}
-
-	minerStorage, err := pb.NewMinerStorage(conn)
-	if err != nil {
-		logger.Log().Fatal("NewMinerStorage error: " + err.Error())
+	selected := make(map[string]bool, len(names))
+	for _, name := range names {
+		selected[name] = true
 	}
 
-	////***** Remote ClickHouse shares timeseries
-	//connShares, err := grpc.DialContext(ctx,
-	//	cfg.GRPC.SharesTarget, // Адрес:порт
-	//	//grpc.WithTransportCredentials(insecure.NewCredentials()), // Отключаем TLS
-	//	grpc.WithTransportCredentials(*clientCreds), // Включаем TLS
-	//	grpc.WithUnaryInterceptor(jwt.GetClientInterceptor()),
-	//)
-	//if err != nil {
-	//	logger.Log().Fatal("Remote ClickHouse DialContext error: " + err.Error())
-	//
-	//}
+	deps := &Dependencies{Config: cfg, BasePath: basePath, components: make(map[string]bool)}
+	registry := NewRegistry(cfg.App.ShutdownTimeout * time.Second)
+	deps.registry = registry
 
-	// Подключение к базе данных ClickHouse
-	connCH, err := clickhouse.Open(&clickhouse.Options{
-		Addr: cfg.Clickhouse.Addr,
-		Auth: clickhouse.Auth{
-			Database: "default",
-			Username: cfg.Clickhouse.Username,
-			Password: cfg.Clickhouse.Password,
-		},
-		Settings: clickhouse.Settings{
-			"max_execution_time": 60,
-		},
-		Debug: true,
-	})
-	if err != nil {
-		logger.Log().Fatal(err.Error())
-	}
+	for _, name := range Components {
+		if !selected[name] {
+			continue
+		}
+		delete(selected, name)
+		deps.components[name] = true
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	// Проверка подключения
-	err = connCH.Ping(ctx)
-	if err != nil {
-		logger.Log().Fatal(err.Error())
+		c, err := deps.newComponent(name)
+		if err != nil {
+			return nil, err
+		}
+		registry.Register(c)
 	}
-
-	// Укажите путь к миграциям и строку подключения к базе данных
-	dsn := fmt.Sprintf("clickhouse://%s:%s@%s/%s", "default", "", cfg.Clickhouse.Addr[0], "default")
-	m, err := migrate.New(
-		"file://"+basePath+"/"+constants.MigrationDir,
-		dsn,
-	)
-	if err != nil {
-		logger.Log().Fatal(err.Error())
+	for name := range selected {
+		return nil, fmt.Errorf("unknown component %q", name)
 	}
 
-	// Сброс миграций
-	m.Force(-1)
-
-	// Применить миграции
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		logger.Log().Fatal(err.Error())
-	}
-	log.Println("Миграции успешно применены")
-
-	connCH.Close()
-
-	// Подключаемся к mpmhouse
-	connCH, err = clickhouse2.NewClickhouseConnect(clickhouse2.Config{
-		Addr:             cfg.Clickhouse.Addr,
-		Database:         cfg.Clickhouse.Database,
-		Username:         cfg.Clickhouse.Username,
-		Password:         cfg.Clickhouse.Password,
-		MaxExecutionTime: 10,
-	})
-	if err != nil {
-		logger.Log().Fatal(err.Error())
-	}
-	defer connCH.Close()
+	return &App{Deps: deps, Registry: registry}, nil
+}
 
-	// Проверка подключения
-	err = connCH.Ping(ctx)
+// Run запуск сервиса в режиме cfg.App.Mode до сигнала остановки или завершения ctx
+// компоненты останавливаются в обратном порядке, ошибка остановки возвращается вызывающему
+func Run(ctx context.Context, cfg config.Config) error {
+	components, err := ModeComponents(cfg.App.Mode)
 	if err != nil {
-		logger.Log().Fatal(err.Error())
+		return err
 	}
 
-	cfgStore := clickhouse2.ShareStorageConfig{
-		Conn:        connCH,
-		ClusterName: "clickhouse_cluster",
-		Database:    "mpmhouse",
-	}
-	shareStorage, err := clickhouse2.NewClickhouseShareStorage(cfgStore)
+	application, err := New(cfg, components...)
 	if err != nil {
-		logger.Log().Fatal("NewShareStorage error: " + err.Error())
+		return err
 	}
 
-	usecase := share.NewShareUseCase(shareStorage, minerStorage, coinStorage, cacheMiner, cacheCoin)
-	analiticsUsecase := analitics.NewAnaliticsUsecase(shareStorage)
+	// Сигнал остановки отслеживаем с начала запуска: он прерывает и ожидание зависимостей
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	// http сервер
-	httpHandler := rest.NewHandler(analiticsUsecase)
-	go func() {
-		http.ListenAndServe(cfg.ApiBaseUrls.Rest, httpHandler.Routes())
-	}()
-
-	// Вычитываем сообщения из Кафки
-	cfgReader := kafka_reader.Config{
-		Brokers:            cfg.KafkaShareReader.Brokers,
-		Group:              cfg.KafkaShareReader.Group,
-		Topic:              cfg.KafkaShareReader.Topic,
-		AutoCommitInterval: constants.KafkaSharesAutocommitInterval,
-		AutoCommitEnable:   true,
+	if err := application.Registry.Start(ctx); err != nil {
+		return err
 	}
+	logger.Log().Info(fmt.Sprintf("Service started in %q mode", cfg.App.Mode))
 
-	reader, err := kafka_reader.NewKafkaReader(cfgReader, logger.Log())
-
-	cfgConsumer := shares.Config{
-		BatchSize:     cfg.KafkaShareReader.ReadBatchSize,
-		FlushInterval: cfg.KafkaShareReader.ReadFlushInterval,
-	}
-
-	consumer, err := shares.NewShareConsumer(cfgConsumer, reader, usecase)
-	if err != nil {
-		logger.Log().Fatal("NewShareConsumer error: " + err.Error())
-	}
-	defer consumer.Close()
-
-	// Стартуем вычитывание сообщений
-	consumer.StartConsume()
-
-	// Настройка graceful shutdown
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
-
-	<-quit
-	log.Println("Shutting down service...")
+	// Ожидаем сигнала остановки или завершения внешнего контекста
+	<-ctx.Done()
+	logger.Log().Info("Shutting down service...")
 
-	return nil
+	return application.Registry.Stop()
 }
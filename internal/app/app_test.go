@@ -0,0 +1,118 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnsoftware/mpm-shares-processor/config"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+)
+
+func TestModeComponents(t *testing.T) {
+	all, err := ModeComponents("")
+	require.NoError(t, err)
+	require.Equal(t, Components, all)
+
+	ingest, err := ModeComponents(ModeIngest)
+	require.NoError(t, err)
+	require.Contains(t, ingest, ComponentConsumer)
+	require.NotContains(t, ingest, ComponentREST)
+	require.NotContains(t, ingest, ComponentGRPCServer)
+	require.NotContains(t, ingest, ComponentRegistration)
+
+	api, err := ModeComponents(ModeAPI)
+	require.NoError(t, err)
+	require.Contains(t, api, ComponentREST)
+	require.NotContains(t, api, ComponentConsumer)
+
+	_, err = ModeComponents("analytics")
+	require.Error(t, err)
+}
+
+func TestDiscoveryServices(t *testing.T) {
+	var cfg config.Config
+	cfg.App.AppID = "shares-1"
+	cfg.ApiBaseUrls.Grps = "127.0.0.1:7000"
+	cfg.ApiBaseUrls.Rest = "127.0.0.1:8000"
+
+	deps := &Dependencies{Config: cfg, components: map[string]bool{ComponentGRPCServer: true, ComponentREST: true}}
+	require.Equal(t, []discoveryService{
+		{key: "shares-1:grpc", addr: "127.0.0.1:7000"},
+		{key: "shares-1:rest", addr: "127.0.0.1:8000"},
+	}, deps.discoveryServices())
+
+	// экземпляр только с чтением Кафки не регистрируется: адреса нет, клиенты его не используют
+	deps = &Dependencies{Config: cfg, components: map[string]bool{ComponentConsumer: true}}
+	require.Empty(t, deps.discoveryServices())
+
+	// служебные команды не регистрируются
+	deps = &Dependencies{Config: cfg, components: map[string]bool{ComponentUsecases: true}}
+	require.Empty(t, deps.discoveryServices())
+}
+
+func TestStartStaticDiscovery(t *testing.T) {
+	logger.InitLogger(logger.LogLevelProduction, t.TempDir()+"/test.log")
+
+	var cfg config.Config
+	cfg.App.AppID = "shares-1"
+	cfg.App.Standalone = true
+	cfg.App.Dependencies = []string{"miners_processor:grpc"}
+	cfg.ApiBaseUrls.Grps = "127.0.0.1:7000"
+	cfg.GRPC.CoinTarget = "miners_processor:grpc"
+	cfg.GRPC.MinerTarget = "miners_processor:grpc"
+	cfg.GRPC.CoinAddr = "127.0.0.1:7878"
+	cfg.GRPC.MinerAddr = "127.0.0.1:7878"
+
+	deps := &Dependencies{Config: cfg, components: map[string]bool{ComponentGRPCServer: true}}
+	require.NoError(t, deps.startDiscovery(context.Background()))
+	require.Nil(t, deps.EtcdClient)
+	require.Equal(t, map[string]string{"miners_processor:grpc": "127.0.0.1:7878"}, deps.Config.App.ServiceDiscoveryList)
+
+	// API экземпляра регистрируется отдельным компонентом после запуска серверов
+	require.NoError(t, deps.startRegistration(context.Background()))
+	services, err := deps.ServiceDiscovery.DiscoverAllServices()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"miners_processor:grpc": "127.0.0.1:7878",
+		"shares-1:grpc":         "127.0.0.1:7000",
+	}, services)
+
+	require.NoError(t, deps.discoveryHealth(context.Background()))
+	require.NoError(t, deps.stopRegistration(context.Background()))
+	require.NoError(t, deps.stopDiscovery(context.Background()))
+}
+
+func TestWaitDependencies(t *testing.T) {
+	logger.InitLogger(logger.LogLevelProduction, t.TempDir()+"/test.log")
+	interval := dependencyPollInterval
+	dependencyPollInterval = 10 * time.Millisecond
+	defer func() { dependencyPollInterval = interval }()
+
+	// зависимость появляется на третьем опросе
+	calls := 0
+	discover := func(ctx context.Context) (map[string]string, error) {
+		calls++
+		switch {
+		case calls == 1:
+			return nil, errors.New("etcd unavailable")
+		case calls < 3:
+			return map[string]string{}, nil
+		}
+		return map[string]string{"miners_processor:grpc": "127.0.0.1:7878"}, nil
+	}
+	services, err := waitDependencies(context.Background(), discover, []string{"miners_processor:grpc"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"miners_processor:grpc": "127.0.0.1:7878"}, services)
+	require.Equal(t, 3, calls)
+
+	// зависимости нет: ожидание прерывается отменой контекста (сигналом остановки)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	never := func(ctx context.Context) (map[string]string, error) { return map[string]string{}, nil }
+	_, err = waitDependencies(ctx, never, []string{"miners_processor:grpc"})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
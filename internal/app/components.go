@@ -0,0 +1,819 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/dnsoftware/mpm-miners-processor/pkg/certmanager"
+	jwtauth "github.com/dnsoftware/mpm-miners-processor/pkg/jwt"
+	"github.com/dnsoftware/mpmslib/pkg/servicediscovery"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/dnsoftware/mpm-shares-processor/config"
+	pb "github.com/dnsoftware/mpm-shares-processor/internal/adapter/grpc"
+	"github.com/dnsoftware/mpm-shares-processor/internal/adapter/grpc/proto"
+	"github.com/dnsoftware/mpm-shares-processor/internal/adapter/kafka_consumer/shares"
+	"github.com/dnsoftware/mpm-shares-processor/internal/adapter/rest"
+	"github.com/dnsoftware/mpm-shares-processor/internal/adapter/ristretto"
+	"github.com/dnsoftware/mpm-shares-processor/internal/constants"
+	clickhouse2 "github.com/dnsoftware/mpm-shares-processor/internal/infrastructure/clickhouse"
+	"github.com/dnsoftware/mpm-shares-processor/internal/infrastructure/discovery"
+	"github.com/dnsoftware/mpm-shares-processor/internal/infrastructure/spill"
+	"github.com/dnsoftware/mpm-shares-processor/internal/usecase/analitics"
+	"github.com/dnsoftware/mpm-shares-processor/internal/usecase/share"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/etcd"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/kafka_reader"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/kafka_writer"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+	otelpkg "github.com/dnsoftware/mpm-shares-processor/pkg/otel"
+)
+
+// Имена компонентов сервиса в порядке запуска
+const (
+	ComponentDiscovery    = "discovery"     // клиент etcd, резолвер адресов и ожидание зависимостей
+	ComponentTracer       = "tracer"        // трассировка OpenTelemetry
+	ComponentGRPCClients  = "grpc_clients"  // соединения с сервисами монет и майнеров
+	ComponentCaches       = "caches"        // кэши монет и майнеров
+	ComponentClickhouse   = "clickhouse"    // хранилище шар, буфер на диске
+	ComponentUsecases     = "usecases"      // нормализация шар и аналитика
+	ComponentConsumer     = "consumer"      // чтение шар из Кафки
+	ComponentGRPCServer   = "grpc_server"   // gRPC API аналитики и приема шар
+	ComponentREST         = "rest"          // REST API аналитики и состояния
+	ComponentRegistration = "registration"  // регистрация API экземпляра в service discovery (после запуска серверов)
+	ComponentConfigReload = "config_reload" // обновление конфига из etcd на ходу
+)
+
+// Components все компоненты сервиса в порядке запуска
+var Components = []string{
+	ComponentDiscovery,
+	ComponentTracer,
+	ComponentGRPCClients,
+	ComponentCaches,
+	ComponentClickhouse,
+	ComponentUsecases,
+	ComponentConsumer,
+	ComponentGRPCServer,
+	ComponentREST,
+	ComponentRegistration,
+	ComponentConfigReload,
+}
+
+// Dependencies объекты, создаваемые компонентами при запуске
+// компонент заполняет свои поля и использует поля компонентов, запущенных раньше
+type Dependencies struct {
+	Config   config.Config
+	BasePath string
+
+	ServiceDiscovery ServiceRegistry
+	EtcdClient       *clientv3.Client      // nil в автономном режиме
+	Resolver         *etcd.ResolverBuilder // nil в автономном режиме
+
+	TracerShutdown func(ctx context.Context) error
+
+	JWT          *jwtauth.ServiceSymmetric
+	CertManager  *certmanager.CertManager
+	CoinConn     *grpc.ClientConn
+	MinerConn    *grpc.ClientConn
+	CoinStorage  *pb.GRPCCoinStorage
+	MinerStorage *pb.GRPCMinerStorage
+
+	CoinCache  *ristretto.RistrettoCoinStorage
+	MinerCache *ristretto.RistrettoMinerStorage
+
+	ClickhouseConn driver.Conn
+	ShareStorage   *clickhouse2.ClickhouseShareStorage
+	SpillStorage   *spill.BufferedShareStorage // nil - буфер на диске не используется
+
+	ShareUseCase     *share.ShareUseCase
+	AnaliticsUseCase *analitics.AnaliticsUsecase
+
+	Consumer         *shares.ShareConsumer
+	DeadLetterWriter *kafka_writer.KafkaWriter // nil - dead-letter топик не используется
+
+	GRPCServer *grpc.Server
+	HTTPServer *http.Server
+
+	registry   *Registry
+	components map[string]bool // компоненты, из которых собран сервис
+
+	remoteConfig config.Config      // конфиг из etcd с примененными на ходу изменениями
+	reloadCancel context.CancelFunc // остановка отслеживания конфига
+	reloadDone   chan struct{}
+}
+
+// ServiceRegistry регистрация экземпляра и поиск сервисов
+// servicediscovery.ServiceDiscovery (etcd) или discovery.StaticRegistry (автономный режим)
+type ServiceRegistry interface {
+	RegisterService(serviceKey, serviceAddr string) error
+	DiscoverAllServices() (map[string]string, error)
+	Close()
+}
+
+// newComponent компонент по имени
+func (d *Dependencies) newComponent(name string) (Component, error) {
+	switch name {
+	case ComponentDiscovery:
+		return &component{name: name, start: d.startDiscovery, stop: d.stopDiscovery, health: d.discoveryHealth}, nil
+	case ComponentTracer:
+		return &component{name: name, start: d.startTracer, stop: d.stopTracer}, nil
+	case ComponentGRPCClients:
+		return &component{name: name, start: d.startGRPCClients, stop: d.stopGRPCClients, health: d.grpcClientsHealth}, nil
+	case ComponentCaches:
+		return &component{name: name, start: d.startCaches}, nil
+	case ComponentClickhouse:
+		return &component{name: name, start: d.startClickhouse, stop: d.stopClickhouse, health: d.clickhouseHealth}, nil
+	case ComponentUsecases:
+		return &component{name: name, start: d.startUsecases}, nil
+	case ComponentConsumer:
+		return &component{name: name, start: d.startConsumer, stop: d.stopConsumer, health: d.consumerHealth}, nil
+	case ComponentGRPCServer:
+		return &component{name: name, start: d.startGRPCServer, stop: d.stopGRPCServer}, nil
+	case ComponentREST:
+		return &component{name: name, start: d.startREST, stop: d.stopREST}, nil
+	case ComponentRegistration:
+		return &component{name: name, start: d.startRegistration, stop: d.stopRegistration}, nil
+	case ComponentConfigReload:
+		return &component{name: name, start: d.startConfigReload, stop: d.stopConfigReload}, nil
+	default:
+		return nil, fmt.Errorf("unknown component %q", name)
+	}
+}
+
+// requires ошибка, если компонент запущен без нужных ему компонентов
+func requires(ok bool, component string) error {
+	if !ok {
+		return fmt.Errorf("component %s is required", component)
+	}
+	return nil
+}
+
+//*** service discovery
+
+// dependencyPollInterval интервал проверки появления зависимостей в service discovery
+var dependencyPollInterval = 2 * time.Second
+
+// startDiscovery клиент etcd, резолвер адресов gRPC сервисов и ожидание зависимостей (до отмены ctx)
+// API экземпляра регистрируется отдельно, после запуска серверов (startRegistration)
+func (d *Dependencies) startDiscovery(ctx context.Context) error {
+	if d.Config.App.Standalone {
+		return d.startStaticDiscovery()
+	}
+	cfg := d.Config
+
+	// Резолвер адресов внешних gRPC сервисов по service discovery (адреса отслеживаются по мере перемещения экземпляров)
+	var err error
+	d.EtcdClient, err = etcd.NewEtcdClient(etcd.EtcdConfig{
+		Nodes:       strings.Split(cfg.EtcdConfig.Endpoints, ","),
+		Username:    cfg.EtcdConfig.Username,
+		Password:    cfg.EtcdConfig.Password,
+		CertCaPath:  d.BasePath + constants.CaPath,
+		CertPath:    d.BasePath + constants.PublicPath,
+		CertKeyPath: d.BasePath + constants.PrivatePath,
+	})
+	if err != nil {
+		return fmt.Errorf("NewEtcdClient: %w", err)
+	}
+	d.Resolver = etcd.NewResolverBuilder(d.EtcdClient, constants.ServiceDiscoveryPath)
+
+	d.Config.App.ServiceDiscoveryList, err = waitDependencies(ctx, d.discoverServices, cfg.App.Dependencies)
+	if err != nil {
+		return err
+	}
+	logger.Log().Info("All services discovered")
+
+	return nil
+}
+
+// discoverServices сервисы, зарегистрированные в service discovery (ключ сервиса -> хост:порт)
+func (d *Dependencies) discoverServices(ctx context.Context) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, constants.ContextTimeout*time.Second)
+	defer cancel()
+
+	prefix := constants.ServiceDiscoveryPath + "/"
+	resp, err := d.EtcdClient.Get(ctx, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	services := make(map[string]string, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		services[strings.TrimPrefix(string(kv.Key), prefix)] = string(kv.Value)
+	}
+
+	return services, nil
+}
+
+// waitDependencies ожидание регистрации зависимостей в service discovery, прерывается отменой ctx (сигналом остановки)
+// возвращает все найденные сервисы
+func waitDependencies(ctx context.Context, discover func(ctx context.Context) (map[string]string, error), dependencies []string) (map[string]string, error) {
+	for {
+		services, err := discover(ctx)
+		if err != nil {
+			logger.Log().Warn("Discover services error: " + err.Error())
+		} else {
+			var missing []string
+			for _, dep := range dependencies {
+				if _, ok := services[dep]; !ok {
+					missing = append(missing, dep)
+				}
+			}
+			if len(missing) == 0 {
+				return services, nil
+			}
+			logger.Log().Warn("Waiting for services: " + strings.Join(missing, ", "))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait dependencies: %w", ctx.Err())
+		case <-time.After(dependencyPollInterval):
+		}
+	}
+}
+
+// startStaticDiscovery статический реестр автономного режима: адреса сервисов монет и майнеров из GRPCConfig, без etcd
+func (d *Dependencies) startStaticDiscovery() error {
+	cfg := d.Config
+
+	static := make(map[string]string)
+	if cfg.GRPC.CoinAddr != "" {
+		static[cfg.GRPC.CoinTarget] = cfg.GRPC.CoinAddr
+	}
+	if cfg.GRPC.MinerAddr != "" {
+		static[cfg.GRPC.MinerTarget] = cfg.GRPC.MinerAddr
+	}
+	registry := discovery.NewStaticRegistry(static)
+	d.ServiceDiscovery = registry
+
+	registry.WaitDependencies(cfg.App.Dependencies)
+	d.Config.App.ServiceDiscoveryList, _ = registry.DiscoverAllServices()
+	logger.Log().Info("Standalone mode, static service registry is used")
+
+	return nil
+}
+
+// stopDiscovery закрытие клиента etcd
+func (d *Dependencies) stopDiscovery(ctx context.Context) error {
+	if d.EtcdClient != nil {
+		return d.EtcdClient.Close()
+	}
+
+	return nil
+}
+
+func (d *Dependencies) discoveryHealth(ctx context.Context) error {
+	if d.EtcdClient == nil {
+		return nil // автономный режим
+	}
+	_, err := d.EtcdClient.Get(ctx, constants.ServiceDiscoveryPath, clientv3.WithPrefix(), clientv3.WithCountOnly())
+	return err
+}
+
+//*** регистрация в service discovery
+
+// startRegistration регистрация API экземпляра в service discovery
+// запускается после серверов: клиенты получают адрес, когда он уже принимает соединения
+// экземпляры без API (чтение Кафки, служебные команды) не регистрируются
+func (d *Dependencies) startRegistration(ctx context.Context) error {
+	services := d.discoveryServices()
+	if len(services) == 0 {
+		return nil
+	}
+
+	if d.Config.App.Standalone {
+		if err := requires(d.ServiceDiscovery != nil, ComponentDiscovery); err != nil {
+			return err
+		}
+		for _, service := range services {
+			if err := d.ServiceDiscovery.RegisterService(service.key, service.addr); err != nil {
+				return fmt.Errorf("%s service register: %w", service.key, err)
+			}
+		}
+		return nil
+	}
+
+	return d.registerServices(services)
+}
+
+// registerServices регистрация API экземпляра в service discovery (etcd)
+func (d *Dependencies) registerServices(services []discoveryService) error {
+	cfg := d.Config
+
+	etcdConf, err := servicediscovery.NewEtcdConfig(servicediscovery.EtcdConfig{
+		Nodes:       strings.Split(cfg.EtcdConfig.Endpoints, ","),
+		Username:    cfg.EtcdConfig.Username,
+		Password:    cfg.EtcdConfig.Password,
+		CertCaPath:  d.BasePath + constants.CaPath,
+		CertPath:    d.BasePath + constants.PublicPath,
+		CertKeyPath: d.BasePath + constants.PrivatePath,
+	})
+	if err != nil {
+		return fmt.Errorf("NewEtcdConfig: %w", err)
+	}
+
+	d.ServiceDiscovery, err = servicediscovery.NewServiceDiscovery(*etcdConf, constants.ServiceDiscoveryPath, services[0].key, services[0].addr, 5, 10)
+	if err != nil {
+		return fmt.Errorf("NewServiceDiscovery: %w", err)
+	}
+	for _, service := range services[1:] {
+		if err := d.ServiceDiscovery.RegisterService(service.key, service.addr); err != nil {
+			return fmt.Errorf("%s service register: %w", service.key, err)
+		}
+	}
+
+	return nil
+}
+
+type discoveryService struct {
+	key  string
+	addr string
+}
+
+// discoveryServices ключи и адреса API экземпляра для service discovery
+// экземпляр без API (только чтение Кафки) и служебные команды не регистрируются: адреса у них нет
+func (d *Dependencies) discoveryServices() []discoveryService {
+	cfg := d.Config
+
+	var services []discoveryService
+	if d.components[ComponentGRPCServer] && cfg.ApiBaseUrls.Grps != "" {
+		services = append(services, discoveryService{key: cfg.App.AppID + ":" + constants.ApiBaseUrlGrpc, addr: cfg.ApiBaseUrls.Grps})
+	}
+	if d.components[ComponentREST] && cfg.ApiBaseUrls.Rest != "" {
+		services = append(services, discoveryService{key: cfg.App.AppID + ":" + constants.ApiBaseUrlRest, addr: cfg.ApiBaseUrls.Rest})
+	}
+
+	return services
+}
+
+// stopRegistration снятие регистрации (отзыв аренды etcd) до остановки серверов
+func (d *Dependencies) stopRegistration(ctx context.Context) error {
+	if d.ServiceDiscovery != nil {
+		d.ServiceDiscovery.Close()
+	}
+
+	return nil
+}
+
+//*** трассировка
+
+func (d *Dependencies) startTracer(ctx context.Context) error {
+	d.TracerShutdown = otelpkg.InitTracerProvider(otelpkg.Config{
+		ServiceName:        d.Config.App.Name,
+		CollectorEndpoint:  d.Config.Otel.Endpoint,
+		BatchTimeout:       d.Config.Otel.BatchTimeout * time.Second,
+		MaxExportBatchSize: d.Config.Otel.MaxExportBatchSize,
+		MaxQueueSize:       d.Config.Otel.MaxQueueSize,
+	})
+
+	return nil
+}
+
+// stopTracer отправка накопленных спанов
+func (d *Dependencies) stopTracer(ctx context.Context) error {
+	if d.TracerShutdown == nil {
+		return nil
+	}
+	return d.TracerShutdown(ctx)
+}
+
+//*** клиентские gRPC соединения
+
+func (d *Dependencies) startGRPCClients(ctx context.Context) error {
+	cfg := d.Config
+	if err := requires(d.Resolver != nil || cfg.App.Standalone, ComponentDiscovery); err != nil {
+		return err
+	}
+
+	// Для работы с JWT токенами
+	d.JWT = jwtauth.NewJWTServiceSymmetric(cfg.Auth.JWTServiceName, cfg.Auth.JWTValidServices, cfg.Auth.JWTSecret, 60)
+
+	// Полномочия для TLS соединения (TLS отключается только в автономном режиме с GRPC.Insecure)
+	var err error
+	transportCreds := insecure.NewCredentials()
+	if !d.grpcInsecure() {
+		d.CertManager, err = certmanager.NewCertManager(d.BasePath + "/certs")
+		if err != nil {
+			return fmt.Errorf("NewCertManager: %w", err)
+		}
+		clientCreds, err := d.CertManager.GetClientCredentials()
+		if err != nil {
+			return fmt.Errorf("GetClientCredentials: %w", err)
+		}
+		transportCreds = *clientCreds
+	}
+
+	// Клиентские GRPC соединения (отдельно к сервису монет и к сервису майнеров)
+	// адрес - ключ сервиса в service discovery, запросы распределяются между всеми его экземплярами,
+	// в автономном режиме - статический адрес из GRPCConfig
+	dialOptions := []grpc.DialOption{
+		grpc.WithTransportCredentials(transportCreds),
+		grpc.WithUnaryInterceptor(d.JWT.GetClientInterceptor()),
+	}
+	coinTarget, minerTarget := cfg.GRPC.CoinAddr, cfg.GRPC.MinerAddr
+	if !cfg.App.Standalone {
+		dialOptions = append(dialOptions, grpc.WithResolvers(d.Resolver), grpc.WithDefaultServiceConfig(etcd.RoundRobinServiceConfig))
+		coinTarget = etcd.ResolverScheme + ":///" + cfg.GRPC.CoinTarget
+		minerTarget = etcd.ResolverScheme + ":///" + cfg.GRPC.MinerTarget
+	}
+
+	d.CoinConn, err = grpc.DialContext(ctx, coinTarget, dialOptions...)
+	if err != nil {
+		return fmt.Errorf("coin DialContext: %w", err)
+	}
+	d.MinerConn, err = grpc.DialContext(ctx, minerTarget, dialOptions...)
+	if err != nil {
+		return fmt.Errorf("miner DialContext: %w", err)
+	}
+
+	// remote API miners processor
+	d.CoinStorage, err = pb.NewCoinStorage(d.CoinConn)
+	if err != nil {
+		return fmt.Errorf("NewCoinStorage: %w", err)
+	}
+	d.MinerStorage, err = pb.NewMinerStorage(d.MinerConn)
+	if err != nil {
+		return fmt.Errorf("NewMinerStorage: %w", err)
+	}
+
+	return nil
+}
+
+// grpcInsecure gRPC без TLS: GRPC.Insecure учитывается только в автономном режиме
+func (d *Dependencies) grpcInsecure() bool {
+	return d.Config.GRPC.Insecure && d.Config.App.Standalone
+}
+
+func (d *Dependencies) stopGRPCClients(ctx context.Context) error {
+	var errs []error
+	for _, conn := range []*grpc.ClientConn{d.CoinConn, d.MinerConn} {
+		if conn != nil {
+			errs = append(errs, conn.Close())
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (d *Dependencies) grpcClientsHealth(ctx context.Context) error {
+	for target, conn := range map[string]*grpc.ClientConn{d.Config.GRPC.CoinTarget: d.CoinConn, d.Config.GRPC.MinerTarget: d.MinerConn} {
+		if state := conn.GetState(); state == connectivity.TransientFailure || state == connectivity.Shutdown {
+			return fmt.Errorf("%s: %s", target, state)
+		}
+	}
+
+	return nil
+}
+
+//*** кэши
+
+func (d *Dependencies) startCaches(ctx context.Context) error {
+	var err error
+	d.CoinCache, err = ristretto.NewRistrettoCoinStorage()
+	if err != nil {
+		return fmt.Errorf("NewRistrettoCoinStorage: %w", err)
+	}
+	d.MinerCache, err = ristretto.NewRistrettoMinerStorage()
+	if err != nil {
+		return fmt.Errorf("NewRistrettoMinerStorage: %w", err)
+	}
+
+	return nil
+}
+
+//*** ClickHouse
+
+func (d *Dependencies) startClickhouse(ctx context.Context) error {
+	cfg := d.Config
+
+	// Подключаемся к базе шар (схема создается командой migrate up до запуска сервиса)
+	var err error
+	d.ClickhouseConn, err = clickhouse2.NewClickhouseConnect(clickhouse2.Config{
+		Addr:             cfg.Clickhouse.Addr,
+		Database:         cfg.Clickhouse.Database,
+		Username:         cfg.Clickhouse.Username,
+		Password:         cfg.Clickhouse.Password,
+		MaxExecutionTime: 10,
+	})
+	if err != nil {
+		return fmt.Errorf("NewClickhouseConnect: %w", err)
+	}
+
+	// Проверка подключения
+	pingCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := d.ClickhouseConn.Ping(pingCtx); err != nil {
+		return fmt.Errorf("ping: %w", err)
+	}
+
+	d.ShareStorage, err = clickhouse2.NewClickhouseShareStorage(clickhouse2.ShareStorageConfig{
+		Conn:                d.ClickhouseConn,
+		ClusterName:         cfg.Clickhouse.Cluster,
+		Database:            cfg.Clickhouse.Database,
+		InsertDeduplication: cfg.Clickhouse.InsertDeduplication,
+	})
+	if err != nil {
+		return fmt.Errorf("NewShareStorage: %w", err)
+	}
+
+	// Буфер на диске на время недоступности ClickHouse
+	if cfg.Spill.Dir != "" {
+		d.SpillStorage, err = spill.NewBufferedShareStorage(spill.Config{
+			Dir:           cfg.Spill.Dir,
+			MaxBytes:      cfg.Spill.MaxSizeMB << 20,
+			DrainInterval: cfg.Spill.DrainInterval * time.Second,
+		}, d.ShareStorage)
+		if err != nil {
+			return fmt.Errorf("NewBufferedShareStorage: %w", err)
+		}
+		d.SpillStorage.Start()
+	}
+
+	return nil
+}
+
+// stopClickhouse остановка вычитки буфера и закрытие соединения
+func (d *Dependencies) stopClickhouse(ctx context.Context) error {
+	var errs []error
+	if d.SpillStorage != nil {
+		errs = append(errs, d.SpillStorage.Close())
+	}
+	if d.ClickhouseConn != nil {
+		errs = append(errs, d.ClickhouseConn.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+func (d *Dependencies) clickhouseHealth(ctx context.Context) error {
+	return d.ClickhouseConn.Ping(ctx)
+}
+
+//*** usecases
+
+func (d *Dependencies) startUsecases(ctx context.Context) error {
+	if err := requires(d.ShareStorage != nil, ComponentClickhouse); err != nil {
+		return err
+	}
+	if err := requires(d.CoinStorage != nil, ComponentGRPCClients); err != nil {
+		return err
+	}
+	if err := requires(d.CoinCache != nil, ComponentCaches); err != nil {
+		return err
+	}
+	cfg := d.Config
+
+	// Повторы nonce воркеров
+	var nonceReplay *share.NonceReplayDetector
+	if cfg.NonceReplay.Enabled {
+		nonceReplay = share.NewNonceReplayDetector(share.NonceReplayConfig{
+			Window: cfg.NonceReplay.Window * time.Second,
+			Reject: cfg.NonceReplay.Reject,
+		}, d.ShareStorage)
+	}
+
+	var batchStorage share.ShareStorage = d.ShareStorage
+	if d.SpillStorage != nil {
+		batchStorage = d.SpillStorage
+	}
+
+	d.ShareUseCase = share.NewShareUseCase(batchStorage, d.MinerStorage, d.CoinStorage, d.MinerCache, d.CoinCache, nonceReplay,
+		share.NewDuplicateFilter(cfg.KafkaShareReader.DedupSize))
+	d.ShareUseCase.SetRewardMethods(cfg.Shares.RewardMethods)
+
+	cfgAnalitics := analitics.Config{
+		CurrentWindow:        cfg.Analitics.HashrateCurrentWindow * time.Second,
+		CoinAlgorithms:       cfg.Analitics.CoinAlgorithms,
+		AlgorithmMultipliers: cfg.Analitics.AlgorithmMultipliers,
+	}
+	for _, w := range cfg.Analitics.HashrateAverageWindows {
+		cfgAnalitics.AverageWindows = append(cfgAnalitics.AverageWindows, w*time.Second)
+	}
+	d.AnaliticsUseCase = analitics.NewAnaliticsUsecase(cfgAnalitics, d.ShareStorage, d.CoinStorage)
+
+	return nil
+}
+
+//*** обработчик Кафки
+
+func (d *Dependencies) startConsumer(ctx context.Context) error {
+	if err := requires(d.ShareUseCase != nil, ComponentUsecases); err != nil {
+		return err
+	}
+	cfg := d.Config
+
+	// Вычитываем сообщения из Кафки
+	reader, err := kafka_reader.NewKafkaReader(kafka_reader.Config{
+		Brokers:            cfg.KafkaShareReader.Brokers,
+		Group:              cfg.KafkaShareReader.Group,
+		Topic:              cfg.KafkaShareReader.Topic,
+		AutoCommitInterval: constants.KafkaSharesAutocommitInterval,
+		AutoCommitEnable:   true,
+	}, logger.Log())
+	if err != nil {
+		return fmt.Errorf("NewKafkaReader: %w", err)
+	}
+
+	// Dead-letter топик для шар, которые не удалось декодировать или нормализовать
+	var deadLetterWriter shares.DeadLetterWriter
+	if cfg.KafkaDeadLetter.Topic != "" {
+		d.DeadLetterWriter, err = kafka_writer.NewKafkaWriter(kafka_writer.Config{
+			Brokers: cfg.KafkaDeadLetter.Brokers,
+			Topic:   cfg.KafkaDeadLetter.Topic,
+		}, logger.Log())
+		if err != nil {
+			reader.Close()
+			return fmt.Errorf("dead-letter NewKafkaWriter: %w", err)
+		}
+		d.DeadLetterWriter.Start()
+		deadLetterWriter = d.DeadLetterWriter
+	}
+
+	cfgConsumer := shares.Config{
+		BatchSize:        cfg.KafkaShareReader.ReadBatchSize,
+		FlushInterval:    cfg.KafkaShareReader.ReadFlushInterval,
+		NormalizeWorkers: cfg.KafkaShareReader.NormalizeWorkers,
+		RetryBackoffMin:  cfg.KafkaShareReader.RetryBackoffMin * time.Second,
+		RetryBackoffMax:  cfg.KafkaShareReader.RetryBackoffMax * time.Second,
+		UnknownRetries:   cfg.KafkaShareReader.UnknownRetries,
+		FlushTimeout:     cfg.KafkaShareReader.FlushTimeout * time.Second,
+	}
+
+	d.Consumer, err = shares.NewShareConsumer(cfgConsumer, reader, d.ShareUseCase, deadLetterWriter, d.ShareStorage)
+	if err != nil {
+		return fmt.Errorf("NewShareConsumer: %w", err)
+	}
+
+	// Стартуем вычитывание сообщений
+	d.Consumer.StartConsume()
+
+	return nil
+}
+
+// stopConsumer остановка чтения (с сохранением незавершенных пакетов) и dead-letter топика
+// ожидание сохранения ограничено ctx: по его истечении остановка прерывается, dead-letter топик не закрывается,
+// так как обработчик еще может в него писать (несохраненные пакеты будут прочитаны заново)
+func (d *Dependencies) stopConsumer(ctx context.Context) error {
+	if d.Consumer != nil {
+		done := make(chan struct{})
+		go func() {
+			d.Consumer.Close()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return fmt.Errorf("consumer close: %w", ctx.Err())
+		}
+	}
+	if d.DeadLetterWriter != nil {
+		d.DeadLetterWriter.Close()
+	}
+
+	return nil
+}
+
+func (d *Dependencies) consumerHealth(ctx context.Context) error {
+	if paused := d.Consumer.Backpressure().Paused; len(paused) > 0 {
+		return fmt.Errorf("%d partitions paused: %s", len(paused), paused[0].LastError)
+	}
+
+	return nil
+}
+
+//*** gRPC сервер
+
+// startGRPCServer GRPC сервер: аналитика и прием шар (TLS + JWT, как и у клиентских соединений; в автономном режиме с GRPC.Insecure без TLS)
+func (d *Dependencies) startGRPCServer(ctx context.Context) error {
+	if d.Config.ApiBaseUrls.Grps == "" {
+		return nil
+	}
+	if err := requires(d.AnaliticsUseCase != nil, ComponentUsecases); err != nil {
+		return err
+	}
+	if err := requires(d.JWT != nil && (d.CertManager != nil || d.grpcInsecure()), ComponentGRPCClients); err != nil {
+		return err
+	}
+
+	serverOptions := []grpc.ServerOption{grpc.UnaryInterceptor(d.JWT.GetValidateInterceptor())}
+	if !d.grpcInsecure() {
+		serverCreds, err := d.CertManager.GetServerCredentials()
+		if err != nil {
+			return fmt.Errorf("GetServerCredentials: %w", err)
+		}
+		serverOptions = append(serverOptions, grpc.Creds(*serverCreds))
+	}
+
+	lis, err := net.Listen("tcp", d.Config.ApiBaseUrls.Grps)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+
+	d.GRPCServer = grpc.NewServer(serverOptions...)
+	analyticsServer, err := pb.NewAnalyticsServer(d.AnaliticsUseCase)
+	if err != nil {
+		return fmt.Errorf("NewAnalyticsServer: %w", err)
+	}
+	proto.RegisterAnalyticsServiceServer(d.GRPCServer, analyticsServer)
+
+	// прием шар напрямую (тот же конвейер нормализации, что и для Кафки)
+	sharesServer, err := pb.NewSharesServer(d.ShareUseCase)
+	if err != nil {
+		return fmt.Errorf("NewSharesServer: %w", err)
+	}
+	proto.RegisterSharesServiceServer(d.GRPCServer, sharesServer)
+
+	go func() {
+		if err := d.GRPCServer.Serve(lis); err != nil {
+			logger.Log().Error("GRPC server error: " + err.Error())
+		}
+	}()
+
+	return nil
+}
+
+// stopGRPCServer остановка gRPC сервера: дожидаемся завершения текущих запросов,
+// по истечении ctx обрываем оставшиеся
+func (d *Dependencies) stopGRPCServer(ctx context.Context) error {
+	if d.GRPCServer == nil {
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		d.GRPCServer.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		d.GRPCServer.Stop()
+		return ctx.Err()
+	}
+}
+
+//*** REST
+
+// startREST http сервер (аналитика, состояние компонентов и обработчика Кафки; admin маршруты - по JWT токену, как у gRPC)
+func (d *Dependencies) startREST(ctx context.Context) error {
+	if d.Config.ApiBaseUrls.Rest == "" {
+		return nil
+	}
+	if err := requires(d.AnaliticsUseCase != nil, ComponentUsecases); err != nil {
+		return err
+	}
+
+	var consumer rest.ConsumerStatus // обработчик Кафки может быть не запущен
+	var duplicates rest.DuplicatesCounter
+	if d.Consumer != nil {
+		consumer = d.Consumer
+	}
+	if d.ShareUseCase != nil {
+		duplicates = d.ShareUseCase
+	}
+	var health rest.HealthChecker
+	if d.registry != nil {
+		health = d.registry
+	}
+	var spillStatus rest.SpillStatus
+	if d.SpillStorage != nil {
+		spillStatus = d.SpillStorage
+	}
+	var auth rest.TokenValidator
+	if d.JWT != nil {
+		auth = d.JWT
+	}
+	httpHandler := rest.NewHandler(d.AnaliticsUseCase, consumer, auth, duplicates, health, spillStatus)
+
+	lis, err := net.Listen("tcp", d.Config.ApiBaseUrls.Rest)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+
+	d.HTTPServer = &http.Server{Handler: httpHandler.Routes()}
+	go func() {
+		if err := d.HTTPServer.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Log().Error("HTTP server error: " + err.Error())
+		}
+	}()
+
+	return nil
+}
+
+func (d *Dependencies) stopREST(ctx context.Context) error {
+	if d.HTTPServer == nil {
+		return nil
+	}
+	return d.HTTPServer.Shutdown(ctx)
+}
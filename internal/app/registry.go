@@ -0,0 +1,184 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+)
+
+// DefaultStopTimeout время на остановку одного компонента
+const DefaultStopTimeout = 10 * time.Second
+
+// DefaultStopGrace время на выход из Stop после отмены его ctx
+const DefaultStopGrace = time.Second
+
+// errStillStopping компонент не вышел из Stop и после отмены ctx
+var errStillStopping = errors.New("still stopping after context cancellation")
+
+// Component подсистема сервиса (service discovery, ClickHouse, обработчик Кафки и т.п.)
+type Component interface {
+	Name() string
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error   // ctx ограничивает время остановки
+	Health(ctx context.Context) error // nil - компонент исправен
+}
+
+// Registry компоненты сервиса в порядке зависимостей
+// запускаются в порядке регистрации, останавливаются в обратном
+type Registry struct {
+	mu          sync.Mutex
+	components  []Component
+	started     []Component
+	stopTimeout time.Duration
+	stopGrace   time.Duration
+}
+
+func NewRegistry(stopTimeout time.Duration) *Registry {
+	if stopTimeout <= 0 {
+		stopTimeout = DefaultStopTimeout
+	}
+
+	return &Registry{stopTimeout: stopTimeout, stopGrace: DefaultStopGrace}
+}
+
+// Register добавление компонентов, каждый следующий может зависеть от предыдущих
+func (r *Registry) Register(components ...Component) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.components = append(r.components, components...)
+}
+
+// Start запуск компонентов по порядку
+// если компонент не запустился, он и уже запущенные компоненты останавливаются в обратном порядке
+// (компонент должен уметь остановиться после частичного запуска)
+func (r *Registry) Start(ctx context.Context) error {
+	r.mu.Lock()
+	components := r.components[len(r.started):]
+	r.mu.Unlock()
+
+	for _, c := range components {
+		start := time.Now()
+		err := c.Start(ctx)
+
+		r.mu.Lock()
+		r.started = append(r.started, c)
+		r.mu.Unlock()
+
+		if err != nil {
+			err = fmt.Errorf("start %s: %w", c.Name(), err)
+			logger.Log().Error(err.Error())
+			if stopErr := r.Stop(); stopErr != nil {
+				return errors.Join(err, stopErr)
+			}
+			return err
+		}
+		logger.Log().Info(fmt.Sprintf("Component %s started in %v", c.Name(), time.Since(start)))
+	}
+
+	return nil
+}
+
+// Stop остановка запущенных компонентов в обратном порядке, на каждый отводится stopTimeout
+// компонент, не уложившийся в отведенное время, считается неудачно остановленным, остановка продолжается со следующего,
+// но только после его выхода из Stop: иначе он продолжил бы работать с уже закрытыми компонентами (ClickHouse и т.п.)
+// если компонент не вышел из Stop и через stopGrace после отмены ctx, предыдущие компоненты не останавливаются
+func (r *Registry) Stop() error {
+	r.mu.Lock()
+	started := r.started
+	r.started = nil
+	r.mu.Unlock()
+
+	var errs []error
+	for i := len(started) - 1; i >= 0; i-- {
+		c := started[i]
+		start := time.Now()
+		if err := r.stopComponent(c); err != nil {
+			logger.Log().Error(fmt.Sprintf("Component %s stop failed: %s", c.Name(), err.Error()))
+			errs = append(errs, fmt.Errorf("stop %s: %w", c.Name(), err))
+			if errors.Is(err, errStillStopping) {
+				for j := i - 1; j >= 0; j-- {
+					logger.Log().Error(fmt.Sprintf("Component %s not stopped: %s is still stopping", started[j].Name(), c.Name()))
+					errs = append(errs, fmt.Errorf("stop %s: skipped, %s is still stopping", started[j].Name(), c.Name()))
+				}
+				break
+			}
+			continue
+		}
+		logger.Log().Info(fmt.Sprintf("Component %s stopped in %v", c.Name(), time.Since(start)))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *Registry) stopComponent(c Component) error {
+	ctx, cancel := context.WithTimeout(context.Background(), r.stopTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Stop(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	}
+
+	// время вышло, ctx отменен: даем компоненту выйти из Stop
+	select {
+	case <-done:
+		return ctx.Err()
+	case <-time.After(r.stopGrace):
+		return fmt.Errorf("%w: %w", ctx.Err(), errStillStopping)
+	}
+}
+
+// Health состояние запущенных компонентов по именам (nil - исправен)
+func (r *Registry) Health(ctx context.Context) map[string]error {
+	r.mu.Lock()
+	started := append([]Component(nil), r.started...)
+	r.mu.Unlock()
+
+	health := make(map[string]error, len(started))
+	for _, c := range started {
+		health[c.Name()] = c.Health(ctx)
+	}
+
+	return health
+}
+
+// component компонент из функций запуска, остановки и проверки состояния
+type component struct {
+	name   string
+	start  func(ctx context.Context) error
+	stop   func(ctx context.Context) error // nil - остановка не требуется
+	health func(ctx context.Context) error // nil - всегда исправен
+}
+
+func (c *component) Name() string {
+	return c.name
+}
+
+func (c *component) Start(ctx context.Context) error {
+	return c.start(ctx)
+}
+
+func (c *component) Stop(ctx context.Context) error {
+	if c.stop == nil {
+		return nil
+	}
+	return c.stop(ctx)
+}
+
+func (c *component) Health(ctx context.Context) error {
+	if c.health == nil {
+		return nil
+	}
+	return c.health(ctx)
+}
@@ -0,0 +1,108 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+)
+
+func TestRegistry(t *testing.T) {
+	logger.InitLogger(logger.LogLevelProduction, t.TempDir()+"/test.log")
+
+	var order []string
+	newComponent := func(name string, startErr, stopErr error) *component {
+		return &component{
+			name: name,
+			start: func(ctx context.Context) error {
+				order = append(order, "start "+name)
+				return startErr
+			},
+			stop: func(ctx context.Context) error {
+				order = append(order, "stop "+name)
+				return stopErr
+			},
+		}
+	}
+	errClose := errors.New("close failed")
+	errUnhealthy := errors.New("unavailable")
+
+	registry := NewRegistry(20 * time.Millisecond)
+	registry.Register(
+		newComponent("discovery", nil, nil),
+		newComponent("clickhouse", nil, errClose),
+		&component{
+			name:  "consumer",
+			start: func(ctx context.Context) error { return nil },
+			stop: func(ctx context.Context) error {
+				<-ctx.Done() // не укладывается в отведенное время
+				return nil
+			},
+			health: func(ctx context.Context) error { return errUnhealthy },
+		},
+		newComponent("rest", nil, nil),
+	)
+
+	require.NoError(t, registry.Start(context.Background()))
+	require.Equal(t, map[string]error{"discovery": nil, "clickhouse": nil, "consumer": errUnhealthy, "rest": nil}, registry.Health(context.Background()))
+
+	// остановка в обратном порядке, после неудачных шагов продолжается
+	err := registry.Stop()
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorIs(t, err, errClose)
+	require.Equal(t, []string{"start discovery", "start clickhouse", "start rest", "stop rest", "stop clickhouse", "stop discovery"}, order)
+	require.Empty(t, registry.Health(context.Background()))
+
+	// компонент не запустился: он и запущенные до него останавливаются, следующие не запускаются
+	order = nil
+	errStart := errors.New("listen failed")
+	registry = NewRegistry(0)
+	registry.Register(
+		newComponent("discovery", nil, nil),
+		newComponent("clickhouse", errStart, nil),
+		newComponent("rest", nil, nil),
+	)
+	require.ErrorIs(t, registry.Start(context.Background()), errStart)
+	require.Equal(t, []string{"start discovery", "start clickhouse", "stop clickhouse", "stop discovery"}, order)
+}
+
+func TestRegistryStopStuck(t *testing.T) {
+	logger.InitLogger(logger.LogLevelProduction, t.TempDir()+"/test.log")
+
+	release := make(chan struct{})
+	var clickhouseStopped atomic.Bool
+	registry := NewRegistry(10 * time.Millisecond)
+	registry.stopGrace = 10 * time.Millisecond
+	registry.Register(
+		&component{
+			name:  "clickhouse",
+			start: func(ctx context.Context) error { return nil },
+			stop: func(ctx context.Context) error {
+				clickhouseStopped.Store(true)
+				return nil
+			},
+		},
+		&component{
+			name:  "consumer",
+			start: func(ctx context.Context) error { return nil },
+			stop: func(ctx context.Context) error {
+				<-release // отмену ctx не учитывает
+				return nil
+			},
+		},
+	)
+	require.NoError(t, registry.Start(context.Background()))
+
+	// ClickHouse не закрывается, пока обработчик еще сохраняет шары
+	err := registry.Stop()
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorIs(t, err, errStillStopping)
+	require.ErrorContains(t, err, "stop clickhouse: skipped")
+	require.False(t, clickhouseStopped.Load())
+	close(release)
+}
@@ -0,0 +1,123 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dnsoftware/mpm-shares-processor/config"
+	"github.com/dnsoftware/mpm-shares-processor/internal/constants"
+	"github.com/dnsoftware/mpm-shares-processor/internal/infrastructure/loaders"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+)
+
+//*** обновление конфига на ходу
+
+// startConfigReload отслеживание конфига приложения в etcd
+// изменения config.ReloadableFields применяются на ходу, остальные отклоняются до перезапуска
+func (d *Dependencies) startConfigReload(ctx context.Context) error {
+	if d.EtcdClient == nil {
+		logger.Log().Info("Config reload disabled: no etcd client (standalone mode)")
+		return nil
+	}
+
+	// конфиг, с которым запущен сервис (удаленный конфиг, сохраненный при запуске в config.yaml)
+	data, err := os.ReadFile(d.BasePath + constants.LocalConfigPath)
+	if err != nil {
+		return fmt.Errorf("read local config: %w", err)
+	}
+	d.remoteConfig, err = config.Parse(data)
+	if err != nil {
+		return fmt.Errorf("parse local config: %w", err)
+	}
+
+	watchCtx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	d.reloadCancel = cancel
+	d.reloadDone = done
+
+	go func() {
+		defer close(done)
+		loaders.WatchRemoteConfig(watchCtx, d.EtcdClient, d.Config.App.AppID, d.reloadConfig)
+	}()
+
+	return nil
+}
+
+func (d *Dependencies) stopConfigReload(ctx context.Context) error {
+	if d.reloadCancel == nil {
+		return nil
+	}
+	d.reloadCancel()
+
+	select {
+	case <-d.reloadDone:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// reloadConfig применение нового текста конфига
+// параметры, требующие перезапуска, не применяются (в лог пишется их разница с текущими значениями)
+// проверяется запущенный конфиг (с флагами и переменными окружения) с новыми значениями параметров, применяемых на ходу
+func (d *Dependencies) reloadConfig(data []byte) {
+	next, err := config.Parse(data)
+	if err == nil {
+		err = config.WithReloadable(d.Config, next).Validate()
+	}
+	if err != nil {
+		logger.Log().Error("Config reload rejected: " + err.Error())
+		return
+	}
+
+	reloadable, restart := config.Diff(d.remoteConfig, next)
+	if len(restart) > 0 {
+		logger.Log().Warn("Config changes require restart, not applied: " + joinChanges(restart))
+	}
+	if len(reloadable) == 0 {
+		return
+	}
+
+	if err := d.applyConfig(next); err != nil {
+		logger.Log().Error(fmt.Sprintf("Config reload rejected (%s): %s", joinChanges(reloadable), err.Error()))
+		return
+	}
+	logger.Log().Info("Config reloaded: " + joinChanges(reloadable))
+}
+
+// applyConfig применение параметров config.ReloadableFields к запущенным компонентам
+func (d *Dependencies) applyConfig(next config.Config) error {
+	reader := next.KafkaShareReader
+	if next.App.LogLevel != "" {
+		if err := logger.SetLevel(next.App.LogLevel); err != nil {
+			return fmt.Errorf("log_level: %w", err)
+		}
+	}
+
+	if d.Consumer != nil {
+		d.Consumer.SetBatching(reader.ReadBatchSize, reader.ReadFlushInterval)
+	}
+	if d.AnaliticsUseCase != nil {
+		var averages []time.Duration
+		for _, w := range next.Analitics.HashrateAverageWindows {
+			averages = append(averages, w*time.Second)
+		}
+		d.AnaliticsUseCase.SetWindows(next.Analitics.HashrateCurrentWindow*time.Second, averages)
+	}
+
+	d.remoteConfig = config.WithReloadable(d.remoteConfig, next)
+
+	return nil
+}
+
+func joinChanges(changes []config.Change) string {
+	items := make([]string, 0, len(changes))
+	for _, c := range changes {
+		items = append(items, c.String())
+	}
+
+	return strings.Join(items, "; ")
+}
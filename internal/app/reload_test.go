@@ -0,0 +1,75 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnsoftware/mpm-shares-processor/config"
+	"github.com/dnsoftware/mpm-shares-processor/internal/adapter/kafka_consumer/shares"
+	"github.com/dnsoftware/mpm-shares-processor/internal/usecase/analitics"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+)
+
+func TestReloadConfig(t *testing.T) {
+	logger.InitLogger(logger.LogLevelProduction, t.TempDir()+"/test.log")
+
+	boot := []byte(`
+kafka_share_reader:
+  topic: "shares"
+  read_batch_size: 100
+  read_flush_interval: 1
+`)
+	remote, err := config.Parse(boot)
+	require.NoError(t, err)
+
+	consumer, err := shares.NewShareConsumer(shares.Config{BatchSize: 100, FlushInterval: 1}, nil, nil, nil, nil)
+	require.NoError(t, err)
+	// запущенный конфиг: секрет JWT и адрес ClickHouse заданы только флагами (-js, -clickhouse_addr), в etcd их нет
+	running := config.Defaults()
+	running.KafkaShareReader.Brokers = []string{"127.0.0.1:9092"}
+	running.KafkaShareReader.Topic = "shares"
+	running.Auth.JWTSecret = "flag_secret"
+	running.Clickhouse.Addr = []string{"localhost:9000"}
+
+	deps := &Dependencies{
+		Config:           running,
+		Consumer:         consumer,
+		AnaliticsUseCase: analitics.NewAnaliticsUsecase(analitics.Config{}, nil, nil),
+		remoteConfig:     remote,
+	}
+
+	// смена топика требует перезапуска и не применяется, размер пакета и уровень логов - на ходу
+	deps.reloadConfig([]byte(`
+application:
+  log_level: "warn"
+kafka_share_reader:
+  topic: "shares_v2"
+  read_batch_size: 500
+  read_flush_interval: 3
+analitics:
+  hashrate_current_window: 300
+`))
+	batchSize, flushInterval := consumer.Batching()
+	require.Equal(t, 500, batchSize)
+	require.Equal(t, time.Duration(3), flushInterval)
+	require.Equal(t, "warn", logger.Level())
+	require.Equal(t, "shares", deps.remoteConfig.KafkaShareReader.Topic)
+	require.Equal(t, time.Duration(300), deps.remoteConfig.Analitics.HashrateCurrentWindow)
+
+	// недопустимые значения отклоняются целиком
+	deps.reloadConfig([]byte(`
+application:
+  log_level: "verbose"
+kafka_share_reader:
+  topic: "shares"
+  read_batch_size: 0
+  read_flush_interval: 3
+`))
+	batchSize, _ = consumer.Batching()
+	require.Equal(t, 500, batchSize)
+	require.Equal(t, "warn", logger.Level())
+
+	require.NoError(t, logger.SetLevel("info"))
+}
@@ -4,10 +4,13 @@ const (
 	ProjectRootAnchorFile = ".env"
 	AppLogFile            = "app.log"
 	TestLogFile           = "test.log"
-	StartConfigFilename   = "/startconf.yaml"             // название файла стартового конфига (с доступами к etcd основного конфига)
-	LocalConfigPath       = "/config.yaml"                // Путь к локальному файлу конфига (сюда сохраняется удаленный конфиг)
-	ServiceConfigPath     = "/service_config/services"    // Папка в etcd где хранятся конфиги микросервисов
-	ServiceDiscoveryPath  = "/service_discovery/services" // Папка в etcd где хранятся текущие адреса микросервисов
+	StartConfigFilename   = "/startconf.yaml"                      // название файла стартового конфига (с доступами к etcd основного конфига)
+	LocalConfigPath       = "/config.yaml"                         // Путь к локальному файлу конфига (сюда сохраняется удаленный конфиг)
+	ServiceConfigPath     = "/service_config/services"             // Папка в etcd где хранятся конфиги микросервисов
+	ServiceDiscoveryPath  = "/service_discovery/services"          // Папка в etcd где хранятся текущие адреса микросервисов
+	StandaloneAppID       = "SHARES_PROCESSOR_LOCAL"               // идентификатор приложения в автономном режиме, если не задан APP_ID
+	MigrationLockPath     = "/service_locks/clickhouse_migrations" // Ключ блокировки миграций в etcd (+ "/" + база ClickHouse)
+	MigrationLockTTL      = 30                                     // время жизни блокировки миграций после падения процесса, в секундах
 
 	CaPath      = "/certs/ca.crt"     // путь к корневому сертификату
 	PublicPath  = "/certs/client.crt" // путь к сертификату
@@ -0,0 +1,101 @@
+package entity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Постоянные ошибки: повтор не поможет, шара пропускается (dead-letter)
+var (
+	ErrUnknownCoin    = errors.New("unknown coin")    // монеты нет в справочнике
+	ErrInvalidShare   = errors.New("invalid share")   // не заполнены обязательные поля шары
+	ErrInvalidDecimal = errors.New("invalid decimal") // сложность или награда не число
+	ErrNonceReplay    = errors.New("nonce replay")    // повтор nonce воркера, отклонен (nonce_replay.reject)
+)
+
+// ErrDuplicateShare шара с таким UUID уже сохранена или сохраняется параллельно
+// не ошибка обработки: в dead-letter не отправляется, при приеме по gRPC сообщается клиенту как отклоненная
+var ErrDuplicateShare = errors.New("duplicate share")
+
+// Временные ошибки: повтор может быть успешным
+var (
+	ErrStorageUnavailable = errors.New("storage unavailable") // ClickHouse недоступен
+	ErrServiceUnavailable = errors.New("service unavailable") // gRPC сервис недоступен или не ответил вовремя
+	ErrQueueUnavailable   = errors.New("queue unavailable")   // Кафка не подтвердила отправку сообщения
+)
+
+// Классы ошибок для логов и метрик
+const (
+	ErrorClassTransient = "transient"
+	ErrorClassPermanent = "permanent"
+	ErrorClassUnknown   = "unknown"
+)
+
+// ShareError ошибка, относящаяся к конкретной шаре пакета
+type ShareError struct {
+	UUID string
+	Err  error
+}
+
+func (e *ShareError) Error() string {
+	return fmt.Sprintf("share %s: %s", e.UUID, e.Err.Error())
+}
+
+func (e *ShareError) Unwrap() error {
+	return e.Err
+}
+
+// PartialSaveError пакет сохранен частично: шары Rejected отклонены, остальные сохранены
+// повторять нужно только отклоненные шары (или отправить их в dead-letter), иначе сохраненные задвоятся
+type PartialSaveError struct {
+	Rejected []*ShareError
+}
+
+func (e *PartialSaveError) Error() string {
+	if len(e.Rejected) == 0 {
+		return "0 shares rejected"
+	}
+	return fmt.Sprintf("%d shares rejected, first: %s", len(e.Rejected), e.Rejected[0].Error())
+}
+
+func (e *PartialSaveError) Unwrap() []error {
+	errs := make([]error, len(e.Rejected))
+	for i, r := range e.Rejected {
+		errs[i] = r
+	}
+	return errs
+}
+
+// RejectedUUIDs UUID отклоненных шар
+func (e *PartialSaveError) RejectedUUIDs() []string {
+	uuids := make([]string, len(e.Rejected))
+	for i, r := range e.Rejected {
+		uuids[i] = r.UUID
+	}
+	return uuids
+}
+
+// IsTransient временная ли ошибка
+func IsTransient(err error) bool {
+	return errors.Is(err, ErrStorageUnavailable) || errors.Is(err, ErrServiceUnavailable) || errors.Is(err, ErrQueueUnavailable) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
+
+// IsPermanent постоянная ли ошибка
+func IsPermanent(err error) bool {
+	return errors.Is(err, ErrUnknownCoin) || errors.Is(err, ErrInvalidShare) || errors.Is(err, ErrInvalidDecimal) ||
+		errors.Is(err, ErrNonceReplay)
+}
+
+// ErrorClass класс ошибки (ErrorClassTransient, ErrorClassPermanent, ErrorClassUnknown)
+func ErrorClass(err error) string {
+	switch {
+	case IsTransient(err):
+		return ErrorClassTransient
+	case IsPermanent(err):
+		return ErrorClassPermanent
+	default:
+		return ErrorClassUnknown
+	}
+}
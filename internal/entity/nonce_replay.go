@@ -0,0 +1,21 @@
+package entity
+
+// NonceReplay повторная отправка воркером шары с уже использованным nonce
+type NonceReplay struct {
+	ShareUUID    string // идентификатор повторной шары
+	OriginalUUID string // идентификатор первой шары с этим nonce
+	ServerID     string // идентификатор пул-сервера
+	CoinID       int64
+	WorkerID     int64
+	WalletID     int64
+	ShareDate    int64  // время повторной шары в миллисекундах
+	Nonce        string // nonce шары
+	Rejected     bool   // шара отклонена (false - только помечена)
+}
+
+// NonceReplayCount количество повторов nonce воркера
+type NonceReplayCount struct {
+	WorkerID int64
+	Count    uint64 // всего повторов
+	Rejected uint64 // из них отклонено
+}
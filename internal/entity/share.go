@@ -14,4 +14,9 @@ type Share struct {
 	IsSolo       bool   // соло режим (оставлено для совместимости с предыдущей версией) TODO выпилить в будущем
 	RewardMethod string // метод начисления вознаграждения
 	Cost         string // награда за шару
+
+	// позиция в Кафке (сохраняется вместе с шарой для exactly-once обработки)
+	KafkaTopic     string // пусто - шара получена не из Кафки
+	KafkaPartition int32
+	KafkaOffset    int64
 }
@@ -0,0 +1,56 @@
+package clickhouse
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+)
+
+// storageError классификация ошибки ClickHouse
+// сетевые ошибки и ошибки получения соединения - временные (entity.ErrStorageUnavailable)
+func storageError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.As(err, &netErr),
+		errors.Is(err, io.EOF),
+		errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, syscall.ECONNREFUSED),
+		errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, syscall.EPIPE),
+		errors.Is(err, driver.ErrBadConn),
+		errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, clickhouse.ErrAcquireConnTimeout),
+		errors.Is(err, clickhouse.ErrAcquireConnNoAddress):
+		return fmt.Errorf("%w: %w", entity.ErrStorageUnavailable, err)
+	}
+
+	return err
+}
+
+// decimalError ошибка разбора числового поля шары
+func decimalError(uuid string, field string, value string, err error) error {
+	return &entity.ShareError{
+		UUID: uuid,
+		Err:  fmt.Errorf("%w: %s %q: %w", entity.ErrInvalidDecimal, field, value, err),
+	}
+}
+
+// appendError ошибка добавления шары в пакет (преобразование значений в типы колонок) - шара некорректна
+func appendError(uuid string, err error) error {
+	return &entity.ShareError{
+		UUID: uuid,
+		Err:  fmt.Errorf("%w: append: %w", entity.ErrInvalidShare, err),
+	}
+}
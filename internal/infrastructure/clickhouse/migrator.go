@@ -0,0 +1,489 @@
+package clickhouse
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+)
+
+// Таблица истории миграций: одна строка на каждое применение/откат, состояние версии - последняя строка
+// общий путь в ZooKeeper без {shard}: история одна на весь кластер, к какой бы ноде ни подключились;
+// база в пути разделяет истории баз одного кластера
+const historyTable = `CREATE TABLE IF NOT EXISTS {{.Database}}.schema_migrations_history ON CLUSTER {{.Cluster}} (
+   version UInt64, -- версия миграции
+   name String, -- название миграции
+   checksum String, -- sha256 файла up-миграции
+   applied Bool, -- true - применена, false - откачена
+   applied_at DateTime64(3) DEFAULT now64(3) -- время применения/отката
+)
+ENGINE = ReplicatedMergeTree(
+    '/clickhouse/tables/{{.Database}}/schema_migrations_history',
+    '{replica}'
+)
+ORDER BY (version, applied_at)`
+
+// destructiveSQL операторы, удаляющие данные (в up-миграциях запрещены, в down - только явно)
+var destructiveSQL = regexp.MustCompile(`(?i)\b(DROP|TRUNCATE|DETACH|DELETE)\b`)
+
+// identifier допустимое имя базы/кластера для подстановки в SQL
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Locker распределенная блокировка на время применения миграций
+type Locker interface {
+	Lock(ctx context.Context) (unlock func(), err error)
+}
+
+// MigratorConfig параметры применения миграций ClickHouse
+type MigratorConfig struct {
+	Conn     driver.Conn // соединение с учетными данными из конфига (имена таблиц в миграциях полные)
+	Dir      string      // каталог файлов миграций (<версия>_<название>.up.sql / .down.sql)
+	Database string      // подставляется в миграции вместо {{.Database}}
+	Cluster  string      // подставляется в миграции вместо {{.Cluster}}
+	Locker   Locker      // nil - без блокировки (автономный режим, одна реплика)
+}
+
+// Migration файл миграции и его состояние в истории
+type Migration struct {
+	Version  uint
+	Name     string
+	Checksum string // sha256 файла up-миграции
+	Applied  bool
+	Modified bool     // файл изменен после применения (контрольная сумма не совпадает с историей)
+	Up       []string // операторы up-миграции после подстановки имен
+	Down     []string // операторы down-миграции после подстановки имен
+}
+
+// MigrationStatus текущая версия схемы и список миграций
+type MigrationStatus struct {
+	Version    uint // последняя примененная версия, 0 - миграции не применялись
+	Migrations []Migration
+}
+
+// appliedMigration последнее состояние версии в истории
+type appliedMigration struct {
+	Checksum string
+	Applied  bool
+}
+
+// Migrator применение и откат миграций ClickHouse
+// миграции не запускаются при старте сервиса, только командой migrate
+type Migrator struct {
+	conn     driver.Conn
+	dir      string
+	names    map[string]string
+	locker   Locker
+	database string
+}
+
+func NewMigrator(cfg MigratorConfig) (*Migrator, error) {
+	if !identifier.MatchString(cfg.Database) {
+		return nil, fmt.Errorf("migrator: bad database name %q", cfg.Database)
+	}
+	if !identifier.MatchString(cfg.Cluster) {
+		return nil, fmt.Errorf("migrator: bad cluster name %q", cfg.Cluster)
+	}
+
+	return &Migrator{
+		conn:     cfg.Conn,
+		dir:      cfg.Dir,
+		names:    map[string]string{"Database": cfg.Database, "Cluster": cfg.Cluster},
+		locker:   cfg.Locker,
+		database: cfg.Database,
+	}, nil
+}
+
+// Pending непримененные миграции по порядку (для up и dry-run)
+// ошибка, если файл уже примененной миграции изменен
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	migrations, err := m.migrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, mg := range migrations {
+		if mg.Modified {
+			return nil, fmt.Errorf("migration %06d_%s: checksum mismatch, file changed after it was applied", mg.Version, mg.Name)
+		}
+		if !mg.Applied {
+			if err := checkNonDestructive(mg.Up); err != nil {
+				return nil, fmt.Errorf("migration %06d_%s: %w", mg.Version, mg.Name, err)
+			}
+			pending = append(pending, mg)
+		}
+	}
+
+	return pending, nil
+}
+
+// Up применение всех непримененных миграций под блокировкой, возвращает примененные
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if err := m.createHistory(ctx); err != nil {
+		return nil, err
+	}
+	// состояние читается только после получения блокировки: другая реплика могла уже все применить
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, mg := range pending {
+		if err := m.exec(ctx, mg.Up); err != nil {
+			return pending[:i], fmt.Errorf("migration %06d_%s up: %w", mg.Version, mg.Name, err)
+		}
+		if err := m.record(ctx, mg, true); err != nil {
+			return pending[:i], err
+		}
+	}
+
+	return pending, nil
+}
+
+// Rollback steps последних примененных миграций в порядке отката (для down и dry-run)
+// ошибка, если откат удаляет данные, а allowDrop не задан, если нет down-файла или файл изменен после применения
+func (m *Migrator) Rollback(ctx context.Context, steps int, allowDrop bool) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, fmt.Errorf("migrate down: steps must be > 0")
+	}
+	migrations, err := m.migrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return rollbackPlan(migrations, steps, allowDrop)
+}
+
+// rollbackPlan выбор миграций для отката
+// ошибка, если у миграции нет down-файла или файл изменен после применения (откат не соответствует примененному)
+func rollbackPlan(migrations []Migration, steps int, allowDrop bool) ([]Migration, error) {
+	var rollback []Migration
+	for i := len(migrations) - 1; i >= 0 && len(rollback) < steps; i-- {
+		mg := migrations[i]
+		if !mg.Applied {
+			continue
+		}
+		if len(mg.Down) == 0 {
+			return nil, fmt.Errorf("migration %06d_%s down: no down migration", mg.Version, mg.Name)
+		}
+		if mg.Modified {
+			return nil, fmt.Errorf("migration %06d_%s down: checksum mismatch, file changed after it was applied", mg.Version, mg.Name)
+		}
+		if !allowDrop {
+			if err := checkNonDestructive(mg.Down); err != nil {
+				return nil, fmt.Errorf("migration %06d_%s down: %w (allow explicitly to roll back)", mg.Version, mg.Name, err)
+			}
+		}
+		rollback = append(rollback, mg)
+	}
+
+	return rollback, nil
+}
+
+// Down откат steps последних миграций под блокировкой, возвращает откаченные
+func (m *Migrator) Down(ctx context.Context, steps int, allowDrop bool) ([]Migration, error) {
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	rollback, err := m.Rollback(ctx, steps, allowDrop)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, mg := range rollback {
+		if err := m.exec(ctx, mg.Down); err != nil {
+			return rollback[:i], fmt.Errorf("migration %06d_%s down: %w", mg.Version, mg.Name, err)
+		}
+		if err := m.record(ctx, mg, false); err != nil {
+			return rollback[:i], err
+		}
+	}
+
+	return rollback, nil
+}
+
+// Status последняя примененная версия и миграции каталога с состоянием
+func (m *Migrator) Status(ctx context.Context) (MigrationStatus, error) {
+	var status MigrationStatus
+
+	migrations, err := m.migrations(ctx)
+	if err != nil {
+		return status, err
+	}
+	status.Migrations = migrations
+	for _, mg := range migrations {
+		if mg.Applied {
+			status.Version = mg.Version
+		}
+	}
+
+	return status, nil
+}
+
+// migrations миграции каталога с состоянием из истории
+func (m *Migrator) migrations(ctx context.Context) ([]Migration, error) {
+	migrations, err := loadMigrations(m.dir, m.names)
+	if err != nil {
+		return nil, err
+	}
+	history, err := m.history(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return applyHistory(migrations, history), nil
+}
+
+func (m *Migrator) lock(ctx context.Context) (func(), error) {
+	if m.locker == nil {
+		return func() {}, nil
+	}
+	unlock, err := m.locker.Lock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("migration lock: %w", err)
+	}
+
+	return unlock, nil
+}
+
+// createHistory создание базы и таблицы истории (до первой миграции)
+func (m *Migrator) createHistory(ctx context.Context) error {
+	statements, err := render("CREATE DATABASE IF NOT EXISTS {{.Database}} ON CLUSTER {{.Cluster}};"+historyTable, m.names)
+	if err != nil {
+		return err
+	}
+	if err := m.exec(ctx, statements); err != nil {
+		return fmt.Errorf("create migrations history: %w", err)
+	}
+
+	return nil
+}
+
+// history последнее состояние каждой версии (пусто, если таблицы истории еще нет)
+func (m *Migrator) history(ctx context.Context) (map[uint]appliedMigration, error) {
+	history := make(map[uint]appliedMigration)
+
+	var exists uint8
+	err := m.conn.QueryRow(ctx, fmt.Sprintf("EXISTS TABLE %s.schema_migrations_history", m.database)).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("migrations history: %w", err)
+	}
+	if exists == 0 {
+		return history, nil
+	}
+
+	rows, err := m.conn.Query(ctx, fmt.Sprintf(`SELECT version, argMax(checksum, applied_at), argMax(applied, applied_at)
+		FROM %s.schema_migrations_history GROUP BY version`, m.database))
+	if err != nil {
+		return nil, fmt.Errorf("migrations history: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			version uint64
+			state   appliedMigration
+		)
+		if err := rows.Scan(&version, &state.Checksum, &state.Applied); err != nil {
+			return nil, fmt.Errorf("migrations history: %w", err)
+		}
+		history[uint(version)] = state
+	}
+
+	return history, rows.Err()
+}
+
+func (m *Migrator) record(ctx context.Context, mg Migration, applied bool) error {
+	err := m.conn.Exec(ctx, fmt.Sprintf("INSERT INTO %s.schema_migrations_history (version, name, checksum, applied) VALUES (?, ?, ?, ?)", m.database),
+		uint64(mg.Version), mg.Name, mg.Checksum, applied)
+	if err != nil {
+		return fmt.Errorf("migration %06d_%s: record history: %w", mg.Version, mg.Name, err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) exec(ctx context.Context, statements []string) error {
+	for _, stmt := range statements {
+		if err := m.conn.Exec(ctx, stmt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyHistory состояние миграций по истории
+func applyHistory(migrations []Migration, history map[uint]appliedMigration) []Migration {
+	for i := range migrations {
+		state, ok := history[migrations[i].Version]
+		if !ok || !state.Applied {
+			continue
+		}
+		migrations[i].Applied = true
+		migrations[i].Modified = state.Checksum != migrations[i].Checksum
+	}
+
+	return migrations
+}
+
+// checkNonDestructive ошибка, если оператор удаляет данные
+func checkNonDestructive(statements []string) error {
+	for _, stmt := range statements {
+		if match := destructiveSQL.FindString(stmt); match != "" {
+			return fmt.Errorf("destructive statement (%s): %s", strings.ToUpper(match), firstLine(stmt))
+		}
+	}
+
+	return nil
+}
+
+// loadMigrations миграции каталога (по файлам .up.sql) в порядке версий с подставленными именами
+func loadMigrations(dir string, names map[string]string) ([]Migration, error) {
+	migrations, err := migrationFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range migrations {
+		mg := &migrations[i]
+		base := filepath.Join(dir, fmt.Sprintf("%06d_%s", mg.Version, mg.Name))
+
+		up, err := os.ReadFile(base + ".up.sql")
+		if err != nil {
+			return nil, fmt.Errorf("read migration: %w", err)
+		}
+		sum := sha256.Sum256(up)
+		mg.Checksum = hex.EncodeToString(sum[:])
+		if mg.Up, err = render(string(up), names); err != nil {
+			return nil, fmt.Errorf("migration %06d_%s up: %w", mg.Version, mg.Name, err)
+		}
+
+		// down-файл не обязателен: без него миграция не откатывается
+		down, err := os.ReadFile(base + ".down.sql")
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("read migration: %w", err)
+		}
+		if mg.Down, err = render(string(down), names); err != nil {
+			return nil, fmt.Errorf("migration %06d_%s down: %w", mg.Version, mg.Name, err)
+		}
+	}
+
+	return migrations, nil
+}
+
+// migrationFiles миграции каталога (по файлам .up.sql) в порядке версий
+func migrationFiles(dir string) ([]Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	var migrations []Migration
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".up.sql")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		versionStr, title, _ := strings.Cut(name, "_")
+		version, err := strconv.ParseUint(versionStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: bad version: %w", entry.Name(), err)
+		}
+		migrations = append(migrations, Migration{Version: uint(version), Name: title})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// render подстановка имен в текст миграции и разбиение на операторы
+func render(text string, names map[string]string) ([]string, error) {
+	tmpl, err := template.New("migration").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, names); err != nil {
+		return nil, err
+	}
+
+	return splitStatements(buf.String()), nil
+}
+
+// splitStatements разбиение SQL на операторы по ';' без комментариев
+// (ClickHouse выполняет по одному оператору за запрос)
+func splitStatements(sql string) []string {
+	var (
+		statements []string
+		current    strings.Builder
+		quote      byte // открытая кавычка, 0 - вне строки
+	)
+	flush := func() {
+		if stmt := strings.TrimSpace(current.String()); stmt != "" {
+			statements = append(statements, stmt)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case quote != 0:
+			current.WriteByte(c)
+			if c == '\\' && i+1 < len(sql) {
+				i++
+				current.WriteByte(sql[i])
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+			current.WriteByte(c)
+		case c == '-' && strings.HasPrefix(sql[i:], "--"):
+			for i < len(sql) && sql[i] != '\n' {
+				i++
+			}
+			current.WriteByte('\n')
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				i = len(sql)
+			} else {
+				i += end + 3
+			}
+			current.WriteByte(' ')
+		case c == ';':
+			flush()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	flush()
+
+	return statements
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return strings.TrimSpace(line)
+}
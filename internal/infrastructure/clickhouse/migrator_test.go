@@ -0,0 +1,137 @@
+package clickhouse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrationFiles(t *testing.T) {
+	migrations, err := migrationFiles("../../../migration")
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(migrations), 4)
+	require.Equal(t, Migration{Version: 1, Name: "create_database"}, migrations[0])
+	for i := 1; i < len(migrations); i++ {
+		require.Less(t, migrations[i-1].Version, migrations[i].Version)
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := loadMigrations("../../../migration", map[string]string{"Database": "shares_db", "Cluster": "main"})
+	require.NoError(t, err)
+
+	for _, m := range migrations {
+		require.Len(t, m.Checksum, 64)
+		require.NotEmpty(t, m.Up)
+		// up-миграции данные не удаляют
+		require.NoError(t, checkNonDestructive(m.Up), m.Name)
+		for _, stmt := range append(m.Up, m.Down...) {
+			require.NotContains(t, stmt, "{{")
+			require.NotContains(t, stmt, "mpmhouse")
+			require.NotContains(t, stmt, "--")
+		}
+	}
+	require.Equal(t, []string{"CREATE DATABASE IF NOT EXISTS shares_db ON CLUSTER main"}, migrations[0].Up)
+	// файл с несколькими операторами разбивается на отдельные запросы
+	require.Len(t, migrations[2].Up, 3)
+	// откат таблицы шар удаляет данные
+	require.Error(t, checkNonDestructive(migrations[1].Down))
+}
+
+func TestHistoryTable(t *testing.T) {
+	statements, err := render(historyTable, map[string]string{"Database": "shares_db", "Cluster": "main"})
+	require.NoError(t, err)
+	require.Len(t, statements, 1)
+	// у каждой базы своя история в ZooKeeper
+	require.Contains(t, statements[0], "'/clickhouse/tables/shares_db/schema_migrations_history'")
+}
+
+func TestSplitStatements(t *testing.T) {
+	sql := `-- комментарий; не оператор
+CREATE TABLE t (a String DEFAULT 'x;y', b String) /* ; */ ENGINE = Memory;
+INSERT INTO t VALUES ('it\'s;', "q;");
+
+`
+	require.Equal(t, []string{
+		"CREATE TABLE t (a String DEFAULT 'x;y', b String)   ENGINE = Memory",
+		`INSERT INTO t VALUES ('it\'s;', "q;")`,
+	}, splitStatements(sql))
+	require.Empty(t, splitStatements("-- только комментарий\n"))
+}
+
+func TestCheckNonDestructive(t *testing.T) {
+	require.NoError(t, checkNonDestructive([]string{"CREATE TABLE IF NOT EXISTS db.t (a String) ENGINE = Memory"}))
+	require.NoError(t, checkNonDestructive([]string{"ALTER TABLE db.t ADD COLUMN IF NOT EXISTS dropped_at DateTime"}))
+	for _, stmt := range []string{
+		"DROP TABLE db.shares SYNC",
+		"truncate table db.shares",
+		"ALTER TABLE db.shares DROP COLUMN nonce",
+		"ALTER TABLE db.shares DELETE WHERE 1",
+		"DETACH TABLE db.shares",
+	} {
+		require.Error(t, checkNonDestructive([]string{stmt}), stmt)
+	}
+}
+
+func TestApplyHistory(t *testing.T) {
+	migrations := []Migration{
+		{Version: 1, Checksum: "a"},
+		{Version: 2, Checksum: "b"},
+		{Version: 3, Checksum: "c"},
+		{Version: 4, Checksum: "d"},
+	}
+	history := map[uint]appliedMigration{
+		1: {Checksum: "a", Applied: true},
+		2: {Checksum: "changed", Applied: true},
+		3: {Checksum: "c", Applied: false}, // откачена
+	}
+
+	migrations = applyHistory(migrations, history)
+	require.True(t, migrations[0].Applied)
+	require.False(t, migrations[0].Modified)
+	require.True(t, migrations[1].Applied)
+	require.True(t, migrations[1].Modified)
+	require.False(t, migrations[2].Applied)
+	require.False(t, migrations[3].Applied)
+}
+
+func TestRollbackPlan(t *testing.T) {
+	migrations := []Migration{
+		{Version: 1, Name: "create_database", Applied: true, Down: []string{"DROP DATABASE db"}},
+		{Version: 2, Name: "add_column", Applied: true, Down: []string{"ALTER TABLE db.t COMMENT COLUMN a 'x'"}},
+		{Version: 3, Name: "pending", Down: []string{"SELECT 1"}},
+	}
+
+	rollback, err := rollbackPlan(migrations, 1, false)
+	require.NoError(t, err)
+	require.Len(t, rollback, 1)
+	require.Equal(t, uint(2), rollback[0].Version)
+
+	// откат, удаляющий данные, только явно
+	_, err = rollbackPlan(migrations, 2, false)
+	require.Error(t, err)
+	rollback, err = rollbackPlan(migrations, 2, true)
+	require.NoError(t, err)
+	require.Len(t, rollback, 2)
+
+	// без down-файла откат невозможен
+	noDown := append([]Migration(nil), migrations...)
+	noDown[1].Down = nil
+	_, err = rollbackPlan(noDown, 1, true)
+	require.ErrorContains(t, err, "no down migration")
+
+	// файл изменен после применения - откат мог бы не соответствовать примененной миграции
+	modified := append([]Migration(nil), migrations...)
+	modified[1].Modified = true
+	_, err = rollbackPlan(modified, 1, true)
+	require.ErrorContains(t, err, "checksum mismatch")
+}
+
+func TestNewMigratorNames(t *testing.T) {
+	_, err := NewMigrator(MigratorConfig{Database: "mpmhouse", Cluster: "clickhouse_cluster"})
+	require.NoError(t, err)
+	_, err = NewMigrator(MigratorConfig{Database: "mpmhouse; DROP DATABASE x", Cluster: "clickhouse_cluster"})
+	require.Error(t, err)
+	_, err = NewMigrator(MigratorConfig{Database: "mpmhouse"})
+	require.Error(t, err)
+}
@@ -5,12 +5,12 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"log"
 	"strconv"
 	"strings"
 	"text/template"
 	"time"
 
+	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	"github.com/shopspring/decimal"
 
@@ -18,26 +18,34 @@ import (
 )
 
 type ShareStorageConfig struct {
-	Conn        driver.Conn
-	ClusterName string
-	Database    string
+	Conn                driver.Conn
+	ClusterName         string
+	Database            string
+	InsertDeduplication bool // шары с UUID, уже сохраненными в shares, не вставляются; пакетная вставка с токеном дедупликации по UUID шар
 }
 
 type ClickhouseShareStorage struct {
-	conn        driver.Conn
-	clusterName string
-	database    string
+	conn                driver.Conn
+	clusterName         string
+	database            string
+	insertDeduplication bool
 }
 
 func NewClickhouseShareStorage(cfg ShareStorageConfig) (*ClickhouseShareStorage, error) {
 	s := &ClickhouseShareStorage{
-		conn:        cfg.Conn,
-		clusterName: cfg.ClusterName,
-		database:    cfg.Database,
+		conn:                cfg.Conn,
+		clusterName:         cfg.ClusterName,
+		database:            cfg.Database,
+		insertDeduplication: cfg.InsertDeduplication,
 	}
 	return s, nil
 }
 
+// Ping проверка доступности ClickHouse
+func (c *ClickhouseShareStorage) Ping(ctx context.Context) error {
+	return c.conn.Ping(ctx)
+}
+
 // AddShare Добавление единичной шары (для теста, в основном коде не используется, используется пакетная вставка)
 func (c *ClickhouseShareStorage) AddShare(ctx context.Context, share entity.Share) error {
 
@@ -73,37 +81,65 @@ func (c *ClickhouseShareStorage) GetShareRow(ctx context.Context, shareUUID stri
 }
 
 // AddSharesBatch пакетная вставка
+// при включенной дедупликации шары, уже сохраненные ранее, не вставляются и возвращаются в *entity.PartialSaveError с entity.ErrDuplicateShare;
+// проверка и вставка не атомарны: одна и та же шара, одновременно вставляемая разными экземплярами сервиса, может задвоиться
 func (c *ClickhouseShareStorage) AddSharesBatch(ctx context.Context, shares []entity.Share) error {
 
-	// Открытие пакетной вставки
-	batch, err := c.conn.PrepareBatch(ctx, "INSERT INTO shares (uuid, server_id, coin_id, worker_id, wallet_id, share_date, difficulty, sharedif, nonce, is_solo, reward_method, cost) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
-	if err != nil {
-		return err
+	// Шары, уже сохраненные ранее (повторное чтение из Кафки, replay -reprocess), не вставляются;
+	// повторная вставка пакета с теми же шарами (ретрай после таймаута) отбрасывается ClickHouse по токену
+	var duplicates []*entity.ShareError
+	if c.insertDeduplication {
+		var err error
+		if shares, duplicates, err = c.withoutStored(ctx, shares); err != nil {
+			return err
+		}
+		if len(shares) == 0 {
+			return &entity.PartialSaveError{Rejected: duplicates}
+		}
+		ctx = clickhouse.Context(ctx, clickhouse.WithSettings(clickhouse.Settings{
+			"insert_deduplication_token": batchDeduplicationToken(shares),
+		}))
 	}
 
-	// Добавление данных в пакет
-	for _, share := range shares {
-		difficulty, err := decimal.NewFromString(share.Difficulty)
-		if err != nil {
-			return err
+	// Числовые поля разбираем до открытия пакета: при ошибке данных соединение не занимается
+	type decimals struct {
+		difficulty, sharedif, cost decimal.Decimal
+	}
+	values := make([]decimals, len(shares))
+	for i, share := range shares {
+		var err error
+		if values[i].difficulty, err = decimal.NewFromString(share.Difficulty); err != nil {
+			return decimalError(share.UUID, "difficulty", share.Difficulty, err)
 		}
-		sharedif, err := decimal.NewFromString(share.Sharedif)
-		if err != nil {
-			return err
+		if values[i].sharedif, err = decimal.NewFromString(share.Sharedif); err != nil {
+			return decimalError(share.UUID, "sharedif", share.Sharedif, err)
 		}
-		cost, err := decimal.NewFromString(share.Cost)
-		if err != nil {
-			return err
+		if values[i].cost, err = decimal.NewFromString(share.Cost); err != nil {
+			return decimalError(share.UUID, "cost", share.Cost, err)
 		}
+	}
 
-		if err := batch.Append(share.UUID, share.ServerID, share.CoinID, share.WorkerID, share.WalletID, share.ShareDate, difficulty, sharedif, share.Nonce, share.IsSolo, share.RewardMethod, cost); err != nil {
-			return err
+	// Открытие пакетной вставки
+	batch, err := c.conn.PrepareBatch(ctx, "INSERT INTO shares (uuid, server_id, coin_id, worker_id, wallet_id, share_date, difficulty, sharedif, nonce, is_solo, reward_method, cost, kafka_topic, kafka_partition, kafka_offset) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return storageError(err)
+	}
+	defer batch.Abort() // возврат соединения в пул, если пакет не отправлен (после Send ничего не делает)
+
+	// Добавление данных в пакет
+	for i, share := range shares {
+		kafkaPartition, kafkaOffset := kafkaPosition(share)
+		if err := batch.Append(share.UUID, share.ServerID, share.CoinID, share.WorkerID, share.WalletID, share.ShareDate, values[i].difficulty, values[i].sharedif, share.Nonce, share.IsSolo, share.RewardMethod, values[i].cost, share.KafkaTopic, kafkaPartition, kafkaOffset); err != nil {
+			return appendError(share.UUID, err)
 		}
 	}
 
 	// Попытка выполнить пакетную вставку
 	if err := batch.Send(); err != nil {
-		return err
+		return storageError(err)
+	}
+	if len(duplicates) > 0 {
+		return &entity.PartialSaveError{Rejected: duplicates}
 	}
 
 	return nil
@@ -219,7 +255,7 @@ func (c *ClickhouseShareStorage) difficultyIntervalGroup(ctx context.Context,
 	var query bytes.Buffer
 	err = tmpl.Execute(&query, sqlSubstrings)
 	if err != nil {
-		log.Fatal(err)
+		return nil, nil, err
 	}
 
 	rows, err := c.conn.Query(ctx, query.String(), dynamicParams...)
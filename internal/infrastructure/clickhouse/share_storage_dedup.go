@@ -0,0 +1,78 @@
+package clickhouse
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+)
+
+// batchDeduplicationToken токен дедупликации вставки: хеш отсортированных UUID шар пакета
+// не зависит от порядка шар, поэтому пакет, собранный повторно из тех же шар, получает тот же токен
+// токены хранятся в пределах replicated_deduplication_window последних вставок
+func batchDeduplicationToken(shares []entity.Share) string {
+	uuids := make([]string, 0, len(shares))
+	for _, share := range shares {
+		uuids = append(uuids, share.UUID)
+	}
+	sort.Strings(uuids)
+
+	h := sha256.New()
+	for _, uuid := range uuids {
+		h.Write([]byte(uuid))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// withoutStored исключение из пакета шар, UUID которых уже есть в таблице shares
+// поиск ограничен диапазоном share_date пакета (ключ сортировки и партиционирования таблицы)
+// возвращает оставшиеся шары и отброшенные с entity.ErrDuplicateShare
+func (c *ClickhouseShareStorage) withoutStored(ctx context.Context, shares []entity.Share) ([]entity.Share, []*entity.ShareError, error) {
+	if len(shares) == 0 {
+		return shares, nil, nil
+	}
+
+	uuids := make([]string, 0, len(shares))
+	from, to := shares[0].ShareDate, shares[0].ShareDate
+	for _, share := range shares {
+		uuids = append(uuids, share.UUID)
+		from, to = min(from, share.ShareDate), max(to, share.ShareDate)
+	}
+
+	query := `SELECT DISTINCT uuid FROM shares WHERE share_date >= ? AND share_date <= ? AND has(?, uuid)`
+	rows, err := c.conn.Query(ctx, query, time.UnixMilli(from), time.UnixMilli(to), uuids)
+	if err != nil {
+		return nil, nil, fmt.Errorf("stored uuids: %w", storageError(err))
+	}
+	defer rows.Close()
+
+	stored := make(map[string]struct{})
+	for rows.Next() {
+		var uuid string
+		if err := rows.Scan(&uuid); err != nil {
+			return nil, nil, fmt.Errorf("stored uuids: %w", err)
+		}
+		stored[uuid] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("stored uuids: %w", storageError(err))
+	}
+
+	rest := shares[:0:0]
+	var rejected []*entity.ShareError
+	for _, share := range shares {
+		if _, ok := stored[share.UUID]; ok {
+			rejected = append(rejected, &entity.ShareError{UUID: share.UUID, Err: entity.ErrDuplicateShare})
+			continue
+		}
+		rest = append(rest, share)
+	}
+
+	return rest, rejected, nil
+}
@@ -1,30 +1,90 @@
 package clickhouse
 
 import (
+	"bytes"
 	"context"
-	"crypto/rand"
-	"encoding/binary"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
 )
 
-func (c *ClickhouseShareStorage) CoinHashrate(ctx context.Context, coinSymbol string) (uint64, error) {
-	var num uint64
-	binary.Read(rand.Reader, binary.LittleEndian, &num)
-	num = num % 65536
-
-	return num, nil
+// CoinDifficultyWindowSums суммы сложностей шар по монете за скользящие окна, заканчивающиеся в periodEnd
+// Возвращает суммы в порядке windows
+func (c *ClickhouseShareStorage) CoinDifficultyWindowSums(ctx context.Context, coinID int64, periodEnd time.Time, windows []time.Duration) ([]float64, error) {
+	_, sums, err := c.difficultyWindowSums(ctx, periodEnd, windows, "coin_id", coinID)
+	return sums, err
 }
 
-func (c *ClickhouseShareStorage) MinerHashrate(ctx context.Context, walletID int64) (uint64, error) {
-	var num uint64
-	binary.Read(rand.Reader, binary.LittleEndian, &num)
+// WalletDifficultyWindowSums суммы сложностей шар майнера (кошелька) за скользящие окна, заканчивающиеся в periodEnd
+// Возвращает код монеты кошелька (0 - если шар за период нет) и суммы в порядке windows
+func (c *ClickhouseShareStorage) WalletDifficultyWindowSums(ctx context.Context, walletID int64, periodEnd time.Time, windows []time.Duration) (int64, []float64, error) {
+	return c.difficultyWindowSums(ctx, periodEnd, windows, "wallet_id", walletID)
+}
 
-	return num, nil
+// WorkerDifficultyWindowSums суммы сложностей шар воркера за скользящие окна, заканчивающиеся в periodEnd
+// Возвращает код монеты воркера (0 - если шар за период нет) и суммы в порядке windows
+func (c *ClickhouseShareStorage) WorkerDifficultyWindowSums(ctx context.Context, workerID int64, periodEnd time.Time, windows []time.Duration) (int64, []float64, error) {
+	return c.difficultyWindowSums(ctx, periodEnd, windows, "worker_id", workerID)
 }
 
-func (c *ClickhouseShareStorage) WorkerHashrate(ctx context.Context, workerID int64) (uint64, error) {
-	var num uint64
-	binary.Read(rand.Reader, binary.LittleEndian, &num)
-	num = num % 65536
+// difficultyWindowSums суммирование сложностей за несколько окон одним запросом
+// окно - (periodEnd - window, periodEnd]
+// дополнительно возвращает код монеты отобранных шар
+func (c *ClickhouseShareStorage) difficultyWindowSums(ctx context.Context,
+	periodEnd time.Time, // окончание всех окон
+	windows []time.Duration, // длительности окон
+	filterField string, // поле отбора (coin_id, wallet_id, worker_id)
+	filterValue int64, // значение поля отбора
+) (int64, []float64, error) {
+
+	if len(windows) == 0 {
+		return 0, nil, nil
+	}
+
+	queryTemplate := `SELECT any(coin_id), {{.sums}}
+			  FROM shares WHERE share_date > ? AND share_date <= ? AND {{.field}} = ?`
+
+	var sums []string       // суммы по каждому окну
+	var dynamicParams []any // параметры в SQL запросе (подставляются вместо знака "?")
+
+	maxWindow := windows[0]
+	for _, w := range windows {
+		sums = append(sums, "toFloat64(sumIf(difficulty, share_date > ?))")
+		dynamicParams = append(dynamicParams, periodEnd.Add(-w))
+		if w > maxWindow {
+			maxWindow = w
+		}
+	}
+	dynamicParams = append(dynamicParams, periodEnd.Add(-maxWindow), periodEnd, filterValue)
+
+	sqlSubstrings := map[string]string{
+		"sums":  strings.Join(sums, ", "),
+		"field": filterField,
+	}
+
+	tmpl, err := template.New("query").Parse(queryTemplate)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var query bytes.Buffer
+	err = tmpl.Execute(&query, sqlSubstrings)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var coinID int64
+	result := make([]float64, len(windows))
+	dest := []any{&coinID}
+	for i := range result {
+		dest = append(dest, &result[i])
+	}
+
+	err = c.conn.QueryRow(ctx, query.String(), dynamicParams...).Scan(dest...)
+	if err != nil {
+		return 0, nil, fmt.Errorf("difficultyWindowSums %s=%d: %w", filterField, filterValue, err)
+	}
 
-	return num, nil
+	return coinID, result, nil
 }
@@ -0,0 +1,50 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+)
+
+// AddNonceReplays сохранение обнаруженных повторов nonce
+func (c *ClickhouseShareStorage) AddNonceReplays(ctx context.Context, replays []entity.NonceReplay) error {
+	batch, err := c.conn.PrepareBatch(ctx, "INSERT INTO nonce_replays (share_uuid, original_uuid, server_id, coin_id, worker_id, wallet_id, share_date, nonce, rejected) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return storageError(err)
+	}
+
+	for _, r := range replays {
+		if err := batch.Append(r.ShareUUID, r.OriginalUUID, r.ServerID, r.CoinID, r.WorkerID, r.WalletID, r.ShareDate, r.Nonce, r.Rejected); err != nil {
+			return err
+		}
+	}
+
+	return storageError(batch.Send())
+}
+
+// WalletNonceReplayCounts количество повторов nonce по воркерам кошелька в диапазоне periodStart <= share_date < periodEnd
+func (c *ClickhouseShareStorage) WalletNonceReplayCounts(ctx context.Context, walletID int64, periodStart time.Time, periodEnd time.Time) ([]entity.NonceReplayCount, error) {
+	query := `SELECT worker_id, count(), countIf(rejected)
+			  FROM nonce_replays FINAL
+			  WHERE wallet_id = ? AND share_date >= ? AND share_date < ?
+			  GROUP BY worker_id ORDER BY worker_id`
+
+	rows, err := c.conn.Query(ctx, query, walletID, periodStart, periodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("WalletNonceReplayCounts: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []entity.NonceReplayCount
+	for rows.Next() {
+		var cnt entity.NonceReplayCount
+		if err := rows.Scan(&cnt.WorkerID, &cnt.Count, &cnt.Rejected); err != nil {
+			return nil, fmt.Errorf("WalletNonceReplayCounts: %w", err)
+		}
+		counts = append(counts, cnt)
+	}
+
+	return counts, rows.Err()
+}
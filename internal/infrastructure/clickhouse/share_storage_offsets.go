@@ -0,0 +1,32 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+)
+
+// LastOffset последнее сохраненное вместе с шарами смещение партиции топика Кафки
+// found = false - шар из этой партиции еще не сохранялось
+func (c *ClickhouseShareStorage) LastOffset(ctx context.Context, topic string, partition int32) (int64, bool, error) {
+	query := `SELECT count(), max(max_offset) FROM share_offsets WHERE topic = ? AND partition = ?`
+
+	var cnt uint64
+	var offset int64
+	err := c.conn.QueryRow(ctx, query, topic, partition).Scan(&cnt, &offset)
+	if err != nil {
+		return 0, false, fmt.Errorf("LastOffset %s/%d: %w", topic, partition, storageError(err))
+	}
+
+	return offset, cnt > 0, nil
+}
+
+// kafkaPosition партиция и смещение шары для вставки (-1, если шара получена не из Кафки)
+func kafkaPosition(share entity.Share) (int32, int64) {
+	if share.KafkaTopic == "" {
+		return -1, -1
+	}
+
+	return share.KafkaPartition, share.KafkaOffset
+}
@@ -0,0 +1,114 @@
+package clickhouse
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+)
+
+type testBatch struct {
+	driver.Batch
+	appendErr error
+	aborted   bool
+	sent      bool
+}
+
+func (b *testBatch) Append(v ...any) error { return b.appendErr }
+
+func (b *testBatch) Abort() error {
+	b.aborted = true
+	return nil
+}
+
+func (b *testBatch) Send() error {
+	b.sent = true
+	return nil
+}
+
+type testRows struct {
+	driver.Rows
+	uuids []string
+}
+
+func (r *testRows) Next() bool { return len(r.uuids) > 0 }
+
+func (r *testRows) Scan(dest ...any) error {
+	*dest[0].(*string) = r.uuids[0]
+	r.uuids = r.uuids[1:]
+	return nil
+}
+
+func (r *testRows) Err() error   { return nil }
+func (r *testRows) Close() error { return nil }
+
+type testConn struct {
+	driver.Conn
+	prepared int
+	batch    *testBatch
+	stored   []string // UUID, уже сохраненные в shares
+}
+
+func (c *testConn) Query(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+	return &testRows{uuids: c.stored}, nil
+}
+
+func (c *testConn) PrepareBatch(ctx context.Context, query string, opts ...driver.PrepareBatchOption) (driver.Batch, error) {
+	c.prepared++
+	return c.batch, nil
+}
+
+func TestAddSharesBatchReleasesConn(t *testing.T) {
+	conn := &testConn{batch: &testBatch{}}
+	s, err := NewClickhouseShareStorage(ShareStorageConfig{Conn: conn})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// ошибка в числовом поле - пакет не открывается
+	err = s.AddSharesBatch(ctx, []entity.Share{
+		{UUID: "1", Difficulty: "1", Sharedif: "1", Cost: "0"},
+		{UUID: "2", Difficulty: "x", Sharedif: "1", Cost: "0"},
+	})
+	require.ErrorIs(t, err, entity.ErrInvalidDecimal)
+	require.Zero(t, conn.prepared)
+
+	// ошибка добавления - шара некорректна, пакет прерывается
+	conn.batch.appendErr = errors.New("converting string to UInt8 is unsupported")
+	err = s.AddSharesBatch(ctx, []entity.Share{{UUID: "3", Difficulty: "1", Sharedif: "1", Cost: "0"}})
+	var shareErr *entity.ShareError
+	require.ErrorAs(t, err, &shareErr)
+	require.Equal(t, "3", shareErr.UUID)
+	require.ErrorIs(t, err, entity.ErrInvalidShare)
+	require.True(t, conn.batch.aborted)
+	require.False(t, conn.batch.sent)
+}
+
+func TestAddSharesBatchSkipsStored(t *testing.T) {
+	conn := &testConn{batch: &testBatch{}, stored: []string{"1"}}
+	s, err := NewClickhouseShareStorage(ShareStorageConfig{Conn: conn, InsertDeduplication: true})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// уже сохраненная шара не вставляется и возвращается как дубликат
+	err = s.AddSharesBatch(ctx, []entity.Share{
+		{UUID: "1", Difficulty: "1", Sharedif: "1", Cost: "0"},
+		{UUID: "2", Difficulty: "1", Sharedif: "1", Cost: "0"},
+	})
+	var partial *entity.PartialSaveError
+	require.ErrorAs(t, err, &partial)
+	require.Equal(t, []string{"1"}, partial.RejectedUUIDs())
+	require.ErrorIs(t, partial.Rejected[0], entity.ErrDuplicateShare)
+	require.True(t, conn.batch.sent)
+
+	// все шары пакета сохранены - пакет не открывается
+	conn.stored = []string{"1", "2"}
+	conn.prepared = 0
+	err = s.AddSharesBatch(ctx, []entity.Share{{UUID: "1"}, {UUID: "2"}})
+	require.ErrorAs(t, err, &partial)
+	require.Equal(t, []string{"1", "2"}, partial.RejectedUUIDs())
+	require.Zero(t, conn.prepared)
+}
@@ -0,0 +1,80 @@
+// Package discovery реализует статический реестр сервисов для автономного режима (без etcd)
+package discovery
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+)
+
+// StaticRegistry реестр сервисов с адресами из конфига
+// повторяет интерфейс servicediscovery.ServiceDiscovery: регистрация хранится только в памяти,
+// ожидание зависимостей не блокирует
+type StaticRegistry struct {
+	mu       sync.RWMutex
+	services map[string]string // ключ сервиса -> хост:порт
+}
+
+// NewStaticRegistry реестр с заранее известными адресами сервисов (ключ сервиса -> хост:порт)
+func NewStaticRegistry(services map[string]string) *StaticRegistry {
+	r := &StaticRegistry{services: make(map[string]string, len(services))}
+	for key, addr := range services {
+		r.services[key] = addr
+	}
+
+	return r
+}
+
+// RegisterService регистрация сервиса экземпляра
+func (r *StaticRegistry) RegisterService(serviceKey, serviceAddr string) error {
+	if serviceKey == "" {
+		return fmt.Errorf("empty service key")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.services[serviceKey] = serviceAddr
+
+	return nil
+}
+
+// WaitDependencies проверка зависимостей без ожидания: отсутствующие в реестре только логируются
+func (r *StaticRegistry) WaitDependencies(dependencies []string) {
+	if missing := r.Missing(dependencies); len(missing) > 0 {
+		logger.Log().Warn("Standalone mode, dependencies without static address: " + strings.Join(missing, ", "))
+	}
+}
+
+// Missing зависимости, которых нет в реестре
+func (r *StaticRegistry) Missing(dependencies []string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var missing []string
+	for _, dep := range dependencies {
+		if _, ok := r.services[dep]; !ok {
+			missing = append(missing, dep)
+		}
+	}
+
+	return missing
+}
+
+// DiscoverAllServices копия всех сервисов реестра
+func (r *StaticRegistry) DiscoverAllServices() (map[string]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	services := make(map[string]string, len(r.services))
+	for key, addr := range r.services {
+		services[key] = addr
+	}
+
+	return services, nil
+}
+
+// Close ничего не освобождает (нет соединений и аренд)
+func (r *StaticRegistry) Close() {}
@@ -0,0 +1,33 @@
+package discovery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+)
+
+func TestStaticRegistry(t *testing.T) {
+	logger.InitLogger(logger.LogLevelProduction, t.TempDir()+"/test.log")
+
+	static := map[string]string{"miners_processor:grpc": "127.0.0.1:7878"}
+	r := NewStaticRegistry(static)
+	static["miners_processor:grpc"] = "changed"
+
+	require.NoError(t, r.RegisterService("SHARES_PROCESSOR_LOCAL:grpc", "127.0.0.1:6878"))
+	require.Error(t, r.RegisterService("", "127.0.0.1:6878"))
+
+	services, err := r.DiscoverAllServices()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"miners_processor:grpc":       "127.0.0.1:7878",
+		"SHARES_PROCESSOR_LOCAL:grpc": "127.0.0.1:6878",
+	}, services)
+
+	// WaitDependencies не блокирует при отсутствующих зависимостях
+	r.WaitDependencies([]string{"miners_processor:grpc", "coins:grpc"})
+	require.Equal(t, []string{"coins:grpc"}, r.Missing([]string{"miners_processor:grpc", "coins:grpc"}))
+
+	r.Close()
+}
@@ -31,7 +31,7 @@ func LoadRemoteConfig(basePath string, startConf configloader.StartConfig, logge
 
 	confText, err := confLoader.LoadRemoteConfig(remoteDataKey)
 	if err != nil { // Если удаленный конфиг не загрузился - логируем ошибку и загружаем локальный вариант
-		return fmt.Errorf("no remote configs load: %w", err.Error())
+		return fmt.Errorf("no remote configs load: %w", err)
 	}
 
 	// если нормально загрузился - сохраняем в локальный файл
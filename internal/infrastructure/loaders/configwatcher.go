@@ -0,0 +1,74 @@
+package loaders
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/constants"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+)
+
+// watchRetryInterval пауза перед повторным чтением конфига после ошибки etcd
+const watchRetryInterval = 5 * time.Second
+
+// WatchRemoteConfig отслеживание изменений конфига приложения appID в etcd до завершения ctx
+// onChange вызывается с текстом конфига при запуске (текущее значение) и при каждой записи ключа
+// при обрыве подписки конфиг перечитывается заново
+func WatchRemoteConfig(ctx context.Context, client *clientv3.Client, appID string, onChange func(data []byte)) {
+	key := constants.ServiceConfigPath + "/" + appID
+
+	for {
+		rev, err := loadConfigKey(ctx, client, key, onChange)
+		if err != nil {
+			logger.Log().Error(err.Error())
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(watchRetryInterval):
+			}
+			continue
+		}
+
+		watchCtx, watchCancel := context.WithCancel(ctx)
+		watchCh := client.Watch(watchCtx, key, clientv3.WithRev(rev+1))
+
+		reload := false
+		for !reload {
+			select {
+			case <-ctx.Done():
+				watchCancel()
+				return
+			case resp, ok := <-watchCh:
+				if !ok || resp.Err() != nil {
+					reload = true
+					break
+				}
+				for _, ev := range resp.Events {
+					if ev.Type == clientv3.EventTypePut {
+						onChange(ev.Kv.Value)
+					}
+				}
+			}
+		}
+		watchCancel()
+	}
+}
+
+// loadConfigKey чтение текущего конфига, возвращает ревизию etcd для подписки на изменения
+func loadConfigKey(ctx context.Context, client *clientv3.Client, key string, onChange func(data []byte)) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	resp, err := client.Get(ctx, key)
+	if err != nil {
+		return 0, fmt.Errorf("config watch %s: %w", key, err)
+	}
+	if len(resp.Kvs) > 0 {
+		onChange(resp.Kvs[0].Value)
+	}
+
+	return resp.Header.Revision, nil
+}
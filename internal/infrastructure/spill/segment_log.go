@@ -0,0 +1,401 @@
+// Package spill реализует локальный буфер пакетов шар на диске на время недоступности ClickHouse
+package spill
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+)
+
+// DefaultSegmentBytes размер сегмента, после которого запись идет в новый сегмент
+const DefaultSegmentBytes = 64 << 20
+
+const segmentExt = ".seg"
+
+const (
+	cursorFile    = "drain.cursor" // позиция вычитки: номер сегмента и количество перенесенных строк
+	quarantineDir = "quarantine"   // строки, которые не удалось перенести в хранилище
+)
+
+// ErrFull суммарный размер сегментов достиг максимального
+var ErrFull = errors.New("spill: segment log is full")
+
+// Record строка сегмента: пакет шар или нераспознанные данные (Err != nil)
+type Record struct {
+	Line   int // номер строки в сегменте, с 0
+	Shares []entity.Share
+	Data   []byte // исходная строка
+	Err    error  // ошибка разбора строки
+}
+
+// SegmentLog журнал пакетов шар из последовательно нумерованных сегментов (<номер>.seg)
+// каждый пакет - одна строка JSON; запись только в конец последнего (активного) сегмента
+// позиция вычитки хранится в drain.cursor, чтобы после сбоя перенесенные строки не вставлялись повторно
+type SegmentLog struct {
+	mu           sync.Mutex
+	dir          string
+	maxBytes     int64 // максимальный суммарный размер сегментов (0 - без ограничения)
+	segmentBytes int64
+
+	segments   []uint64 // номера сегментов по возрастанию, последний - активный (если открыт)
+	active     *os.File // nil - активного сегмента нет, следующий пакет откроет новый
+	activeSize int64
+	size       int64 // суммарный размер сегментов
+
+	cursorSeq  uint64 // сегмент, вычитка которого начата
+	cursorLine int    // количество перенесенных строк сегмента cursorSeq
+}
+
+// OpenSegmentLog открытие журнала в каталоге dir, ранее записанные сегменты сохраняются для вычитки
+func OpenSegmentLog(dir string, maxBytes int64, segmentBytes int64) (*SegmentLog, error) {
+	if segmentBytes <= 0 {
+		segmentBytes = DefaultSegmentBytes
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("spill: %w", err)
+	}
+
+	l := &SegmentLog{
+		dir:          dir,
+		maxBytes:     maxBytes,
+		segmentBytes: segmentBytes,
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("spill: %w", err)
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), segmentExt) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(e.Name(), segmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return nil, fmt.Errorf("spill: %w", err)
+		}
+		l.segments = append(l.segments, seq)
+		l.size += info.Size()
+	}
+	sort.Slice(l.segments, func(i, j int) bool { return l.segments[i] < l.segments[j] })
+
+	if err := l.readCursor(); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Append запись пакета шар в конец журнала (с fsync)
+// при ошибке записи недописанная строка удаляется, следующий пакет пишется с начала строки
+func (l *SegmentLog) Append(shares []entity.Share) error {
+	data, err := json.Marshal(shares)
+	if err != nil {
+		return fmt.Errorf("spill: %w", err)
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.maxBytes > 0 && l.size+int64(len(data)) > l.maxBytes {
+		return ErrFull
+	}
+
+	if l.active != nil && l.activeSize >= l.segmentBytes {
+		if err := l.roll(); err != nil {
+			return err
+		}
+	}
+	if l.active == nil {
+		if err := l.openNext(); err != nil {
+			return err
+		}
+	}
+
+	n, err := l.active.Write(data)
+	if err == nil {
+		err = l.active.Sync()
+	}
+	if err != nil {
+		l.discardTail(n)
+		return fmt.Errorf("spill: %w", err)
+	}
+	l.activeSize += int64(n)
+	l.size += int64(n)
+
+	return nil
+}
+
+// discardTail отмена неподтвержденной записи n байт в активный сегмент: сегмент обрезается до размера перед записью
+// если обрезать не удалось, сегмент закрывается и следующий пакет пойдет в новый,
+// иначе он был бы дописан в одну строку с недописанным
+func (l *SegmentLog) discardTail(n int) {
+	if n == 0 {
+		return
+	}
+	if err := l.active.Truncate(l.activeSize); err == nil {
+		return
+	}
+
+	l.activeSize += int64(n)
+	l.size += int64(n)
+	_ = l.roll()
+}
+
+// Oldest номер самого старого сегмента для вычитки
+// активный сегмент перед вычиткой закрывается, новые пакеты пойдут в следующий
+func (l *SegmentLog) Oldest() (uint64, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.segments) == 0 {
+		return 0, false, nil
+	}
+	if l.active != nil && len(l.segments) == 1 {
+		if err := l.roll(); err != nil {
+			return 0, false, err
+		}
+	}
+
+	return l.segments[0], true, nil
+}
+
+// ReadSegment строки сегмента в порядке записи
+// нераспознанная строка возвращается с Err, а не прерывает чтение: ее нужно отложить в карантин
+// недописанный последний пакет (сбой при записи) пропускается: его запись не была подтверждена
+func (l *SegmentLog) ReadSegment(seq uint64) ([]Record, error) {
+	f, err := os.Open(l.path(seq))
+	if err != nil {
+		return nil, fmt.Errorf("spill: %w", err)
+	}
+	defer f.Close()
+
+	var records []Record
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("spill: %w", err)
+		}
+
+		rec := Record{Line: len(records), Data: line}
+		if err := json.Unmarshal(line, &rec.Shares); err != nil {
+			rec.Shares, rec.Err = nil, fmt.Errorf("spill: segment %d line %d: %w", seq, rec.Line, err)
+		}
+		records = append(records, rec)
+	}
+}
+
+// Cursor количество уже перенесенных строк сегмента seq
+func (l *SegmentLog) Cursor(seq uint64) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if seq != l.cursorSeq {
+		return 0
+	}
+	return l.cursorLine
+}
+
+// Commit сохранение позиции вычитки (с fsync): перенесены строки сегмента seq до line (не включая)
+func (l *SegmentLog) Commit(seq uint64, line int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.writeFile(cursorFile, []byte(fmt.Sprintf("%d %d\n", seq, line))); err != nil {
+		return err
+	}
+	l.cursorSeq, l.cursorLine = seq, line
+
+	return nil
+}
+
+// Quarantine сохранение строки сегмента, которую не удалось перенести, в каталог quarantine для разбора
+func (l *SegmentLog) Quarantine(seq uint64, line int, data []byte) error {
+	if err := os.MkdirAll(filepath.Join(l.dir, quarantineDir), 0o755); err != nil {
+		return fmt.Errorf("spill: %w", err)
+	}
+
+	return l.writeFile(filepath.Join(quarantineDir, fmt.Sprintf("%020d-%06d.json", seq, line)), data)
+}
+
+// QuarantineLen количество строк в каталоге quarantine (ошибка чтения каталога - 0)
+func (l *SegmentLog) QuarantineLen() int {
+	entries, err := os.ReadDir(filepath.Join(l.dir, quarantineDir))
+	if err != nil {
+		return 0
+	}
+
+	n := 0
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			n++
+		}
+	}
+
+	return n
+}
+
+// RemoveSegment удаление вычитанного сегмента
+func (l *SegmentLog) RemoveSegment(seq uint64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.active != nil && seq == l.segments[len(l.segments)-1] {
+		return fmt.Errorf("spill: segment %d is active", seq)
+	}
+
+	info, err := os.Stat(l.path(seq))
+	if err != nil {
+		return fmt.Errorf("spill: %w", err)
+	}
+	if err := os.Remove(l.path(seq)); err != nil {
+		return fmt.Errorf("spill: %w", err)
+	}
+
+	l.size -= info.Size()
+	if seq == l.cursorSeq {
+		l.cursorSeq, l.cursorLine = 0, 0
+		if err := os.Remove(filepath.Join(l.dir, cursorFile)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("spill: %w", err)
+		}
+	}
+	for i, s := range l.segments {
+		if s == seq {
+			l.segments = append(l.segments[:i], l.segments[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// Size суммарный размер сегментов в байтах
+func (l *SegmentLog) Size() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.size
+}
+
+// Empty нет пакетов для вычитки
+func (l *SegmentLog) Empty() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.segments) == 0
+}
+
+func (l *SegmentLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.active == nil {
+		return nil
+	}
+	err := l.active.Close()
+	l.active = nil
+
+	return err
+}
+
+// openNext открытие нового активного сегмента
+func (l *SegmentLog) openNext() error {
+	var seq uint64 = 1
+	if len(l.segments) > 0 {
+		seq = l.segments[len(l.segments)-1] + 1
+	}
+
+	f, err := os.OpenFile(l.path(seq), os.O_CREATE|os.O_EXCL|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("spill: %w", err)
+	}
+
+	l.active = f
+	l.activeSize = 0
+	l.segments = append(l.segments, seq)
+
+	return nil
+}
+
+// roll закрытие активного сегмента
+func (l *SegmentLog) roll() error {
+	err := l.active.Close()
+	l.active = nil
+	if err != nil {
+		return fmt.Errorf("spill: %w", err)
+	}
+
+	return nil
+}
+
+// readCursor загрузка позиции вычитки, позиция удаленного сегмента не учитывается
+func (l *SegmentLog) readCursor() error {
+	data, err := os.ReadFile(filepath.Join(l.dir, cursorFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("spill: %w", err)
+	}
+
+	var seq uint64
+	var line int
+	if _, err := fmt.Sscanf(string(data), "%d %d", &seq, &line); err != nil {
+		return fmt.Errorf("spill: %s: %w", cursorFile, err)
+	}
+	for _, s := range l.segments {
+		if s == seq {
+			l.cursorSeq, l.cursorLine = seq, line
+		}
+	}
+
+	return nil
+}
+
+// writeFile атомарная запись файла в каталоге журнала (через временный файл, с fsync)
+func (l *SegmentLog) writeFile(name string, data []byte) error {
+	path := filepath.Join(l.dir, name)
+	tmp := path + ".tmp"
+
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("spill: %w", err)
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		return fmt.Errorf("spill: %w", err)
+	}
+
+	return nil
+}
+
+func (l *SegmentLog) path(seq uint64) string {
+	return filepath.Join(l.dir, fmt.Sprintf("%020d%s", seq, segmentExt))
+}
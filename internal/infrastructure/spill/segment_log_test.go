@@ -0,0 +1,108 @@
+package spill
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSegmentLogFull(t *testing.T) {
+	l, err := OpenSegmentLog(t.TempDir(), 400, 0)
+	require.NoError(t, err)
+	defer l.Close()
+
+	require.NoError(t, l.Append(batch("1")))
+	require.ErrorIs(t, l.Append(batch("2", "3", "4")), ErrFull)
+
+	// недописанный последний пакет пропускается
+	seq, ok, err := l.Oldest()
+	require.NoError(t, err)
+	require.True(t, ok)
+	f, err := os.OpenFile(l.path(seq), os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`[{"UUID":"torn"`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	batches, err := l.ReadSegment(seq)
+	require.NoError(t, err)
+	require.Len(t, batches, 1)
+	require.Equal(t, "1", batches[0].Shares[0].UUID)
+}
+
+func TestSegmentLogDiscardTail(t *testing.T) {
+	l, err := OpenSegmentLog(t.TempDir(), 0, 0)
+	require.NoError(t, err)
+	defer l.Close()
+
+	require.NoError(t, l.Append(batch("1")))
+	size := l.Size()
+
+	// ошибка записи после части строки: хвост обрезается, следующий пакет пишется с начала строки
+	n, err := l.active.WriteString(`[{"UUID":"torn"`)
+	require.NoError(t, err)
+	l.discardTail(n)
+	require.Equal(t, size, l.Size())
+	require.NoError(t, l.Append(batch("2")))
+
+	// обрезать не удалось - следующий пакет идет в новый сегмент
+	n, err = l.active.WriteString(`[{"UUID":"torn"`)
+	require.NoError(t, err)
+	active := l.active
+	require.NoError(t, active.Close())
+	l.discardTail(n)
+	require.Nil(t, l.active)
+	require.NoError(t, l.Append(batch("3")))
+
+	var uuids []string
+	for _, seq := range l.segments {
+		records, err := l.ReadSegment(seq)
+		require.NoError(t, err)
+		for _, rec := range records {
+			require.NoError(t, rec.Err)
+			uuids = append(uuids, rec.Shares[0].UUID)
+		}
+	}
+	require.Equal(t, []string{"1", "2", "3"}, uuids)
+}
+
+func TestSegmentLogCursor(t *testing.T) {
+	dir := t.TempDir()
+	l, err := OpenSegmentLog(dir, 0, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, l.Append(batch("1")))
+	seq, _, err := l.Oldest()
+	require.NoError(t, err)
+	f, err := os.OpenFile(l.path(seq), os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString("garbage\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	// нераспознанная строка не прерывает чтение
+	records, err := l.ReadSegment(seq)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.NoError(t, records[0].Err)
+	require.Error(t, records[1].Err)
+	require.Equal(t, "garbage\n", string(records[1].Data))
+
+	// позиция вычитки сохраняется между запусками
+	require.NoError(t, l.Commit(seq, 1))
+	require.NoError(t, l.Close())
+	l, err = OpenSegmentLog(dir, 0, 0)
+	require.NoError(t, err)
+	defer l.Close()
+	require.Equal(t, 1, l.Cursor(seq))
+	require.Equal(t, 0, l.Cursor(seq+1))
+
+	require.NoError(t, l.Quarantine(seq, 1, records[1].Data))
+	require.NoError(t, l.RemoveSegment(seq))
+	require.Equal(t, 0, l.Cursor(seq))
+	require.True(t, l.Empty())
+	_, err = os.Stat(filepath.Join(dir, quarantineDir))
+	require.NoError(t, err)
+}
@@ -0,0 +1,368 @@
+package spill
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/constants"
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+)
+
+// DefaultDrainInterval интервал проверки доступности ClickHouse для вычитки журнала
+const DefaultDrainInterval = 5 * time.Second
+
+// DefaultDrainAttempts попыток перенести пакет при доступном хранилище, после - пакет уходит в карантин
+const DefaultDrainAttempts = 5
+
+type Config struct {
+	Dir           string        // каталог сегментов
+	MaxBytes      int64         // максимальный суммарный размер сегментов (0 - без ограничения)
+	SegmentBytes  int64         // размер сегмента (0 - DefaultSegmentBytes)
+	DrainInterval time.Duration // интервал проверки доступности хранилища (0 - DefaultDrainInterval)
+	DrainAttempts int           // попыток переноса пакета при доступном хранилище (0 - DefaultDrainAttempts)
+}
+
+// ShareStorage основное хранилище шар (реализуется clickhouse.ClickhouseShareStorage)
+type ShareStorage interface {
+	AddSharesBatch(ctx context.Context, shares []entity.Share) error
+	Ping(ctx context.Context) error
+	LastOffset(ctx context.Context, topic string, partition int32) (int64, bool, error) // последнее сохраненное смещение партиции Кафки
+}
+
+// BufferedShareStorage хранилище шар с буфером на диске
+// если хранилище недоступно (не отвечает на Ping), пакет пишется в журнал и считается сохраненным;
+// пока журнал не вычитан, новые пакеты тоже пишутся в журнал, чтобы не обгонять накопленные;
+// фоновая горутина переносит журнал в хранилище построчно и по порядку, как только оно снова доступно,
+// строки, которые хранилище не принимает, откладываются в карантин и не блокируют остальные
+// шары пишутся в журнал вместе с позицией в Кафке: пакет, повторно прочитанный из Кафки после сбоя
+// до подтверждения смещения, попадает в журнал второй раз, и при вычитке его шары с уже сохраненными смещениями пропускаются
+type BufferedShareStorage struct {
+	storage  ShareStorage
+	log      *SegmentLog
+	interval time.Duration
+	attempts int
+
+	spilled     atomic.Uint64 // счетчик пакетов, записанных в журнал
+	drained     atomic.Uint64 // счетчик пакетов, перенесенных из журнала в хранилище
+	skipped     atomic.Uint64 // счетчик шар журнала, пропущенных как уже сохраненные (по смещению в Кафке)
+	quarantined atomic.Uint64 // счетчик строк журнала в карантине (с учетом отложенных до запуска)
+
+	failedLine int // строка вычитываемого сегмента, перенос которой не удался (только в горутине вычитки)
+	failures   int // неудачных попыток переноса строки failedLine
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewBufferedShareStorage(cfg Config, storage ShareStorage) (*BufferedShareStorage, error) {
+	if cfg.DrainInterval <= 0 {
+		cfg.DrainInterval = DefaultDrainInterval
+	}
+	if cfg.DrainAttempts <= 0 {
+		cfg.DrainAttempts = DefaultDrainAttempts
+	}
+
+	log, err := OpenSegmentLog(cfg.Dir, cfg.MaxBytes, cfg.SegmentBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &BufferedShareStorage{
+		storage:  storage,
+		log:      log,
+		interval: cfg.DrainInterval,
+		attempts: cfg.DrainAttempts,
+	}
+	b.quarantined.Store(uint64(log.QuarantineLen()))
+
+	return b, nil
+}
+
+// AddSharesBatch вставка пакета в хранилище, при недоступности хранилища или невычитанном журнале - в журнал
+// постоянные ошибки и ошибки, не связанные с недоступностью (хранилище отвечает на Ping), возвращаются как есть
+func (b *BufferedShareStorage) AddSharesBatch(ctx context.Context, shares []entity.Share) error {
+	if !b.log.Empty() {
+		if err := b.log.Append(shares); err != nil {
+			return fmt.Errorf("%w: spill backlog: %v", entity.ErrStorageUnavailable, err)
+		}
+		b.spilled.Add(1)
+		return nil
+	}
+
+	err := b.storage.AddSharesBatch(ctx, shares)
+	if err == nil || entity.IsPermanent(err) {
+		return err
+	}
+
+	pingCtx, cancel := context.WithTimeout(context.Background(), constants.ContextTimeout*time.Second)
+	defer cancel()
+	if b.storage.Ping(pingCtx) == nil {
+		return err
+	}
+
+	if spillErr := b.log.Append(shares); spillErr != nil {
+		return fmt.Errorf("%w (spill: %v)", err, spillErr)
+	}
+	b.spilled.Add(1)
+	logger.Log().Warn(fmt.Sprintf("Storage unavailable, batch of %d shares spilled to disk (%d bytes buffered): %s", len(shares), b.log.Size(), err.Error()))
+
+	return nil
+}
+
+// Start запуск фоновой вычитки журнала
+func (b *BufferedShareStorage) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	b.cancel = cancel
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+
+		ticker := time.NewTicker(b.interval)
+		defer ticker.Stop()
+
+		for {
+			b.drain(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Close остановка вычитки, невычитанные сегменты остаются на диске до следующего запуска
+func (b *BufferedShareStorage) Close() error {
+	if b.cancel != nil {
+		b.cancel()
+	}
+	b.wg.Wait()
+
+	return b.log.Close()
+}
+
+// drain перенос сегментов журнала в хранилище по порядку, пока хранилище доступно
+// после каждой строки сохраняется позиция вычитки, сегмент удаляется после переноса всех строк
+// (при сбое между вставкой и сохранением позиции шары строки, полученные не из Кафки, могут быть вставлены повторно)
+func (b *BufferedShareStorage) drain(ctx context.Context) {
+	for ctx.Err() == nil && !b.log.Empty() {
+		pingCtx, cancel := context.WithTimeout(ctx, constants.ContextTimeout*time.Second)
+		err := b.storage.Ping(pingCtx)
+		cancel()
+		if err != nil {
+			return
+		}
+
+		seq, ok, err := b.log.Oldest()
+		if err != nil || !ok {
+			if err != nil {
+				logger.Log().Error("Spill drain error: " + err.Error())
+			}
+			return
+		}
+
+		records, err := b.log.ReadSegment(seq)
+		if err != nil {
+			logger.Log().Error("Spill drain error: " + err.Error())
+			return
+		}
+
+		for _, rec := range records[min(b.log.Cursor(seq), len(records)):] {
+			if !b.drainRecord(ctx, seq, rec) {
+				return
+			}
+			if err := b.log.Commit(seq, rec.Line+1); err != nil {
+				logger.Log().Error("Spill drain error: " + err.Error())
+				return
+			}
+			b.failedLine, b.failures = 0, 0
+		}
+
+		if err := b.log.RemoveSegment(seq); err != nil {
+			logger.Log().Error("Spill drain error: " + err.Error())
+			return
+		}
+		logger.Log().Info(fmt.Sprintf("Spill segment %d drained (%d batches), %d bytes left", seq, len(records), b.log.Size()))
+	}
+}
+
+// drainRecord перенос строки журнала в хранилище
+// нераспознанная строка, постоянная ошибка или отказ при доступном хранилище после attempts попыток - карантин
+// отклоненные при частичной вставке шары уходят в карантин, остальные сохранены
+// шары из Кафки со смещением не больше сохраненного в хранилище пропускаются (пакет записан в журнал повторно)
+// false - строку перенести не удалось, вычитка продолжится с нее на следующем цикле
+func (b *BufferedShareStorage) drainRecord(ctx context.Context, seq uint64, rec Record) (done bool) {
+	if rec.Err != nil {
+		return b.quarantine(seq, rec.Line, rec.Data, rec.Err)
+	}
+
+	shares, err := b.unstored(ctx, rec.Shares)
+	if err != nil {
+		logger.Log().Warn(fmt.Sprintf("Spill drain of segment %d paused: %s", seq, err.Error()))
+		return false
+	}
+	defer func() {
+		if done {
+			b.skipped.Add(uint64(len(rec.Shares) - len(shares)))
+		}
+	}()
+	if len(shares) == 0 {
+		b.drained.Add(1)
+		return true
+	}
+	data := rec.Data
+	if len(shares) < len(rec.Shares) {
+		if d, err := json.Marshal(shares); err == nil {
+			data = d
+		}
+	}
+
+	insertCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	err = b.storage.AddSharesBatch(insertCtx, shares)
+	cancel()
+	if err == nil {
+		b.drained.Add(1)
+		return true
+	}
+
+	var partial *entity.PartialSaveError
+	if errors.As(err, &partial) {
+		// принятые шары уже вставлены: строку нельзя повторять, даже если карантин не записался
+		b.drained.Add(1)
+		rejected := rejectedShares(shares, partial)
+		if len(rejected) == 0 {
+			return true
+		}
+		if d, marshalErr := json.Marshal(rejected); marshalErr == nil {
+			data = d
+		}
+		b.quarantine(seq, rec.Line, data, err)
+		return true
+	}
+
+	if !entity.IsPermanent(err) {
+		pingCtx, cancel := context.WithTimeout(ctx, constants.ContextTimeout*time.Second)
+		pingErr := b.storage.Ping(pingCtx)
+		cancel()
+		if pingErr != nil {
+			logger.Log().Warn(fmt.Sprintf("Spill drain of segment %d paused, storage unavailable: %s", seq, err.Error()))
+			return false
+		}
+
+		if b.failedLine != rec.Line {
+			b.failedLine, b.failures = rec.Line, 0
+		}
+		b.failures++
+		if b.failures < b.attempts {
+			logger.Log().Error(fmt.Sprintf("Spill drain of segment %d line %d error (attempt %d of %d): %s", seq, rec.Line, b.failures, b.attempts, err.Error()))
+			return false
+		}
+	}
+
+	return b.quarantine(seq, rec.Line, data, err)
+}
+
+// unstored шары пакета, которых еще нет в хранилище: смещение шары из Кафки больше последнего сохраненного в ее партиции
+// шары не из Кафки возвращаются все
+func (b *BufferedShareStorage) unstored(ctx context.Context, shares []entity.Share) ([]entity.Share, error) {
+	type partitionKey struct {
+		topic     string
+		partition int32
+	}
+	last := make(map[partitionKey]int64)
+
+	result := shares[:0:0]
+	for _, sh := range shares {
+		if sh.KafkaTopic == "" {
+			result = append(result, sh)
+			continue
+		}
+
+		key := partitionKey{topic: sh.KafkaTopic, partition: sh.KafkaPartition}
+		offset, ok := last[key]
+		if !ok {
+			queryCtx, cancel := context.WithTimeout(ctx, constants.ContextTimeout*time.Second)
+			stored, found, err := b.storage.LastOffset(queryCtx, sh.KafkaTopic, sh.KafkaPartition)
+			cancel()
+			if err != nil {
+				return nil, err
+			}
+			offset = -1
+			if found {
+				offset = stored
+			}
+			last[key] = offset
+		}
+
+		if sh.KafkaOffset <= offset {
+			continue
+		}
+		result = append(result, sh)
+	}
+
+	return result, nil
+}
+
+// quarantine перенос строки журнала в карантин, false - записать не удалось
+func (b *BufferedShareStorage) quarantine(seq uint64, line int, data []byte, reason error) bool {
+	if err := b.log.Quarantine(seq, line, data); err != nil {
+		logger.Log().Error("Spill quarantine error: " + err.Error())
+		return false
+	}
+	b.quarantined.Add(1)
+	logger.Log().Error(fmt.Sprintf("Spill segment %d line %d quarantined: %s", seq, line, reason.Error()))
+
+	return true
+}
+
+// rejectedShares шары пакета, отклоненные хранилищем при частичной вставке (дубликаты уже сохраненных не в счет)
+func rejectedShares(shares []entity.Share, partial *entity.PartialSaveError) []entity.Share {
+	rejected := make(map[string]struct{}, len(partial.Rejected))
+	for _, r := range partial.Rejected {
+		if !errors.Is(r, entity.ErrDuplicateShare) {
+			rejected[r.UUID] = struct{}{}
+		}
+	}
+
+	var result []entity.Share
+	for _, sh := range shares {
+		if _, ok := rejected[sh.UUID]; ok {
+			result = append(result, sh)
+		}
+	}
+
+	return result
+}
+
+// SpilledCount количество пакетов, записанных в журнал с момента запуска
+func (b *BufferedShareStorage) SpilledCount() uint64 {
+	return b.spilled.Load()
+}
+
+// DrainedCount количество пакетов, перенесенных из журнала в хранилище с момента запуска
+func (b *BufferedShareStorage) DrainedCount() uint64 {
+	return b.drained.Load()
+}
+
+// SkippedCount количество шар журнала, пропущенных при вычитке как уже сохраненные, с момента запуска
+func (b *BufferedShareStorage) SkippedCount() uint64 {
+	return b.skipped.Load()
+}
+
+// QuarantinedCount количество строк журнала в карантине: найденных в каталоге при запуске и отложенных после
+func (b *BufferedShareStorage) QuarantinedCount() uint64 {
+	return b.quarantined.Load()
+}
+
+// BufferedBytes размер невычитанного журнала в байтах
+func (b *BufferedShareStorage) BufferedBytes() int64 {
+	return b.log.Size()
+}
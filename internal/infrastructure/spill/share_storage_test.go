@@ -0,0 +1,216 @@
+package spill
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+	"github.com/dnsoftware/mpm-shares-processor/pkg/logger"
+)
+
+type testStorage struct {
+	mu      sync.Mutex
+	down    bool   // хранилище недоступно
+	badData bool   // ошибка вставки при доступном хранилище
+	badUUID string // ошибка вставки пакета с этой шарой при доступном хранилище
+	saved   []string
+	offsets map[int32]int64 // последние сохраненные смещения по партициям
+}
+
+func (s *testStorage) AddSharesBatch(ctx context.Context, shares []entity.Share) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.down {
+		return fmt.Errorf("connection refused")
+	}
+	if s.badData {
+		return fmt.Errorf("bad data")
+	}
+	for _, sh := range shares {
+		if sh.UUID == s.badUUID {
+			return fmt.Errorf("bad data")
+		}
+	}
+	for _, sh := range shares {
+		s.saved = append(s.saved, sh.UUID)
+		if sh.KafkaTopic != "" {
+			if s.offsets == nil {
+				s.offsets = make(map[int32]int64)
+			}
+			s.offsets[sh.KafkaPartition] = max(s.offsets[sh.KafkaPartition], sh.KafkaOffset)
+		}
+	}
+	return nil
+}
+
+func (s *testStorage) LastOffset(ctx context.Context, topic string, partition int32) (int64, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	offset, ok := s.offsets[partition]
+	return offset, ok, nil
+}
+
+func (s *testStorage) Ping(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.down {
+		return fmt.Errorf("connection refused")
+	}
+	return nil
+}
+
+func (s *testStorage) setDown(down bool) {
+	s.mu.Lock()
+	s.down = down
+	s.mu.Unlock()
+}
+
+func (s *testStorage) savedUUIDs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.saved...)
+}
+
+func batch(uuids ...string) []entity.Share {
+	shares := make([]entity.Share, 0, len(uuids))
+	for _, uuid := range uuids {
+		shares = append(shares, entity.Share{UUID: uuid, Difficulty: "1", Sharedif: "1", Cost: "0"})
+	}
+	return shares
+}
+
+func TestBufferedShareStorage(t *testing.T) {
+	logger.InitLogger(logger.LogLevelProduction, t.TempDir()+"/test.log")
+
+	dir := t.TempDir()
+	storage := &testStorage{down: true}
+	b, err := NewBufferedShareStorage(Config{Dir: dir, SegmentBytes: 100, DrainInterval: 10 * time.Millisecond}, storage)
+	require.NoError(t, err)
+
+	// хранилище недоступно - пакеты уходят в журнал (несколько сегментов)
+	ctx := context.Background()
+	require.NoError(t, b.AddSharesBatch(ctx, batch("1", "2")))
+	require.NoError(t, b.AddSharesBatch(ctx, batch("3")))
+	require.NoError(t, b.AddSharesBatch(ctx, batch("4")))
+	require.Equal(t, uint64(3), b.SpilledCount())
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Greater(t, len(entries), 1)
+
+	// после перезапуска журнал сохраняется
+	require.NoError(t, b.Close())
+	b, err = NewBufferedShareStorage(Config{Dir: dir, SegmentBytes: 100, DrainInterval: 10 * time.Millisecond}, storage)
+	require.NoError(t, err)
+	b.Start()
+	defer b.Close()
+
+	// хранилище снова доступно - журнал вычитывается по порядку
+	storage.setDown(false)
+	require.Eventually(t, func() bool { return len(storage.savedUUIDs()) == 4 }, time.Second, 10*time.Millisecond)
+	require.Equal(t, []string{"1", "2", "3", "4"}, storage.savedUUIDs())
+	require.Eventually(t, func() bool { return b.BufferedBytes() == 0 }, time.Second, 10*time.Millisecond)
+
+	// ошибка данных при доступном хранилище возвращается, в журнал не пишется
+	storage.mu.Lock()
+	storage.badData = true
+	storage.mu.Unlock()
+	require.Error(t, b.AddSharesBatch(ctx, batch("5")))
+	require.Equal(t, int64(0), b.BufferedBytes())
+}
+
+func TestBufferedShareStorageQuarantine(t *testing.T) {
+	logger.InitLogger(logger.LogLevelProduction, t.TempDir()+"/test.log")
+
+	dir := t.TempDir()
+	storage := &testStorage{down: true, badUUID: "bad"}
+	b, err := NewBufferedShareStorage(Config{Dir: dir, SegmentBytes: 1 << 20, DrainInterval: 10 * time.Millisecond, DrainAttempts: 2}, storage)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, b.AddSharesBatch(ctx, batch("1")))
+	require.NoError(t, b.log.Append([]entity.Share{{UUID: "bad"}}))
+	require.NoError(t, b.AddSharesBatch(ctx, batch("2")))
+
+	// нераспознанная строка в середине сегмента
+	seq, _, err := b.log.Oldest()
+	require.NoError(t, err)
+	f, err := os.OpenFile(b.log.path(seq), os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString("garbage\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	// пока журнал не вычитан, новые пакеты идут в журнал, даже если хранилище доступно
+	storage.setDown(false)
+	require.NoError(t, b.AddSharesBatch(ctx, batch("3")))
+	require.Empty(t, storage.savedUUIDs())
+
+	// первая строка перенесена, вторая пока повторяется - после перезапуска вычитка продолжается с нее
+	b.drain(ctx)
+	require.Equal(t, []string{"1"}, storage.savedUUIDs())
+	require.NoError(t, b.Close())
+	b, err = NewBufferedShareStorage(Config{Dir: dir, SegmentBytes: 1 << 20, DrainInterval: 10 * time.Millisecond, DrainAttempts: 2}, storage)
+	require.NoError(t, err)
+	b.Start()
+	defer b.Close()
+
+	// неисправимые строки уходят в карантин и не блокируют остальные
+	require.Eventually(t, func() bool { return b.BufferedBytes() == 0 }, time.Second, 10*time.Millisecond)
+	require.Equal(t, []string{"1", "2", "3"}, storage.savedUUIDs())
+	require.Equal(t, uint64(2), b.QuarantinedCount())
+	quarantined, err := os.ReadDir(filepath.Join(dir, quarantineDir))
+	require.NoError(t, err)
+	require.Len(t, quarantined, 2)
+
+	// строки в карантине учитываются и после перезапуска
+	restarted, err := NewBufferedShareStorage(Config{Dir: dir}, storage)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), restarted.QuarantinedCount())
+	require.NoError(t, restarted.Close())
+
+	// журнал вычитан - пакеты снова идут напрямую в хранилище
+	require.NoError(t, b.AddSharesBatch(ctx, batch("4")))
+	require.Equal(t, []string{"1", "2", "3", "4"}, storage.savedUUIDs())
+}
+
+func TestBufferedShareStorageSkipsStoredOffsets(t *testing.T) {
+	logger.InitLogger(logger.LogLevelProduction, t.TempDir()+"/test.log")
+
+	dir := t.TempDir()
+	storage := &testStorage{down: true}
+	b, err := NewBufferedShareStorage(Config{Dir: dir, DrainInterval: 10 * time.Millisecond}, storage)
+	require.NoError(t, err)
+	defer b.Close()
+
+	kafkaBatch := func(offsets ...int64) []entity.Share {
+		shares := batch()
+		for _, offset := range offsets {
+			shares = append(shares, entity.Share{UUID: fmt.Sprintf("k%d", offset), KafkaTopic: "shares", KafkaPartition: 1, KafkaOffset: offset})
+		}
+		return shares
+	}
+
+	// пакет записан в журнал, смещение в Кафке не подтверждено (сбой) - после перезапуска пакет читается и пишется в журнал снова
+	ctx := context.Background()
+	require.NoError(t, b.AddSharesBatch(ctx, kafkaBatch(1, 2)))
+	require.NoError(t, b.AddSharesBatch(ctx, append(kafkaBatch(1, 2, 3), batch("grpc")...)))
+
+	// при вычитке уже сохраненные смещения пропускаются
+	storage.setDown(false)
+	b.drain(ctx)
+	require.Equal(t, []string{"k1", "k2", "k3", "grpc"}, storage.savedUUIDs())
+	require.Equal(t, uint64(2), b.SkippedCount())
+	require.Equal(t, uint64(2), b.DrainedCount())
+	require.Equal(t, int64(0), b.BufferedBytes())
+}
@@ -0,0 +1,158 @@
+package analitics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Алгоритмы майнинга (нормализованные названия из поля coins.algo)
+const (
+	AlgoBlake3    = "blake3"
+	AlgoHeavyHash = "heavyhash"
+	AlgoNexaPow   = "nexapow"
+	AlgoEthashB3  = "ethashb3"
+)
+
+var (
+	ErrUnknownAlgorithm = errors.New("unknown mining algorithm") // у монеты не задан алгоритм или для алгоритма нет множителя
+	ErrInvalidQuery     = errors.New("invalid query")            // недопустимые параметры запроса (период, интервал)
+)
+
+// DefaultAlgorithmMultipliers кол-во хешей на шару единичной сложности по алгоритмам (соглашения stratum пула)
+var DefaultAlgorithmMultipliers = map[string]float64{
+	// Alephium, Kaspa, Nexa: цель шары сложности 1 - 2^224 (diff1 Bitcoin), в среднем 2^32 хешей на шару
+	AlgoBlake3:    1 << 32,
+	AlgoHeavyHash: 1 << 32,
+	AlgoNexaPow:   1 << 32,
+	// ethash: цель шары 2^256/сложность, сложность задается непосредственно в хешах
+	AlgoEthashB3: 1,
+}
+
+// NormalizeAlgorithm приведение названия алгоритма к виду ключа реестра ("Blake 3" -> "blake3")
+func NormalizeAlgorithm(algo string) string {
+	algo = strings.ToLower(algo)
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(algo)
+}
+
+// AlgorithmRegistry реестр множителей перевода сложности в хеши по алгоритму монеты
+// алгоритм монеты берется из справочника монет (coins.algo), алгоритмы из конфига его переопределяют
+type AlgorithmRegistry struct {
+	coinAlgorithms map[string]string  // символ монеты -> нормализованный алгоритм (из конфига)
+	multipliers    map[string]float64 // нормализованный алгоритм -> множитель
+	coinStorage    CoinStorage
+
+	mu       sync.Mutex
+	byCoinID map[int64]string    // нормализованные алгоритмы по коду монеты
+	resolved map[string]struct{} // монеты из конфига, коды которых уже получены из CoinStorage
+}
+
+// NewAlgorithmRegistry создание реестра
+// coinAlgorithms переопределяют алгоритмы справочника монет, multipliers дополняют (переопределяют) значения по умолчанию
+func NewAlgorithmRegistry(coinAlgorithms map[string]string, multipliers map[string]float64, coinStorage CoinStorage) *AlgorithmRegistry {
+	r := &AlgorithmRegistry{
+		coinAlgorithms: make(map[string]string),
+		multipliers:    make(map[string]float64),
+		coinStorage:    coinStorage,
+		byCoinID:       make(map[int64]string),
+		resolved:       make(map[string]struct{}),
+	}
+
+	for coin, algo := range coinAlgorithms {
+		r.coinAlgorithms[strings.ToUpper(coin)] = NormalizeAlgorithm(algo)
+	}
+	for algo, m := range DefaultAlgorithmMultipliers {
+		r.multipliers[algo] = m
+	}
+	for algo, m := range multipliers {
+		r.multipliers[NormalizeAlgorithm(algo)] = m
+	}
+
+	return r
+}
+
+// MultiplierByCoinID множитель по коду монеты (coinID = 0 - шар нет, множитель не нужен)
+// ErrUnknownAlgorithm, если алгоритм монеты не задан ни в справочнике, ни в конфиге или для него нет множителя
+func (r *AlgorithmRegistry) MultiplierByCoinID(ctx context.Context, coinID int64) (float64, error) {
+	if coinID <= 0 {
+		return 0, nil
+	}
+
+	algo, err := r.algorithm(ctx, coinID)
+	if err != nil {
+		return 0, err
+	}
+	m, ok := r.multipliers[algo]
+	if !ok {
+		return 0, fmt.Errorf("%w %q (coin %d): no multiplier in analitics.algorithm_multipliers", ErrUnknownAlgorithm, algo, coinID)
+	}
+
+	return m, nil
+}
+
+// algorithm нормализованный алгоритм монеты: из конфига, иначе из справочника монет
+// коды монет из конфига получаем из CoinStorage при обращении, пока не будут получены все
+// запросы к CoinStorage идут без блокировки реестра (параллельные вызовы могут запросить одну монету повторно)
+func (r *AlgorithmRegistry) algorithm(ctx context.Context, coinID int64) (string, error) {
+	r.mu.Lock()
+	algo, ok := r.byCoinID[coinID]
+	var unresolved []string
+	if !ok {
+		for coin := range r.coinAlgorithms {
+			if _, ok := r.resolved[coin]; !ok {
+				unresolved = append(unresolved, coin)
+			}
+		}
+	}
+	r.mu.Unlock()
+	if ok {
+		return algo, nil
+	}
+
+	var resolveErr error
+	resolved := make(map[string]int64, len(unresolved))
+	for _, coin := range unresolved {
+		id, err := r.coinStorage.GetCoinIDByName(ctx, coin)
+		if err != nil {
+			resolveErr = err
+			continue
+		}
+		resolved[coin] = id
+	}
+
+	r.mu.Lock()
+	for coin, id := range resolved {
+		r.resolved[coin] = struct{}{}
+		if id > 0 {
+			r.byCoinID[id] = r.coinAlgorithms[coin]
+		}
+	}
+	algo, ok = r.byCoinID[coinID]
+	r.mu.Unlock()
+	if ok {
+		return algo, nil
+	}
+	if resolveErr != nil {
+		return "", resolveErr // монета может быть среди еще не полученных из конфига
+	}
+
+	name, err := r.coinStorage.GetCoinAlgorithm(ctx, coinID)
+	if err != nil {
+		return "", fmt.Errorf("coin %d algorithm: %w", coinID, err)
+	}
+	algo = NormalizeAlgorithm(name)
+	if algo == "" {
+		return "", fmt.Errorf("%w: coin %d has no algorithm in the coin directory, set analitics.coin_algorithms", ErrUnknownAlgorithm, coinID)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cached, ok := r.byCoinID[coinID]; ok {
+		return cached, nil
+	}
+	r.byCoinID[coinID] = algo
+
+	return algo, nil
+}
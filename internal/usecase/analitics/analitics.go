@@ -2,59 +2,220 @@ package analitics
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/dnsoftware/mpm-shares-processor/internal/constants"
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
 )
 
+// Окна расчета хешрейта по умолчанию
+const (
+	DefaultCurrentWindow = 10 * time.Minute
+)
+
+var DefaultAverageWindows = []time.Duration{time.Hour, 24 * time.Hour}
+
+type Config struct {
+	CurrentWindow        time.Duration      // окно расчета текущего хешрейта
+	AverageWindows       []time.Duration    // окна расчета среднего хешрейта
+	CoinAlgorithms       map[string]string  // алгоритмы монет (переопределяют справочник монет), ключ - символ монеты
+	AlgorithmMultipliers map[string]float64 // кол-во хешей на шару единичной сложности (дополняют DefaultAlgorithmMultipliers), ключ - алгоритм
+}
+
 type ShareStorage interface {
-	CoinHashrate(ctx context.Context, coinSymbol string) (uint64, error)
-	MinerHashrate(ctx context.Context, walletID int64) (uint64, error)
-	WorkerHashrate(ctx context.Context, workerID int64) (uint64, error)
+	// суммы сложностей шар за скользящие окна, заканчивающиеся в periodEnd (в порядке windows)
+	// для кошелька и воркера дополнительно возвращается код монеты (0 - если шар за период нет)
+	CoinDifficultyWindowSums(ctx context.Context, coinID int64, periodEnd time.Time, windows []time.Duration) ([]float64, error)
+	WalletDifficultyWindowSums(ctx context.Context, walletID int64, periodEnd time.Time, windows []time.Duration) (int64, []float64, error)
+	WorkerDifficultyWindowSums(ctx context.Context, workerID int64, periodEnd time.Time, windows []time.Duration) (int64, []float64, error)
+
+	// суммы сложностей по интервалам (interval - в секундах) в рамках периода [periodStart, periodEnd)
+	// возвращаются только непустые интервалы: метки начала интервалов и суммы
+	DifficultyIntervalGroupGlobal(ctx context.Context, periodStart time.Time, periodEnd time.Time, interval int, coinID int64, rewardMethod string) ([]time.Time, []float64, error)
+	DifficultyIntervalGroupWallet(ctx context.Context, periodStart time.Time, periodEnd time.Time, interval int, coinID int64, walletID int64, rewardMethod string) ([]time.Time, []float64, error)
+	DifficultyIntervalGroupWorker(ctx context.Context, periodStart time.Time, periodEnd time.Time, interval int, coinID int64, workerID int64, rewardMethod string) ([]time.Time, []float64, error)
+
+	// сумма сложностей и кол-во шар в диапазоне dateStart < share_date <= dateEnd
+	RangeDifficultySum(ctx context.Context, dateStart time.Time, dateEnd time.Time, coinID int64, rewardMethod string) (float64, uint64, error)
+
+	// количество повторов nonce по воркерам кошелька в диапазоне periodStart <= share_date < periodEnd
+	WalletNonceReplayCounts(ctx context.Context, walletID int64, periodStart time.Time, periodEnd time.Time) ([]entity.NonceReplayCount, error)
+}
+
+// CoinStorage справочник монет
+type CoinStorage interface {
+	GetCoinIDByName(ctx context.Context, coin string) (int64, error)    // код монеты по буквенному коду (ALPH, KAS и т.д.)
+	GetCoinAlgorithm(ctx context.Context, coinID int64) (string, error) // алгоритм майнинга монеты (coins.algo, пусто - не задан)
+}
+
+// HashrateWindow хешрейт за скользящее окно
+type HashrateWindow struct {
+	Window   time.Duration
+	Hashrate float64 // хешей в секунду
+}
+
+// Hashrate текущий и средние хешрейты
+type Hashrate struct {
+	Current  HashrateWindow
+	Averages []HashrateWindow
 }
 
 type AnaliticsUsecase struct {
+	mu           sync.RWMutex // окна расчета изменяются на ходу (SetWindows)
+	cfg          Config
 	shareStorage ShareStorage
+	coinStorage  CoinStorage
+	algorithms   *AlgorithmRegistry // множители перевода сложности в хеши по алгоритмам монет
 }
 
-func NewAnaliticsUsecase(s ShareStorage) *AnaliticsUsecase {
-	return &AnaliticsUsecase{
+func NewAnaliticsUsecase(cfg Config, s ShareStorage, c CoinStorage) *AnaliticsUsecase {
+	a := &AnaliticsUsecase{
+		cfg:          cfg,
 		shareStorage: s,
+		coinStorage:  c,
+		algorithms:   NewAlgorithmRegistry(cfg.CoinAlgorithms, cfg.AlgorithmMultipliers, c),
 	}
+	a.SetWindows(cfg.CurrentWindow, cfg.AverageWindows)
+
+	return a
+}
+
+// SetWindows изменение окон расчета хешрейта на ходу (пустые значения - окна по умолчанию)
+func (a *AnaliticsUsecase) SetWindows(current time.Duration, averages []time.Duration) {
+	if current <= 0 {
+		current = DefaultCurrentWindow
+	}
+	if len(averages) == 0 {
+		averages = DefaultAverageWindows
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.cfg.CurrentWindow = current
+	a.cfg.AverageWindows = append([]time.Duration(nil), averages...)
 }
 
-func (a *AnaliticsUsecase) CoinHashrate(coinSymbol string) (uint64, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), constants.ContextTimeout*time.Second)
+func (a *AnaliticsUsecase) CoinHashrate(ctx context.Context, coinSymbol string) (Hashrate, error) {
+	ctx, cancel := context.WithTimeout(ctx, constants.ContextTimeout*time.Second)
 	defer cancel()
 
-	coinHashrate, err := a.shareStorage.CoinHashrate(ctx, coinSymbol)
+	coinID, err := a.coinStorage.GetCoinIDByName(ctx, coinSymbol)
+	if err != nil {
+		return Hashrate{}, err
+	}
+	if coinID == 0 {
+		return Hashrate{}, fmt.Errorf("%w %s", entity.ErrUnknownCoin, coinSymbol)
+	}
+
+	windows := a.windows()
+	sums, err := a.shareStorage.CoinDifficultyWindowSums(ctx, coinID, time.Now(), windows)
+	if err != nil {
+		return Hashrate{}, err
+	}
+
+	multiplier, err := a.algorithms.MultiplierByCoinID(ctx, coinID)
 	if err != nil {
-		return 0, err
+		return Hashrate{}, err
 	}
 
-	return coinHashrate, nil
+	return hashrate(windows, sums, multiplier), nil
 }
 
-func (a *AnaliticsUsecase) MinerHashrate(walletID int64) (uint64, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), constants.ContextTimeout*time.Second)
+func (a *AnaliticsUsecase) MinerHashrate(ctx context.Context, walletID int64) (Hashrate, error) {
+	ctx, cancel := context.WithTimeout(ctx, constants.ContextTimeout*time.Second)
 	defer cancel()
 
-	walletHashrate, err := a.shareStorage.MinerHashrate(ctx, walletID)
+	windows := a.windows()
+	coinID, sums, err := a.shareStorage.WalletDifficultyWindowSums(ctx, walletID, time.Now(), windows)
 	if err != nil {
-		return 0, err
+		return Hashrate{}, err
 	}
 
-	return walletHashrate, nil
+	multiplier, err := a.algorithms.MultiplierByCoinID(ctx, coinID)
+	if err != nil {
+		return Hashrate{}, err
+	}
+
+	return hashrate(windows, sums, multiplier), nil
+}
+
+func (a *AnaliticsUsecase) WorkerHashrate(ctx context.Context, workerID int64) (Hashrate, error) {
+	ctx, cancel := context.WithTimeout(ctx, constants.ContextTimeout*time.Second)
+	defer cancel()
+
+	windows := a.windows()
+	coinID, sums, err := a.shareStorage.WorkerDifficultyWindowSums(ctx, workerID, time.Now(), windows)
+	if err != nil {
+		return Hashrate{}, err
+	}
+
+	multiplier, err := a.algorithms.MultiplierByCoinID(ctx, coinID)
+	if err != nil {
+		return Hashrate{}, err
+	}
+
+	return hashrate(windows, sums, multiplier), nil
 }
 
-func (a *AnaliticsUsecase) WorkerHashrate(workerID int64) (uint64, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), constants.ContextTimeout*time.Second)
+// RoundDifficultySum сумма сложностей и кол-во шар раунда (dateStart < share_date <= dateEnd)
+// dateStart - дата нахождения предыдущего блока, dateEnd - дата нахождения текущего блока
+func (a *AnaliticsUsecase) RoundDifficultySum(ctx context.Context, coinSymbol string, dateStart time.Time, dateEnd time.Time, rewardMethod string) (float64, uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, constants.ContextTimeout*time.Second)
 	defer cancel()
 
-	workerHashrate, err := a.shareStorage.WorkerHashrate(ctx, workerID)
+	coinID, err := a.coinStorage.GetCoinIDByName(ctx, coinSymbol)
 	if err != nil {
-		return 0, err
+		return 0, 0, err
+	}
+	if coinID == 0 {
+		return 0, 0, fmt.Errorf("%w %s", entity.ErrUnknownCoin, coinSymbol)
+	}
+	if !dateStart.Before(dateEnd) {
+		return 0, 0, fmt.Errorf("%w: round start %s must be before end %s", ErrInvalidQuery, dateStart.Format(time.RFC3339), dateEnd.Format(time.RFC3339))
+	}
+
+	return a.shareStorage.RangeDifficultySum(ctx, dateStart, dateEnd, coinID, strings.ToUpper(rewardMethod))
+}
+
+// windows все окна расчета: первым текущее, затем средние
+func (a *AnaliticsUsecase) windows() []time.Duration {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return append([]time.Duration{a.cfg.CurrentWindow}, a.cfg.AverageWindows...)
+}
+
+// hashrate перевод сумм сложностей (в порядке windows) в хешрейт
+// multiplier - кол-во хешей на шару единичной сложности для алгоритма монеты
+func hashrate(windows []time.Duration, sums []float64, multiplier float64) Hashrate {
+	hr := Hashrate{
+		Averages: make([]HashrateWindow, 0, len(windows)-1),
+	}
+	for i, w := range windows {
+		item := HashrateWindow{
+			Window:   w,
+			Hashrate: DifficultyToHashrate(sums[i], multiplier, w),
+		}
+		if i == 0 {
+			hr.Current = item
+		} else {
+			hr.Averages = append(hr.Averages, item)
+		}
+	}
+
+	return hr
+}
+
+// DifficultyToHashrate перевод суммы сложностей шар за период в хеши в секунду
+// multiplier - кол-во хешей на шару единичной сложности (см. AlgorithmRegistry)
+func DifficultyToHashrate(difficultySum float64, multiplier float64, period time.Duration) float64 {
+	if period <= 0 {
+		return 0
 	}
 
-	return workerHashrate, nil
+	return difficultySum * multiplier / period.Seconds()
 }
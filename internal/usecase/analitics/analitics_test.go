@@ -0,0 +1,241 @@
+package analitics
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+)
+
+type testShareStorage struct {
+	coinID int64
+	sums   []float64
+
+	labels       []time.Time // интервалы истории
+	intervalSums []float64
+
+	replays []entity.NonceReplayCount
+}
+
+func (s *testShareStorage) CoinDifficultyWindowSums(ctx context.Context, coinID int64, periodEnd time.Time, windows []time.Duration) ([]float64, error) {
+	return s.sums, nil
+}
+
+func (s *testShareStorage) WalletDifficultyWindowSums(ctx context.Context, walletID int64, periodEnd time.Time, windows []time.Duration) (int64, []float64, error) {
+	return s.coinID, s.sums, nil
+}
+
+func (s *testShareStorage) WorkerDifficultyWindowSums(ctx context.Context, workerID int64, periodEnd time.Time, windows []time.Duration) (int64, []float64, error) {
+	return s.coinID, s.sums, nil
+}
+
+func (s *testShareStorage) DifficultyIntervalGroupGlobal(ctx context.Context, periodStart time.Time, periodEnd time.Time, interval int, coinID int64, rewardMethod string) ([]time.Time, []float64, error) {
+	return s.labels, s.intervalSums, nil
+}
+
+func (s *testShareStorage) DifficultyIntervalGroupWallet(ctx context.Context, periodStart time.Time, periodEnd time.Time, interval int, coinID int64, walletID int64, rewardMethod string) ([]time.Time, []float64, error) {
+	return s.labels, s.intervalSums, nil
+}
+
+func (s *testShareStorage) DifficultyIntervalGroupWorker(ctx context.Context, periodStart time.Time, periodEnd time.Time, interval int, coinID int64, workerID int64, rewardMethod string) ([]time.Time, []float64, error) {
+	return s.labels, s.intervalSums, nil
+}
+
+func (s *testShareStorage) RangeDifficultySum(ctx context.Context, dateStart time.Time, dateEnd time.Time, coinID int64, rewardMethod string) (float64, uint64, error) {
+	var sum float64
+	for _, v := range s.intervalSums {
+		sum += v
+	}
+	return sum, uint64(len(s.intervalSums)), nil
+}
+
+func (s *testShareStorage) WalletNonceReplayCounts(ctx context.Context, walletID int64, periodStart time.Time, periodEnd time.Time) ([]entity.NonceReplayCount, error) {
+	return s.replays, nil
+}
+
+type testCoinStorage map[string]int64
+
+func (c testCoinStorage) GetCoinIDByName(ctx context.Context, coin string) (int64, error) {
+	return c[coin], nil
+}
+
+// testCoinAlgorithms алгоритмы справочника монет по кодам
+var testCoinAlgorithms = map[int64]string{2: "HeavyHash", 4: "Blake 3", 11: "EthashB3"}
+
+func (c testCoinStorage) GetCoinAlgorithm(ctx context.Context, coinID int64) (string, error) {
+	return testCoinAlgorithms[coinID], nil
+}
+
+func TestCoinHashrate(t *testing.T) {
+	cfg := Config{
+		CurrentWindow:  10 * time.Minute,
+		AverageWindows: []time.Duration{time.Hour, 24 * time.Hour},
+	}
+	storage := &testShareStorage{sums: []float64{600, 3600, 86400 * 2}}
+	a := NewAnaliticsUsecase(cfg, storage, testCoinStorage{"ALPH": 4})
+
+	hr, err := a.CoinHashrate(context.Background(), "ALPH")
+	require.NoError(t, err)
+
+	require.Equal(t, 10*time.Minute, hr.Current.Window)
+	require.Equal(t, float64(1<<32), hr.Current.Hashrate)
+	require.Len(t, hr.Averages, 2)
+	require.Equal(t, time.Hour, hr.Averages[0].Window)
+	require.Equal(t, float64(1<<32), hr.Averages[0].Hashrate)
+	require.Equal(t, 24*time.Hour, hr.Averages[1].Window)
+	require.Equal(t, float64(2<<32), hr.Averages[1].Hashrate)
+
+	_, err = a.CoinHashrate(context.Background(), "UNKNOWN")
+	require.Error(t, err)
+}
+
+func TestDefaultWindows(t *testing.T) {
+	a := NewAnaliticsUsecase(Config{}, &testShareStorage{}, testCoinStorage{})
+
+	require.Equal(t, []time.Duration{DefaultCurrentWindow, time.Hour, 24 * time.Hour}, a.windows())
+	require.Equal(t, float64(0), DifficultyToHashrate(100, 1<<32, 0))
+}
+
+func TestWalletHashrateAlgorithm(t *testing.T) {
+	cfg := Config{
+		CurrentWindow:        time.Second,
+		AverageWindows:       []time.Duration{time.Second},
+		AlgorithmMultipliers: map[string]float64{"EthashB3": 10},
+	}
+	storage := &testShareStorage{coinID: 11, sums: []float64{3, 3}}
+	a := NewAnaliticsUsecase(cfg, storage, testCoinStorage{"ALPH": 4, "HYP": 11})
+
+	hr, err := a.MinerHashrate(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, float64(30), hr.Current.Hashrate)
+
+	// алгоритм из конфига переопределяет справочник монет
+	a = NewAnaliticsUsecase(Config{
+		CurrentWindow:        time.Second,
+		AverageWindows:       []time.Duration{time.Second},
+		CoinAlgorithms:       map[string]string{"hyp": "Blake 3"},
+		AlgorithmMultipliers: map[string]float64{"blake3": 2},
+	}, storage, testCoinStorage{"ALPH": 4, "HYP": 11})
+	hr, err = a.MinerHashrate(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, float64(6), hr.Current.Hashrate)
+
+	// у монеты нет алгоритма ни в справочнике, ни в конфиге - ошибка, а не множитель по умолчанию
+	storage.coinID = 10
+	_, err = a.WorkerHashrate(context.Background(), 1)
+	require.ErrorIs(t, err, ErrUnknownAlgorithm)
+
+	// алгоритм без множителя
+	testCoinAlgorithms[12] = "RandomX"
+	defer delete(testCoinAlgorithms, 12)
+	storage.coinID = 12
+	_, err = a.WorkerHashrate(context.Background(), 1)
+	require.ErrorIs(t, err, ErrUnknownAlgorithm)
+
+	// шар нет - хешрейт нулевой без обращения к справочнику
+	storage.coinID = 0
+	hr, err = a.WorkerHashrate(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, float64(0), hr.Current.Hashrate)
+}
+
+func TestHumanHashrate(t *testing.T) {
+	value, unit := HumanHashrate(999)
+	require.Equal(t, float64(999), value)
+	require.Equal(t, "H/s", unit)
+
+	value, unit = HumanHashrate(1.5e9)
+	require.Equal(t, 1.5, value)
+	require.Equal(t, "GH/s", unit)
+
+	value, unit = HumanHashrate(2e18)
+	require.Equal(t, float64(2000), value)
+	require.Equal(t, "PH/s", unit)
+}
+
+func TestNormalizeAlgorithm(t *testing.T) {
+	require.Equal(t, AlgoBlake3, NormalizeAlgorithm("Blake 3"))
+	require.Equal(t, AlgoEthashB3, NormalizeAlgorithm("EthashB3"))
+}
+
+// gatedCoinStorage запрос монеты gatedCoin: ошибка, пока fail, затем ожидание закрытия gate
+type gatedCoinStorage struct {
+	testCoinStorage
+	gatedCoin string
+	fail      atomic.Bool
+	gate      chan struct{}
+}
+
+func (c *gatedCoinStorage) GetCoinIDByName(ctx context.Context, coin string) (int64, error) {
+	if coin == c.gatedCoin {
+		if c.fail.Load() {
+			return 0, errors.New("coin service unavailable")
+		}
+		<-c.gate
+	}
+	return c.testCoinStorage.GetCoinIDByName(ctx, coin)
+}
+
+func TestMultiplierByCoinIDNoLockDuringRPC(t *testing.T) {
+	storage := &gatedCoinStorage{testCoinStorage: testCoinStorage{"ALPH": 4, "HYP": 11, "KAS": 2}, gatedCoin: "KAS", gate: make(chan struct{})}
+	r := NewAlgorithmRegistry(map[string]string{"ALPH": "Blake 3", "HYP": "EthashB3", "KAS": "HeavyHash"}, map[string]float64{"EthashB3": 10}, storage)
+
+	// коды всех монет, кроме KAS, получены
+	storage.fail.Store(true)
+	m, err := r.MultiplierByCoinID(context.Background(), 11)
+	require.NoError(t, err)
+	require.Equal(t, float64(10), m)
+	storage.fail.Store(false)
+
+	// запрос кода KAS висит
+	done := make(chan float64)
+	go func() {
+		m, _ := r.MultiplierByCoinID(context.Background(), 2)
+		done <- m
+	}()
+
+	// пока запрос висит, уже полученные коды отдаются без ожидания
+	for i := 0; i < 100; i++ {
+		m, err := r.MultiplierByCoinID(context.Background(), 4)
+		require.NoError(t, err)
+		require.Equal(t, float64(1<<32), m)
+	}
+	select {
+	case <-done:
+		t.Fatal("KAS request must wait for the gate")
+	default:
+	}
+
+	close(storage.gate)
+	require.Equal(t, float64(1<<32), <-done)
+}
+
+// ctxShareStorage хранилище, учитывающее отмену контекста запроса
+type ctxShareStorage struct {
+	testShareStorage
+}
+
+func (s *ctxShareStorage) WalletDifficultyWindowSums(ctx context.Context, walletID int64, periodEnd time.Time, windows []time.Duration) (int64, []float64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, nil, err
+	}
+	return s.testShareStorage.WalletDifficultyWindowSums(ctx, walletID, periodEnd, windows)
+}
+
+func TestHashrateRequestCancel(t *testing.T) {
+	a := NewAnaliticsUsecase(Config{}, &ctxShareStorage{testShareStorage{sums: []float64{0, 0, 0}}}, testCoinStorage{})
+
+	// отмена запроса клиентом прерывает запросы к хранилищу
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := a.MinerHashrate(ctx, 1)
+	require.ErrorIs(t, err, context.Canceled)
+
+	_, err = a.MinerHashrate(context.Background(), 1)
+	require.NoError(t, err)
+}
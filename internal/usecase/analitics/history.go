@@ -0,0 +1,202 @@
+package analitics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/constants"
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+)
+
+// Параметры истории хешрейта по умолчанию
+const (
+	DefaultHistoryPeriod   = 24 * time.Hour
+	DefaultHistoryInterval = 10 * time.Minute
+	DefaultRewardMethod    = "PPLNS"
+	MaxHistoryPoints       = 5000 // максимальное кол-во интервалов в ответе
+)
+
+// HistoryQuery параметры запроса истории хешрейта
+type HistoryQuery struct {
+	From         time.Time     // начало периода (включительно)
+	To           time.Time     // окончание периода (не включительно)
+	Interval     time.Duration // интервал группировки (кратен секунде)
+	RewardMethod string        // метод начисления вознаграждения
+}
+
+// HashratePoint хешрейт за интервал истории
+type HashratePoint struct {
+	Time     time.Time // начало интервала
+	Hashrate float64   // хешей в секунду
+}
+
+// HashrateHistory история хешрейта с пустыми интервалами, заполненными нулями
+type HashrateHistory struct {
+	HistoryQuery
+	Points []HashratePoint
+}
+
+// CoinHashrateHistory история хешрейта по монете
+func (a *AnaliticsUsecase) CoinHashrateHistory(ctx context.Context, coinSymbol string, q HistoryQuery) (HashrateHistory, error) {
+	if err := q.normalize(); err != nil {
+		return HashrateHistory{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, constants.ContextTimeout*time.Second)
+	defer cancel()
+
+	coinID, err := a.coinStorage.GetCoinIDByName(ctx, coinSymbol)
+	if err != nil {
+		return HashrateHistory{}, err
+	}
+	if coinID == 0 {
+		return HashrateHistory{}, fmt.Errorf("%w %s", entity.ErrUnknownCoin, coinSymbol)
+	}
+
+	labels, sums, err := a.shareStorage.DifficultyIntervalGroupGlobal(ctx, q.From, q.To, q.intervalSeconds(), coinID, q.RewardMethod)
+	if err != nil {
+		return HashrateHistory{}, err
+	}
+
+	multiplier, err := a.algorithms.MultiplierByCoinID(ctx, coinID)
+	if err != nil {
+		return HashrateHistory{}, err
+	}
+
+	return fillHistory(q, labels, sums, multiplier), nil
+}
+
+// MinerHashrateHistory история хешрейта майнера (кошелька)
+func (a *AnaliticsUsecase) MinerHashrateHistory(ctx context.Context, walletID int64, q HistoryQuery) (HashrateHistory, error) {
+	if err := q.normalize(); err != nil {
+		return HashrateHistory{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, constants.ContextTimeout*time.Second)
+	defer cancel()
+
+	// код монеты кошелька определяем по шарам за период
+	coinID, _, err := a.shareStorage.WalletDifficultyWindowSums(ctx, walletID, q.To, []time.Duration{q.To.Sub(q.From)})
+	if err != nil {
+		return HashrateHistory{}, err
+	}
+	if coinID == 0 {
+		return fillHistory(q, nil, nil, 0), nil
+	}
+
+	labels, sums, err := a.shareStorage.DifficultyIntervalGroupWallet(ctx, q.From, q.To, q.intervalSeconds(), coinID, walletID, q.RewardMethod)
+	if err != nil {
+		return HashrateHistory{}, err
+	}
+
+	multiplier, err := a.algorithms.MultiplierByCoinID(ctx, coinID)
+	if err != nil {
+		return HashrateHistory{}, err
+	}
+
+	return fillHistory(q, labels, sums, multiplier), nil
+}
+
+// WorkerHashrateHistory история хешрейта воркера
+func (a *AnaliticsUsecase) WorkerHashrateHistory(ctx context.Context, workerID int64, q HistoryQuery) (HashrateHistory, error) {
+	if err := q.normalize(); err != nil {
+		return HashrateHistory{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, constants.ContextTimeout*time.Second)
+	defer cancel()
+
+	// код монеты воркера определяем по шарам за период
+	coinID, _, err := a.shareStorage.WorkerDifficultyWindowSums(ctx, workerID, q.To, []time.Duration{q.To.Sub(q.From)})
+	if err != nil {
+		return HashrateHistory{}, err
+	}
+	if coinID == 0 {
+		return fillHistory(q, nil, nil, 0), nil
+	}
+
+	labels, sums, err := a.shareStorage.DifficultyIntervalGroupWorker(ctx, q.From, q.To, q.intervalSeconds(), coinID, workerID, q.RewardMethod)
+	if err != nil {
+		return HashrateHistory{}, err
+	}
+
+	multiplier, err := a.algorithms.MultiplierByCoinID(ctx, coinID)
+	if err != nil {
+		return HashrateHistory{}, err
+	}
+
+	return fillHistory(q, labels, sums, multiplier), nil
+}
+
+// normalize заполнение параметров по умолчанию и проверка
+func (q *HistoryQuery) normalize() error {
+	if q.To.IsZero() {
+		q.To = time.Now()
+	}
+	if q.From.IsZero() {
+		q.From = q.To.Add(-DefaultHistoryPeriod)
+	}
+	if q.Interval == 0 {
+		q.Interval = DefaultHistoryInterval
+	}
+	q.Interval = q.Interval.Truncate(time.Second)
+	q.RewardMethod = strings.ToUpper(q.RewardMethod)
+	if q.RewardMethod == "" {
+		q.RewardMethod = DefaultRewardMethod
+	}
+
+	if !q.From.Before(q.To) {
+		return fmt.Errorf("%w: from %s must be before to %s", ErrInvalidQuery, q.From.Format(time.RFC3339), q.To.Format(time.RFC3339))
+	}
+	if q.Interval < time.Second {
+		return fmt.Errorf("%w: interval must be at least 1 second", ErrInvalidQuery)
+	}
+	if points := q.To.Sub(q.From) / q.Interval; points > MaxHistoryPoints {
+		return fmt.Errorf("%w: too many intervals: %d (max %d)", ErrInvalidQuery, points, MaxHistoryPoints)
+	}
+
+	return nil
+}
+
+func (q *HistoryQuery) intervalSeconds() int {
+	return int(q.Interval / time.Second)
+}
+
+// fillHistory перевод сумм сложностей по интервалам в хешрейт
+// интервалы без шар заполняются нулями, метки интервалов как у toStartOfInterval (кратны интервалу от начала эпохи)
+// хешрейт крайних интервалов считается по их пересечению с периодом запроса
+func fillHistory(q HistoryQuery, labels []time.Time, sums []float64, multiplier float64) HashrateHistory {
+	step := int64(q.intervalSeconds())
+
+	bySecond := make(map[int64]float64, len(labels))
+	for i, label := range labels {
+		bySecond[label.Unix()] += sums[i]
+	}
+
+	start := q.From.Unix()
+	start -= ((start % step) + step) % step
+
+	history := HashrateHistory{HistoryQuery: q}
+	for ts := start; time.Unix(ts, 0).Before(q.To); ts += step {
+		intervalStart := time.Unix(ts, 0)
+		intervalEnd := intervalStart.Add(q.Interval)
+
+		periodStart := intervalStart
+		if periodStart.Before(q.From) {
+			periodStart = q.From
+		}
+		periodEnd := intervalEnd
+		if periodEnd.After(q.To) {
+			periodEnd = q.To
+		}
+
+		history.Points = append(history.Points, HashratePoint{
+			Time:     intervalStart,
+			Hashrate: DifficultyToHashrate(bySecond[ts], multiplier, periodEnd.Sub(periodStart)),
+		})
+	}
+
+	return history
+}
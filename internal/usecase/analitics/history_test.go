@@ -0,0 +1,63 @@
+package analitics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoinHashrateHistory(t *testing.T) {
+	from := time.Unix(960, 0)
+	storage := &testShareStorage{
+		labels:       []time.Time{time.Unix(960, 0), time.Unix(1140, 0)},
+		intervalSums: []float64{60, 120},
+	}
+	a := NewAnaliticsUsecase(Config{AlgorithmMultipliers: map[string]float64{"blake3": 1}}, storage, testCoinStorage{"ALPH": 4})
+
+	h, err := a.CoinHashrateHistory(context.Background(), "ALPH", HistoryQuery{From: from, To: from.Add(4 * time.Minute), Interval: time.Minute})
+	require.NoError(t, err)
+	require.Equal(t, DefaultRewardMethod, h.RewardMethod)
+
+	// пустые интервалы заполнены нулями
+	require.Len(t, h.Points, 4)
+	require.Equal(t, []float64{1, 0, 0, 2}, []float64{h.Points[0].Hashrate, h.Points[1].Hashrate, h.Points[2].Hashrate, h.Points[3].Hashrate})
+	require.Equal(t, time.Unix(1020, 0), h.Points[1].Time)
+}
+
+func TestMinerHashrateHistoryNoShares(t *testing.T) {
+	a := NewAnaliticsUsecase(Config{}, &testShareStorage{}, testCoinStorage{})
+
+	to := time.Unix(3600, 0)
+	h, err := a.MinerHashrateHistory(context.Background(), 1, HistoryQuery{From: to.Add(-time.Hour), To: to})
+	require.NoError(t, err)
+	require.Len(t, h.Points, 6)
+	for _, p := range h.Points {
+		require.Equal(t, float64(0), p.Hashrate)
+	}
+}
+
+func TestFillHistoryPartialIntervals(t *testing.T) {
+	// период не кратен интервалу: крайние интервалы считаются по пересечению с периодом
+	q := HistoryQuery{From: time.Unix(90, 0), To: time.Unix(150, 0), Interval: time.Minute}
+	h := fillHistory(q, []time.Time{time.Unix(60, 0), time.Unix(120, 0)}, []float64{30, 30}, 1)
+
+	require.Len(t, h.Points, 2)
+	require.Equal(t, time.Unix(60, 0), h.Points[0].Time)
+	require.Equal(t, float64(1), h.Points[0].Hashrate)
+	require.Equal(t, float64(1), h.Points[1].Hashrate)
+}
+
+func TestHistoryQueryValidation(t *testing.T) {
+	now := time.Now()
+
+	q := HistoryQuery{From: now, To: now.Add(-time.Hour)}
+	require.ErrorIs(t, q.normalize(), ErrInvalidQuery)
+
+	q = HistoryQuery{From: now.Add(-time.Hour), To: now, Interval: time.Millisecond}
+	require.ErrorIs(t, q.normalize(), ErrInvalidQuery)
+
+	q = HistoryQuery{From: now.Add(-365 * 24 * time.Hour), To: now, Interval: time.Minute}
+	require.ErrorIs(t, q.normalize(), ErrInvalidQuery)
+}
@@ -0,0 +1,55 @@
+package analitics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/constants"
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+)
+
+// WalletNonceReplays повторы nonce воркеров кошелька за период
+type WalletNonceReplays struct {
+	WalletID int64
+	From     time.Time
+	To       time.Time
+	Count    uint64                    // всего повторов
+	Rejected uint64                    // из них отклонено
+	Workers  []entity.NonceReplayCount // по воркерам
+}
+
+// MinerNonceReplays количество повторов nonce по кошельку за период [from, to)
+// пустые from/to - последние DefaultHistoryPeriod
+func (a *AnaliticsUsecase) MinerNonceReplays(ctx context.Context, walletID int64, from time.Time, to time.Time) (WalletNonceReplays, error) {
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.Add(-DefaultHistoryPeriod)
+	}
+	if !from.Before(to) {
+		return WalletNonceReplays{}, fmt.Errorf("%w: from must be before to", ErrInvalidQuery)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, constants.ContextTimeout*time.Second)
+	defer cancel()
+
+	counts, err := a.shareStorage.WalletNonceReplayCounts(ctx, walletID, from, to)
+	if err != nil {
+		return WalletNonceReplays{}, err
+	}
+
+	res := WalletNonceReplays{
+		WalletID: walletID,
+		From:     from,
+		To:       to,
+		Workers:  counts,
+	}
+	for _, c := range counts {
+		res.Count += c.Count
+		res.Rejected += c.Rejected
+	}
+
+	return res, nil
+}
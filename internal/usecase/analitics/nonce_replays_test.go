@@ -0,0 +1,30 @@
+package analitics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+)
+
+func TestMinerNonceReplays(t *testing.T) {
+	storage := &testShareStorage{replays: []entity.NonceReplayCount{
+		{WorkerID: 1, Count: 3, Rejected: 1},
+		{WorkerID: 2, Count: 2},
+	}}
+	a := NewAnaliticsUsecase(Config{}, storage, testCoinStorage{})
+
+	res, err := a.MinerNonceReplays(context.Background(), 10, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, int64(10), res.WalletID)
+	require.Equal(t, uint64(5), res.Count)
+	require.Equal(t, uint64(1), res.Rejected)
+	require.Len(t, res.Workers, 2)
+	require.Equal(t, DefaultHistoryPeriod, res.To.Sub(res.From))
+
+	_, err = a.MinerNonceReplays(context.Background(), 10, time.Unix(2000, 0), time.Unix(1000, 0))
+	require.Error(t, err)
+}
@@ -0,0 +1,16 @@
+package analitics
+
+// HashrateUnits единицы измерения хешрейта по возрастанию (шаг 1000)
+var HashrateUnits = []string{"H/s", "KH/s", "MH/s", "GH/s", "TH/s", "PH/s"}
+
+// HumanHashrate перевод хешрейта (H/s) в удобочитаемые единицы
+// Возвращает значение и единицу измерения (например 1.5, "GH/s")
+func HumanHashrate(hashrate float64) (float64, string) {
+	i := 0
+	for hashrate >= 1000 && i < len(HashrateUnits)-1 {
+		hashrate /= 1000
+		i++
+	}
+
+	return hashrate, HashrateUnits[i]
+}
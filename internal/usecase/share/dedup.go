@@ -0,0 +1,98 @@
+package share
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+)
+
+// DuplicateFilter отсев повторно присланных шар по UUID (и из Кафки, и по gRPC)
+// помнит UUID последних size сохраненных шар, при переполнении вытесняются самые старые
+// UUID шар, сохраняемых в данный момент, тоже считаются занятыми: параллельный пакет с той же шарой ее не задвоит
+type DuplicateFilter struct {
+	mu         sync.Mutex
+	size       int
+	uuids      map[string]struct{}
+	ring       []string            // UUID в порядке добавления
+	next       int                 // позиция для следующего UUID в ring
+	inFlight   map[string]struct{} // UUID шар, сохранение которых еще не завершено
+	duplicates atomic.Uint64       // счетчик отброшенных дубликатов
+}
+
+// NewDuplicateFilter фильтр на size последних шар, при size <= 0 - nil (дедупликация отключена)
+func NewDuplicateFilter(size int) *DuplicateFilter {
+	if size <= 0 {
+		return nil
+	}
+
+	return &DuplicateFilter{
+		size:     size,
+		uuids:    make(map[string]struct{}, size),
+		ring:     make([]string, 0, size),
+		inFlight: make(map[string]struct{}),
+	}
+}
+
+// reserve исключение из пакета шар, уже сохраненных, сохраняемых параллельно или повторяющихся в самом пакете
+// возвращает оставшиеся шары, их UUID, зарезервированные до вызова release, и UUID отброшенных дубликатов
+func (f *DuplicateFilter) reserve(shares []entity.Share) ([]entity.Share, []string, []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	unique := shares[:0:0]
+	reserved := make([]string, 0, len(shares))
+	var duplicates []string
+	for _, share := range shares {
+		if share.UUID != "" {
+			_, saved := f.uuids[share.UUID]
+			_, busy := f.inFlight[share.UUID]
+			if saved || busy {
+				f.duplicates.Add(1)
+				duplicates = append(duplicates, share.UUID)
+				continue
+			}
+			f.inFlight[share.UUID] = struct{}{}
+			reserved = append(reserved, share.UUID)
+		}
+		unique = append(unique, share)
+	}
+
+	return unique, reserved, duplicates
+}
+
+// release снятие резерва с UUID пакета, UUID сохраненных шар запоминаются
+func (f *DuplicateFilter) release(reserved []string, saved []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, uuid := range reserved {
+		delete(f.inFlight, uuid)
+	}
+	for _, uuid := range saved {
+		f.add(uuid)
+	}
+}
+
+func (f *DuplicateFilter) add(uuid string) {
+	if _, ok := f.uuids[uuid]; ok || uuid == "" {
+		return
+	}
+
+	if len(f.ring) < f.size {
+		f.ring = append(f.ring, uuid)
+	} else {
+		delete(f.uuids, f.ring[f.next])
+		f.ring[f.next] = uuid
+	}
+	f.next = (f.next + 1) % f.size
+	f.uuids[uuid] = struct{}{}
+}
+
+// DuplicatesCount количество отброшенных дубликатов шар с момента запуска
+func (f *DuplicateFilter) DuplicatesCount() uint64 {
+	if f == nil {
+		return 0
+	}
+	return f.duplicates.Load()
+}
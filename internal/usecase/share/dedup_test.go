@@ -0,0 +1,101 @@
+package share
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+)
+
+// testFlakyShareStorage хранилище, отклоняющее шары reject и возвращающее err
+type testFlakyShareStorage struct {
+	saved  []entity.Share
+	reject map[string]bool
+	err    error
+}
+
+func (s *testFlakyShareStorage) AddSharesBatch(ctx context.Context, shares []entity.Share) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	var partial entity.PartialSaveError
+	for _, sh := range shares {
+		if s.reject[sh.UUID] {
+			partial.Rejected = append(partial.Rejected, &entity.ShareError{UUID: sh.UUID, Err: entity.ErrInvalidShare})
+			continue
+		}
+		s.saved = append(s.saved, sh)
+	}
+	if len(partial.Rejected) > 0 {
+		return &partial
+	}
+	return nil
+}
+
+func TestDuplicateFilter(t *testing.T) {
+	require.Nil(t, NewDuplicateFilter(0))
+	require.Equal(t, uint64(0), (*DuplicateFilter)(nil).DuplicatesCount())
+
+	f := NewDuplicateFilter(3)
+	_, reserved, _ := f.reserve([]entity.Share{{UUID: "a"}, {UUID: "b"}, {UUID: "c"}})
+	f.release(reserved, reserved)
+
+	// при переполнении вытесняется самый старый UUID
+	_, reserved, _ = f.reserve([]entity.Share{{UUID: "d"}})
+	f.release(reserved, reserved)
+	unique, reserved, duplicates := f.reserve([]entity.Share{{UUID: "a"}, {UUID: "b"}})
+	require.Equal(t, []entity.Share{{UUID: "a"}}, unique)
+	require.Equal(t, []string{"b"}, duplicates)
+	require.Equal(t, uint64(1), f.DuplicatesCount())
+
+	// сохраняемая шара занята до release
+	unique, _, duplicates = f.reserve([]entity.Share{{UUID: "a"}})
+	require.Empty(t, unique)
+	require.Equal(t, []string{"a"}, duplicates)
+	f.release(reserved, nil)
+	unique, _, _ = f.reserve([]entity.Share{{UUID: "a"}})
+	require.Len(t, unique, 1)
+}
+
+func TestAddSharesBatchDuplicates(t *testing.T) {
+	storage := &testFlakyShareStorage{reject: map[string]bool{"3": true}}
+	u := NewShareUseCase(storage, nil, nil, nil, nil, nil, NewDuplicateFilter(100))
+
+	// дубликат внутри пакета - не сохраняется и возвращается как отклоненный
+	err := u.AddSharesBatch(context.Background(), []entity.Share{{UUID: "1"}, {UUID: "2"}, {UUID: "1"}})
+	var partial *entity.PartialSaveError
+	require.ErrorAs(t, err, &partial)
+	require.Len(t, partial.Rejected, 1)
+	require.ErrorIs(t, partial.Rejected[0], entity.ErrDuplicateShare)
+	require.Len(t, storage.saved, 2)
+	require.Equal(t, uint64(1), u.DuplicatesCount())
+
+	// повторная отправка уже сохраненных шар (например, по gRPC после Кафки)
+	err = u.AddSharesBatch(context.Background(), []entity.Share{{UUID: "2"}, {UUID: "3"}, {UUID: "4"}})
+	require.ErrorAs(t, err, &partial)
+	require.Equal(t, []string{"2", "3"}, partial.RejectedUUIDs())
+	require.ErrorIs(t, partial.Rejected[0], entity.ErrDuplicateShare)
+	require.ErrorIs(t, partial.Rejected[1], entity.ErrInvalidShare)
+	require.Len(t, storage.saved, 3)
+	require.Equal(t, uint64(2), u.DuplicatesCount())
+
+	// отклоненная шара дубликатом не считается
+	delete(storage.reject, "3")
+	err = u.AddSharesBatch(context.Background(), []entity.Share{{UUID: "3"}, {UUID: "4"}})
+	require.ErrorAs(t, err, &partial)
+	require.Equal(t, []string{"4"}, partial.RejectedUUIDs())
+	require.Len(t, storage.saved, 4)
+	require.Equal(t, "3", storage.saved[3].UUID)
+
+	// несохраненный пакет тоже
+	storage.err = fmt.Errorf("clickhouse unavailable")
+	require.Error(t, u.AddSharesBatch(context.Background(), []entity.Share{{UUID: "5"}}))
+	storage.err = nil
+	require.NoError(t, u.AddSharesBatch(context.Background(), []entity.Share{{UUID: "5"}}))
+	require.Equal(t, "5", storage.saved[4].UUID)
+	require.Equal(t, uint64(3), u.DuplicatesCount())
+}
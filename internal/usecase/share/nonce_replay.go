@@ -0,0 +1,136 @@
+package share
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+)
+
+// DefaultNonceReplayWindow окно отслеживания nonce по умолчанию
+const DefaultNonceReplayWindow = 10 * time.Minute
+
+// NonceReplayStorage сохранение обнаруженных повторов nonce для разбора (ClickHouse)
+type NonceReplayStorage interface {
+	AddNonceReplays(ctx context.Context, replays []entity.NonceReplay) error
+}
+
+type NonceReplayConfig struct {
+	Window time.Duration // сколько помнить nonce воркера (<= 0 - DefaultNonceReplayWindow)
+	Reject bool          // отклонять повторные шары (false - сохранять, только помечая как повтор)
+}
+
+// nonceKey ключ отслеживания nonce
+type nonceKey struct {
+	coinID   int64
+	workerID int64
+	nonce    string
+}
+
+// nonceSeen первая шара с nonce
+type nonceSeen struct {
+	key    nonceKey
+	uuid   string
+	seenAt time.Time
+}
+
+// NonceReplayDetector обнаружение повторной отправки воркером шары с тем же nonce
+// nonce запоминаются на время Window с момента первой шары
+type NonceReplayDetector struct {
+	cfg     NonceReplayConfig
+	storage NonceReplayStorage
+	now     func() time.Time
+
+	mu      sync.Mutex
+	nonces  map[nonceKey]string // ключ -> UUID первой шары
+	queue   []nonceSeen         // в порядке добавления, для вытеснения по времени
+	replays atomic.Uint64       // счетчик обнаруженных повторов
+}
+
+func NewNonceReplayDetector(cfg NonceReplayConfig, storage NonceReplayStorage) *NonceReplayDetector {
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultNonceReplayWindow
+	}
+
+	return &NonceReplayDetector{
+		cfg:     cfg,
+		storage: storage,
+		now:     time.Now,
+		nonces:  make(map[nonceKey]string),
+	}
+}
+
+// Check отбор повторов nonce в пакете шар
+// возвращает шары для сохранения (без повторов, если Reject) и обнаруженные повторы
+// шара с тем же UUID, что и первая (повторная вставка пакета), повтором не считается
+func (d *NonceReplayDetector) Check(shares []entity.Share) ([]entity.Share, []entity.NonceReplay) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	d.expire(now)
+
+	var replays []entity.NonceReplay
+	accepted := shares[:0:0]
+	for _, share := range shares {
+		if share.Nonce == "" {
+			accepted = append(accepted, share)
+			continue
+		}
+
+		key := nonceKey{coinID: share.CoinID, workerID: share.WorkerID, nonce: share.Nonce}
+		original, ok := d.nonces[key]
+		if !ok {
+			d.nonces[key] = share.UUID
+			d.queue = append(d.queue, nonceSeen{key: key, uuid: share.UUID, seenAt: now})
+			accepted = append(accepted, share)
+			continue
+		}
+
+		if original == share.UUID {
+			accepted = append(accepted, share)
+			continue
+		}
+
+		replays = append(replays, entity.NonceReplay{
+			ShareUUID:    share.UUID,
+			OriginalUUID: original,
+			ServerID:     share.ServerID,
+			CoinID:       share.CoinID,
+			WorkerID:     share.WorkerID,
+			WalletID:     share.WalletID,
+			ShareDate:    share.ShareDate,
+			Nonce:        share.Nonce,
+			Rejected:     d.cfg.Reject,
+		})
+		if !d.cfg.Reject {
+			accepted = append(accepted, share)
+		}
+	}
+	d.replays.Add(uint64(len(replays)))
+
+	return accepted, replays
+}
+
+// expire удаление nonce старше окна
+func (d *NonceReplayDetector) expire(now time.Time) {
+	border := now.Add(-d.cfg.Window)
+
+	i := 0
+	for ; i < len(d.queue) && !d.queue[i].seenAt.After(border); i++ {
+		seen := d.queue[i]
+		if d.nonces[seen.key] == seen.uuid {
+			delete(d.nonces, seen.key)
+		}
+	}
+	if i > 0 {
+		d.queue = append(d.queue[:0:0], d.queue[i:]...)
+	}
+}
+
+// ReplaysCount количество обнаруженных повторов nonce с момента запуска
+func (d *NonceReplayDetector) ReplaysCount() uint64 {
+	return d.replays.Load()
+}
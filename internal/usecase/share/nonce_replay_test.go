@@ -0,0 +1,105 @@
+package share
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+)
+
+type testNonceReplayStorage struct {
+	replays []entity.NonceReplay
+	err     error
+}
+
+func (s *testNonceReplayStorage) AddNonceReplays(ctx context.Context, replays []entity.NonceReplay) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.replays = append(s.replays, replays...)
+	return nil
+}
+
+type testShareStorage struct {
+	saved []entity.Share
+}
+
+func (s *testShareStorage) AddSharesBatch(ctx context.Context, shares []entity.Share) error {
+	s.saved = append(s.saved, shares...)
+	return nil
+}
+
+func TestNonceReplayDetector(t *testing.T) {
+	now := time.Unix(1000, 0)
+	d := NewNonceReplayDetector(NonceReplayConfig{Window: time.Minute}, nil)
+	d.now = func() time.Time { return now }
+
+	accepted, replays := d.Check([]entity.Share{
+		{UUID: "1", CoinID: 4, WorkerID: 1, Nonce: "aa"},
+		{UUID: "2", CoinID: 4, WorkerID: 2, Nonce: "aa"}, // другой воркер
+		{UUID: "3", CoinID: 4, WorkerID: 1, Nonce: "aa"}, // повтор
+		{UUID: "4", CoinID: 4, WorkerID: 1},              // без nonce
+	})
+	require.Len(t, accepted, 4) // без Reject повторы только помечаются
+	require.Len(t, replays, 1)
+	require.Equal(t, "3", replays[0].ShareUUID)
+	require.Equal(t, "1", replays[0].OriginalUUID)
+	require.False(t, replays[0].Rejected)
+
+	// повторная вставка того же пакета повтором не считается
+	_, replays = d.Check([]entity.Share{{UUID: "1", CoinID: 4, WorkerID: 1, Nonce: "aa"}})
+	require.Empty(t, replays)
+
+	// за пределами окна nonce забывается
+	now = now.Add(2 * time.Minute)
+	_, replays = d.Check([]entity.Share{{UUID: "5", CoinID: 4, WorkerID: 1, Nonce: "aa"}})
+	require.Empty(t, replays)
+	require.Equal(t, uint64(1), d.ReplaysCount())
+}
+
+func TestAddSharesBatchRejectsNonceReplays(t *testing.T) {
+	replayStorage := &testNonceReplayStorage{}
+	shareStorage := &testShareStorage{}
+	d := NewNonceReplayDetector(NonceReplayConfig{Reject: true}, replayStorage)
+	u := NewShareUseCase(shareStorage, nil, nil, nil, nil, d, nil)
+
+	err := u.AddSharesBatch(context.Background(), []entity.Share{
+		{UUID: "1", CoinID: 4, WorkerID: 1, WalletID: 10, Nonce: "aa"},
+		{UUID: "2", CoinID: 4, WorkerID: 1, WalletID: 10, Nonce: "aa"},
+	})
+	// отклоненный повтор возвращается как отклоненная шара (dead-letter, отказ по gRPC)
+	var partial *entity.PartialSaveError
+	require.ErrorAs(t, err, &partial)
+	require.Equal(t, []string{"2"}, partial.RejectedUUIDs())
+	require.ErrorIs(t, err, entity.ErrNonceReplay)
+	require.True(t, entity.IsPermanent(err))
+	require.Len(t, shareStorage.saved, 1)
+	require.Len(t, replayStorage.replays, 1)
+	require.True(t, replayStorage.replays[0].Rejected)
+	require.Equal(t, int64(10), replayStorage.replays[0].WalletID)
+
+	// пакет только из повторов - в хранилище шар ничего не отправляется
+	err = u.AddSharesBatch(context.Background(), []entity.Share{{UUID: "3", CoinID: 4, WorkerID: 1, Nonce: "aa"}})
+	require.ErrorAs(t, err, &partial)
+	require.Equal(t, []string{"3"}, partial.RejectedUUIDs())
+	require.Len(t, shareStorage.saved, 1)
+
+	// ошибка сохранения повторов - пакет не сохраняется
+	replayStorage.err = fmt.Errorf("clickhouse unavailable")
+	err = u.AddSharesBatch(context.Background(), []entity.Share{{UUID: "4", CoinID: 4, WorkerID: 1, Nonce: "aa"}})
+	require.Error(t, err)
+	require.Len(t, shareStorage.saved, 1)
+
+	// без Reject повтор сохраняется, ошибки нет
+	replayStorage.err = nil
+	u = NewShareUseCase(shareStorage, nil, nil, nil, nil, NewNonceReplayDetector(NonceReplayConfig{}, replayStorage), nil)
+	require.NoError(t, u.AddSharesBatch(context.Background(), []entity.Share{
+		{UUID: "5", CoinID: 4, WorkerID: 1, Nonce: "bb"},
+		{UUID: "6", CoinID: 4, WorkerID: 1, Nonce: "bb"},
+	}))
+	require.Len(t, shareStorage.saved, 3)
+}
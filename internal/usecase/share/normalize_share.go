@@ -37,7 +37,7 @@ func (u *ShareUseCase) NormalizeShare(ctxTask context.Context, shareFound dto.Sh
 		if coinID == 0 { // в базе нет (а должна быть, так как в миграциях заполнили все монеты в таблице)
 			spanCoinRemote.RecordError(err)
 			spanCoinRemote.SetStatus(codes.Error, "coinID must be greater then 0")
-			return entity.Share{}, fmt.Errorf("coinID must be greater then 0")
+			return entity.Share{}, fmt.Errorf("%w %s", entity.ErrUnknownCoin, shareFound.CoinSymbol)
 		}
 
 		coinID, err = u.coinCache.CreateCoin(shareFound.CoinSymbol, coinID) // кешируем
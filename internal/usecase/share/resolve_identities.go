@@ -0,0 +1,110 @@
+package share
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/dto"
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+)
+
+// IdentitiesBatchSize максимальное кол-во кошельков/воркеров в одном запросе ResolveIdentitiesBatch
+const IdentitiesBatchSize = 1000
+
+// ResolveIdentities предварительное разрешение кошельков и воркеров пакета шар
+// собирает отсутствующие в кэше уникальные (воркер, монета, метод вознаграждения), получает/создает их
+// одним запросом на IdentitiesBatchSize записей и пакетно кэширует результат, после чего NormalizeShare берет коды из кэша
+// Ошибка не критична: не разрешенные записи будут получены в NormalizeShare по одной
+func (u *ShareUseCase) ResolveIdentities(ctx context.Context, shares []dto.ShareFound) error {
+	batchStorage, ok := u.minerStorage.(MinerBatchStorage)
+	if !ok {
+		return nil
+	}
+
+	type identityKey struct {
+		workerfull   string
+		coinID       int64
+		rewardMethod string
+	}
+
+	coinIDs := make(map[string]int64)
+	seen := make(map[identityKey]struct{})
+	var identities []entity.Identity
+
+	for _, sf := range shares {
+		coinID, ok := coinIDs[sf.CoinSymbol]
+		if !ok {
+			// ошибка монеты относится только к ее шарам, они будут обработаны в NormalizeShare
+			coinID, _ = u.coinID(ctx, sf.CoinSymbol)
+			coinIDs[sf.CoinSymbol] = coinID
+		}
+		if coinID == 0 {
+			continue
+		}
+
+		key := identityKey{workerfull: sf.Workerfull, coinID: coinID, rewardMethod: sf.RewardMethod}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		walletName := WalletFromWorkerfull(sf.Workerfull)
+		walletID, err := u.minerCache.GetWalletIDByName(walletName, coinID, sf.RewardMethod)
+		if err != nil {
+			return err
+		}
+		workerID, err := u.minerCache.GetWorkerIDByName(sf.Workerfull, coinID, sf.RewardMethod)
+		if err != nil {
+			return err
+		}
+		if walletID > 0 && workerID > 0 { // уже в кэше
+			continue
+		}
+
+		identities = append(identities, entity.Identity{
+			CoinID:       coinID,
+			Workerfull:   sf.Workerfull,
+			Wallet:       walletName,
+			Worker:       WorkerFromWorkerfull(sf.Workerfull),
+			ServerID:     sf.ServerID,
+			IP:           sf.MinerIp,
+			IsSolo:       sf.IsSolo,
+			RewardMethod: sf.RewardMethod,
+		})
+	}
+
+	for start := 0; start < len(identities); start += IdentitiesBatchSize {
+		end := min(start+IdentitiesBatchSize, len(identities))
+
+		resolved, err := batchStorage.ResolveIdentitiesBatch(ctx, identities[start:end])
+		if err != nil {
+			return fmt.Errorf("ResolveIdentitiesBatch: %w", err)
+		}
+		if err := u.minerCache.CreateIdentities(resolved); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// coinID код монеты из кэша, при отсутствии - из хранилища с кэшированием
+func (u *ShareUseCase) coinID(ctx context.Context, coinSymbol string) (int64, error) {
+	coinID, err := u.coinCache.GetCoinIDByName(coinSymbol)
+	if err != nil {
+		return 0, err
+	}
+	if coinID > 0 {
+		return coinID, nil
+	}
+
+	coinID, err = u.coinStorage.GetCoinIDByName(ctx, coinSymbol)
+	if err != nil {
+		return 0, err
+	}
+	if coinID == 0 {
+		return 0, fmt.Errorf("%w %s", entity.ErrUnknownCoin, coinSymbol)
+	}
+
+	return u.coinCache.CreateCoin(coinSymbol, coinID)
+}
@@ -0,0 +1,96 @@
+package share
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/dto"
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+)
+
+type testMinerStorage struct {
+	MinerStorage
+	calls    int
+	requests []entity.Identity
+}
+
+func (m *testMinerStorage) ResolveIdentitiesBatch(ctx context.Context, identities []entity.Identity) ([]entity.Identity, error) {
+	m.calls++
+	m.requests = append(m.requests, identities...)
+
+	resolved := make([]entity.Identity, len(identities))
+	for i, ident := range identities {
+		resolved[i] = ident
+		resolved[i].WalletID = int64(100 + i)
+		resolved[i].WorkerID = int64(200 + i)
+	}
+	return resolved, nil
+}
+
+type testCoinStorage map[string]int64
+
+func (c testCoinStorage) GetCoinIDByName(ctx context.Context, coin string) (int64, error) {
+	return c[coin], nil
+}
+
+type testCoinCache map[string]int64
+
+func (c testCoinCache) CreateCoin(key string, value int64) (int64, error) {
+	c[key] = value
+	return value, nil
+}
+
+func (c testCoinCache) GetCoinIDByName(coin string) (int64, error) {
+	return c[coin], nil
+}
+
+type testMinerCache map[string]int64
+
+func (c testMinerCache) CreateWallet(wallet entity.Wallet) (int64, error) { return wallet.ID, nil }
+func (c testMinerCache) CreateWorker(worker entity.Worker) (int64, error) { return worker.ID, nil }
+
+func (c testMinerCache) GetWalletIDByName(wallet string, coinID int64, rewardMethod string) (int64, error) {
+	return c[fmt.Sprintf("wallet:%s:%d:%s", wallet, coinID, rewardMethod)], nil
+}
+
+func (c testMinerCache) GetWorkerIDByName(worker string, coinID int64, rewardMethod string) (int64, error) {
+	return c[fmt.Sprintf("worker:%s:%d:%s", worker, coinID, rewardMethod)], nil
+}
+
+func (c testMinerCache) CreateIdentities(identities []entity.Identity) error {
+	for _, ident := range identities {
+		c[fmt.Sprintf("wallet:%s:%d:%s", ident.Wallet, ident.CoinID, ident.RewardMethod)] = ident.WalletID
+		c[fmt.Sprintf("worker:%s:%d:%s", ident.Workerfull, ident.CoinID, ident.RewardMethod)] = ident.WorkerID
+	}
+	return nil
+}
+
+func TestResolveIdentities(t *testing.T) {
+	miners := &testMinerStorage{}
+	minerCache := testMinerCache{
+		"wallet:cached:4:PPLNS":    1,
+		"worker:cached.w1:4:PPLNS": 2,
+	}
+	u := NewShareUseCase(nil, miners, testCoinStorage{"ALPH": 4}, minerCache, testCoinCache{}, nil, nil)
+
+	shares := []dto.ShareFound{
+		{CoinSymbol: "ALPH", Workerfull: "wallet1.w1", RewardMethod: "PPLNS"},
+		{CoinSymbol: "ALPH", Workerfull: "wallet1.w1", RewardMethod: "PPLNS"}, // повтор
+		{CoinSymbol: "ALPH", Workerfull: "wallet1.w2", RewardMethod: "PPLNS"},
+		{CoinSymbol: "ALPH", Workerfull: "cached.w1", RewardMethod: "PPLNS"},     // уже в кэше
+		{CoinSymbol: "UNKNOWN", Workerfull: "wallet2.w1", RewardMethod: "PPLNS"}, // монета не найдена
+	}
+
+	err := u.ResolveIdentities(context.Background(), shares)
+	require.NoError(t, err)
+	require.Equal(t, 1, miners.calls)
+	require.Len(t, miners.requests, 2)
+	require.Equal(t, "wallet1", miners.requests[0].Wallet)
+	require.Equal(t, "w2", miners.requests[1].Worker)
+
+	id, _ := minerCache.GetWorkerIDByName("wallet1.w2", 4, "PPLNS")
+	require.Equal(t, int64(201), id)
+}
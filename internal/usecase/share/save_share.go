@@ -2,19 +2,112 @@ package share
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
 )
 
 // AddSharesBatch сохранение шары в базе данных (ClickHouse)
-// Возвращает nil, если запись была добавлена успешно
-func (u *ShareUseCase) AddSharesBatch(shares []entity.Share) error {
+// Возвращает nil, если запись была добавлена успешно; на вставку отводится не более 10 секунд и не дольше ctx
+// Дубликаты по UUID (если фильтр включен) не сохраняются и возвращаются в *entity.PartialSaveError с entity.ErrDuplicateShare,
+// запоминаются только сохраненные шары
+// Повторы nonce сохраняются отдельно до сохранения шар (при ошибке пакет будет повторен целиком),
+// отклоненные повторы (nonce_replay.reject) возвращаются в *entity.PartialSaveError с entity.ErrNonceReplay
+func (u *ShareUseCase) AddSharesBatch(ctx context.Context, shares []entity.Share) error {
+	if u.dedup == nil {
+		_, rejected, err := u.saveShares(ctx, shares)
+		return withRejected(err, rejected)
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	shares, reserved, duplicates := u.dedup.reserve(shares)
+	rejected := make([]*entity.ShareError, 0, len(duplicates))
+	for _, uuid := range duplicates {
+		rejected = append(rejected, &entity.ShareError{UUID: uuid, Err: entity.ErrDuplicateShare})
+	}
+
+	var err error
+	if len(shares) > 0 {
+		var saved []entity.Share
+		var replays []*entity.ShareError
+		saved, replays, err = u.saveShares(ctx, shares)
+		u.dedup.release(reserved, savedUUIDs(saved, err))
+		rejected = append(rejected, replays...)
+	}
+
+	return withRejected(err, rejected)
+}
+
+// withRejected добавление отброшенных шар к результату сохранения
+// ошибка сохранения всего пакета возвращается как есть (пакет будет повторен)
+func withRejected(err error, rejected []*entity.ShareError) error {
+	if len(rejected) == 0 {
+		return err
+	}
+
+	var partial *entity.PartialSaveError
+	switch {
+	case err == nil:
+		return &entity.PartialSaveError{Rejected: rejected}
+	case errors.As(err, &partial):
+		return &entity.PartialSaveError{Rejected: append(rejected, partial.Rejected...)}
+	default:
+		return err
+	}
+}
+
+// saveShares отсев повторов nonce и сохранение
+// возвращает шары, переданные в хранилище, и отклоненные повторы nonce
+func (u *ShareUseCase) saveShares(ctx context.Context, shares []entity.Share) ([]entity.Share, []*entity.ShareError, error) {
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
+	var rejected []*entity.ShareError
+	if u.nonceReplay != nil {
+		var replays []entity.NonceReplay
+		shares, replays = u.nonceReplay.Check(shares)
+		if len(replays) > 0 && u.nonceReplay.storage != nil {
+			if err := u.nonceReplay.storage.AddNonceReplays(ctx, replays); err != nil {
+				return nil, nil, fmt.Errorf("AddNonceReplays: %w", err)
+			}
+		}
+		for _, r := range replays {
+			if r.Rejected {
+				rejected = append(rejected, &entity.ShareError{UUID: r.ShareUUID, Err: fmt.Errorf("%w of share %s", entity.ErrNonceReplay, r.OriginalUUID)})
+			}
+		}
+		if len(shares) == 0 {
+			return nil, rejected, nil
+		}
+	}
+
 	err := u.shareStorage.AddSharesBatch(ctx, shares)
 
-	return err
+	return shares, rejected, err
+}
+
+// savedUUIDs UUID сохраненных шар: при успехе - все, при частичном сохранении - кроме отклоненных
+func savedUUIDs(shares []entity.Share, err error) []string {
+	var partial *entity.PartialSaveError
+	if err != nil && !errors.As(err, &partial) {
+		return nil
+	}
+
+	rejected := make(map[string]struct{})
+	if partial != nil {
+		for _, uuid := range partial.RejectedUUIDs() {
+			rejected[uuid] = struct{}{}
+		}
+	}
+
+	uuids := make([]string, 0, len(shares))
+	for _, sh := range shares {
+		if _, ok := rejected[sh.UUID]; !ok {
+			uuids = append(uuids, sh.UUID)
+		}
+	}
+
+	return uuids
 }
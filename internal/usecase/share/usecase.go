@@ -19,6 +19,12 @@ type MinerStorage interface {
 	GetWorkerIDByName(ctx context.Context, worker string, coinID int64, rewardMethod string) (int64, error) // 0 - если не найден
 }
 
+// MinerBatchStorage пакетное получение/создание кошельков и воркеров
+// необязательное расширение MinerStorage: если хранилище его не реализует, разрешение идет по одной шаре
+type MinerBatchStorage interface {
+	ResolveIdentitiesBatch(ctx context.Context, identities []entity.Identity) ([]entity.Identity, error) // WalletID/WorkerID = 0 - если для записи не удалось
+}
+
 // CoinStorage работа с данными о монете из хранилища  (Postgresql или кэш (ristretto))
 // Метода сохранения в базу нет, потому что подразумевается что база уже заполнена
 type CoinStorage interface {
@@ -32,6 +38,7 @@ type MinerCache interface {
 	CreateWorker(worker entity.Worker) (int64, error)
 	GetWalletIDByName(wallet string, coinID int64, rewardMethod string) (int64, error) // 0 - если не найден
 	GetWorkerIDByName(worker string, coinID int64, rewardMethod string) (int64, error) // 0 - если не найден
+	CreateIdentities(identities []entity.Identity) error                               // пакетное кэширование кошельков и воркеров
 }
 
 // CoinCache работа с кэшированными данными о монете
@@ -41,19 +48,30 @@ type CoinCache interface {
 }
 
 type ShareUseCase struct {
-	shareStorage ShareStorage // персистентная база (ClickHouse)
-	minerStorage MinerStorage // персистентная база (Postgresql)
-	coinStorage  CoinStorage  // персистентная база (Postgresql)
-	minerCache   MinerCache   // кэш в оперативной памяти для майнеров
-	coinCache    CoinCache    // кэш в оперативной памяти для монет
+	shareStorage ShareStorage         // персистентная база (ClickHouse)
+	minerStorage MinerStorage         // персистентная база (Postgresql)
+	coinStorage  CoinStorage          // персистентная база (Postgresql)
+	minerCache   MinerCache           // кэш в оперативной памяти для майнеров
+	coinCache    CoinCache            // кэш в оперативной памяти для монет
+	nonceReplay  *NonceReplayDetector // nil - повторы nonce не отслеживаются
+	dedup        *DuplicateFilter     // nil - дубликаты по UUID не отсеиваются
+
+	rewardMethods map[string]struct{} // допустимые методы начисления вознаграждения (nil - любой непустой)
 }
 
-func NewShareUseCase(s ShareStorage, m MinerStorage, c CoinStorage, mc MinerCache, cc CoinCache) *ShareUseCase {
+func NewShareUseCase(s ShareStorage, m MinerStorage, c CoinStorage, mc MinerCache, cc CoinCache, nr *NonceReplayDetector, df *DuplicateFilter) *ShareUseCase {
 	return &ShareUseCase{
 		shareStorage: s,
 		minerStorage: m,
 		coinStorage:  c,
 		minerCache:   mc,
 		coinCache:    cc,
+		nonceReplay:  nr,
+		dedup:        df,
 	}
 }
+
+// DuplicatesCount количество отброшенных дубликатов шар с момента запуска
+func (u *ShareUseCase) DuplicatesCount() uint64 {
+	return u.dedup.DuplicatesCount()
+}
@@ -0,0 +1,105 @@
+package share
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/dto"
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+)
+
+// Допустимый интервал времени нахождения шары
+var (
+	MinShareDate     = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC) // более ранние даты - мусор (секунды вместо миллисекунд и т.п.)
+	MaxShareDateSkew = time.Hour                                   // насколько время шары может опережать текущее (расхождение часов пул-серверов)
+)
+
+// ValidateShare проверка полей шары перед нормализацией
+func (u *ShareUseCase) ValidateShare(shareFound dto.ShareFound) error {
+	return ValidateShareFound(shareFound, time.Now(), u.rewardMethods)
+}
+
+// ValidateNormalized проверка полей уже нормализованной шары
+func (u *ShareUseCase) ValidateNormalized(share entity.Share) error {
+	return ValidateNormalizedShare(share, time.Now(), u.rewardMethods)
+}
+
+// SetRewardMethods допустимые методы начисления вознаграждения (пусто - любой непустой)
+// вызывается до начала обработки шар
+func (u *ShareUseCase) SetRewardMethods(methods []string) {
+	u.rewardMethods = nil
+	if len(methods) == 0 {
+		return
+	}
+
+	u.rewardMethods = make(map[string]struct{}, len(methods))
+	for _, m := range methods {
+		u.rewardMethods[m] = struct{}{}
+	}
+}
+
+// ValidateShareFound проверка полей шары: формат uuid, имя воркера, время нахождения, числовые поля, метод начисления
+// rewardMethods - допустимые методы начисления (nil - любой непустой)
+// возвращает постоянную ошибку (entity.ErrInvalidShare или entity.ErrInvalidDecimal) с причиной
+func ValidateShareFound(shareFound dto.ShareFound, now time.Time, rewardMethods map[string]struct{}) error {
+	if _, err := uuid.Parse(shareFound.Uuid); err != nil {
+		return fmt.Errorf("%w: uuid %q: %s", entity.ErrInvalidShare, shareFound.Uuid, err.Error())
+	}
+
+	if shareFound.Workerfull == "" || WalletFromWorkerfull(shareFound.Workerfull) == "" {
+		return fmt.Errorf("%w: workerfull %q: wallet is empty", entity.ErrInvalidShare, shareFound.Workerfull)
+	}
+
+	return validateShareFields(shareFound.ShareDate, shareFound.RewardMethod, shareFound.Difficulty, shareFound.Sharedif, shareFound.Cost, now, rewardMethods)
+}
+
+// ValidateNormalizedShare проверка уже нормализованной шары (получена по gRPC с кодами монеты, кошелька и воркера)
+// проверки те же, что и у ValidateShareFound, вместо имени воркера - коды больше нуля
+func ValidateNormalizedShare(share entity.Share, now time.Time, rewardMethods map[string]struct{}) error {
+	if _, err := uuid.Parse(share.UUID); err != nil {
+		return fmt.Errorf("%w: uuid %q: %s", entity.ErrInvalidShare, share.UUID, err.Error())
+	}
+
+	if share.CoinID <= 0 || share.WalletID <= 0 || share.WorkerID <= 0 {
+		return fmt.Errorf("%w: coin_id, wallet_id and worker_id must be greater than 0", entity.ErrInvalidShare)
+	}
+
+	return validateShareFields(share.ShareDate, share.RewardMethod, share.Difficulty, share.Sharedif, share.Cost, now, rewardMethods)
+}
+
+// validateShareFields общие проверки: время нахождения, метод начисления, числовые поля
+func validateShareFields(shareDateMs int64, rewardMethod, difficulty, sharedif, cost string, now time.Time, rewardMethods map[string]struct{}) error {
+	shareDate := time.UnixMilli(shareDateMs)
+	if shareDate.Before(MinShareDate) || shareDate.After(now.Add(MaxShareDateSkew)) {
+		return fmt.Errorf("%w: shareDate %d out of range", entity.ErrInvalidShare, shareDateMs)
+	}
+
+	if rewardMethod == "" {
+		return fmt.Errorf("%w: rewardMethod is empty", entity.ErrInvalidShare)
+	}
+	if _, ok := rewardMethods[rewardMethod]; rewardMethods != nil && !ok {
+		return fmt.Errorf("%w: unknown rewardMethod %q", entity.ErrInvalidShare, rewardMethod)
+	}
+
+	for _, field := range []struct {
+		name  string
+		value string
+	}{
+		{"difficulty", difficulty},
+		{"sharedif", sharedif},
+		{"cost", cost},
+	} {
+		d, err := decimal.NewFromString(field.value)
+		if err != nil {
+			return fmt.Errorf("%w: %s %q", entity.ErrInvalidDecimal, field.name, field.value)
+		}
+		if d.IsNegative() {
+			return fmt.Errorf("%w: %s %q is negative", entity.ErrInvalidDecimal, field.name, field.value)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package share
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dnsoftware/mpm-shares-processor/internal/dto"
+	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
+)
+
+func TestValidateShareFound(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	valid := dto.ShareFound{
+		Uuid:         "23c4567b-f8e4-473f-bd06-a0ff8b295e82",
+		CoinSymbol:   "ALPH",
+		Workerfull:   "15DPDpMdvB3iKzS3mVykxPqSyvE3SdArSUeE98vwyoyKe.test_local",
+		ShareDate:    1734885835318,
+		Difficulty:   "0.002649",
+		Sharedif:     "0.003806",
+		RewardMethod: "PPLNS",
+		Cost:         "0.000000",
+	}
+	require.NoError(t, ValidateShareFound(valid, now, nil))
+	require.NoError(t, ValidateShareFound(valid, now, map[string]struct{}{"PPLNS": {}}))
+
+	tests := []struct {
+		name   string
+		modify func(sf *dto.ShareFound)
+		want   error
+	}{
+		{"uuid", func(sf *dto.ShareFound) { sf.Uuid = "uuid-1" }, entity.ErrInvalidShare},
+		{"empty workerfull", func(sf *dto.ShareFound) { sf.Workerfull = "" }, entity.ErrInvalidShare},
+		{"empty wallet", func(sf *dto.ShareFound) { sf.Workerfull = ".worker" }, entity.ErrInvalidShare},
+		{"share date in seconds", func(sf *dto.ShareFound) { sf.ShareDate = 1734885835 }, entity.ErrInvalidShare},
+		{"share date in future", func(sf *dto.ShareFound) { sf.ShareDate = now.Add(2 * time.Hour).UnixMilli() }, entity.ErrInvalidShare},
+		{"reward method", func(sf *dto.ShareFound) { sf.RewardMethod = "pplns" }, entity.ErrInvalidShare},
+		{"empty reward method", func(sf *dto.ShareFound) { sf.RewardMethod = "" }, entity.ErrInvalidShare},
+		{"difficulty", func(sf *dto.ShareFound) { sf.Difficulty = "" }, entity.ErrInvalidDecimal},
+		{"sharedif", func(sf *dto.ShareFound) { sf.Sharedif = "1,5" }, entity.ErrInvalidDecimal},
+		{"negative cost", func(sf *dto.ShareFound) { sf.Cost = "-1" }, entity.ErrInvalidDecimal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := valid
+			tt.modify(&sf)
+			err := ValidateShareFound(sf, now, map[string]struct{}{"PPLNS": {}, "SOLO": {}})
+			require.ErrorIs(t, err, tt.want)
+			require.True(t, entity.IsPermanent(err))
+		})
+	}
+}
+
+func TestValidateNormalizedShare(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	valid := entity.Share{
+		UUID:         "23c4567b-f8e4-473f-bd06-a0ff8b295e82",
+		CoinID:       4,
+		WalletID:     1,
+		WorkerID:     1,
+		ShareDate:    1734885835318,
+		Difficulty:   "0.002649",
+		Sharedif:     "0.003806",
+		RewardMethod: "PPLNS",
+		Cost:         "0.000000",
+	}
+	require.NoError(t, ValidateNormalizedShare(valid, now, map[string]struct{}{"PPLNS": {}}))
+
+	tests := []struct {
+		name   string
+		modify func(sh *entity.Share)
+		want   error
+	}{
+		{"uuid", func(sh *entity.Share) { sh.UUID = "" }, entity.ErrInvalidShare},
+		{"coin", func(sh *entity.Share) { sh.CoinID = 0 }, entity.ErrInvalidShare},
+		{"wallet", func(sh *entity.Share) { sh.WalletID = 0 }, entity.ErrInvalidShare},
+		{"worker", func(sh *entity.Share) { sh.WorkerID = -1 }, entity.ErrInvalidShare},
+		{"share date in seconds", func(sh *entity.Share) { sh.ShareDate = 1734885835 }, entity.ErrInvalidShare},
+		{"reward method", func(sh *entity.Share) { sh.RewardMethod = "pplns" }, entity.ErrInvalidShare},
+		{"cost", func(sh *entity.Share) { sh.Cost = "" }, entity.ErrInvalidDecimal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sh := valid
+			tt.modify(&sh)
+			err := ValidateNormalizedShare(sh, now, map[string]struct{}{"PPLNS": {}})
+			require.ErrorIs(t, err, tt.want)
+			require.True(t, entity.IsPermanent(err))
+		})
+	}
+}
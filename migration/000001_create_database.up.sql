@@ -1 +1 @@
-CREATE DATABASE IF NOT EXISTS mpmhouse ON CLUSTER clickhouse_cluster;
+CREATE DATABASE IF NOT EXISTS {{.Database}} ON CLUSTER {{.Cluster}};
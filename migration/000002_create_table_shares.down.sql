@@ -1 +1 @@
-DROP TABLE mpmhouse.shares ON CLUSTER clickhouse_cluster SYNC;
\ No newline at end of file
+DROP TABLE {{.Database}}.shares ON CLUSTER {{.Cluster}} SYNC;
\ No newline at end of file
@@ -1,4 +1,4 @@
-CREATE TABLE IF NOT EXISTS mpmhouse.shares  ON CLUSTER clickhouse_cluster (
+CREATE TABLE IF NOT EXISTS {{.Database}}.shares  ON CLUSTER {{.Cluster}} (
    uuid String, -- уникальный идентификатор
    server_id String, -- идентификатор пул-сервера
    coin_id Int64, -- идентификатор монеты
@@ -0,0 +1,6 @@
+DROP VIEW IF EXISTS {{.Database}}.share_offsets_mv ON CLUSTER {{.Cluster}} SYNC;
+DROP TABLE IF EXISTS {{.Database}}.share_offsets ON CLUSTER {{.Cluster}} SYNC;
+ALTER TABLE {{.Database}}.shares ON CLUSTER {{.Cluster}}
+    DROP COLUMN IF EXISTS kafka_topic,
+    DROP COLUMN IF EXISTS kafka_partition,
+    DROP COLUMN IF EXISTS kafka_offset;
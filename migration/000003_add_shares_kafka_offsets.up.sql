@@ -0,0 +1,25 @@
+-- позиция шары в Кафке: сохраняется в той же вставке, что и сама шара (exactly-once при повторном чтении)
+ALTER TABLE {{.Database}}.shares ON CLUSTER {{.Cluster}}
+    ADD COLUMN IF NOT EXISTS kafka_topic LowCardinality(String) DEFAULT '', -- топик (пусто - шара получена не из Кафки)
+    ADD COLUMN IF NOT EXISTS kafka_partition Int32 DEFAULT -1, -- партиция
+    ADD COLUMN IF NOT EXISTS kafka_offset Int64 DEFAULT -1; -- смещение
+
+-- последние сохраненные смещения по топикам/партициям
+CREATE TABLE IF NOT EXISTS {{.Database}}.share_offsets ON CLUSTER {{.Cluster}} (
+   topic LowCardinality(String), -- топик
+   partition Int32, -- партиция
+   max_offset SimpleAggregateFunction(max, Int64) -- последнее сохраненное смещение
+)
+ENGINE = ReplicatedAggregatingMergeTree(
+    '/clickhouse/tables/{shard}/share_offsets',
+    '{replica}'
+)
+ORDER BY (topic, partition);
+
+-- заполняется при каждой вставке в shares
+CREATE MATERIALIZED VIEW IF NOT EXISTS {{.Database}}.share_offsets_mv ON CLUSTER {{.Cluster}}
+TO {{.Database}}.share_offsets AS
+SELECT kafka_topic AS topic, kafka_partition AS partition, max(kafka_offset) AS max_offset
+FROM {{.Database}}.shares
+WHERE kafka_offset >= 0
+GROUP BY topic, partition;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS {{.Database}}.nonce_replays ON CLUSTER {{.Cluster}} SYNC;
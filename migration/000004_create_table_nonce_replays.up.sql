@@ -0,0 +1,19 @@
+-- повторные отправки воркером шары с тем же nonce (для разбора)
+CREATE TABLE IF NOT EXISTS {{.Database}}.nonce_replays ON CLUSTER {{.Cluster}} (
+   share_uuid String, -- идентификатор повторной шары
+   original_uuid String, -- идентификатор первой шары с этим nonce
+   server_id String, -- идентификатор пул-сервера
+   coin_id Int64, -- идентификатор монеты
+   worker_id Int64, -- ID воркера
+   wallet_id Int64, -- ID майнера (кошелька)
+   share_date DateTime64(3), -- время повторной шары
+   nonce String, -- nonce шары
+   rejected Bool, -- шара отклонена (false - только помечена, сохранена в shares)
+   detected_at DateTime64(3) DEFAULT now64(3) -- время обнаружения
+)
+ENGINE = ReplicatedReplacingMergeTree(
+    '/clickhouse/tables/{shard}/nonce_replays',
+    '{replica}'
+)
+PARTITION BY toYYYYMM(share_date)
+ORDER BY (wallet_id, share_date, share_uuid);
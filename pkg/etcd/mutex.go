@@ -0,0 +1,45 @@
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
+)
+
+// Mutex распределенная блокировка по ключу etcd
+// держится арендой сессии: при падении процесса снимается через ttl
+type Mutex struct {
+	client *clientv3.Client
+	key    string
+	ttl    int // время жизни аренды, в секундах
+}
+
+func NewMutex(client *clientv3.Client, key string, ttl int) *Mutex {
+	return &Mutex{client: client, key: key, ttl: ttl}
+}
+
+// Lock ожидание блокировки (до отмены ctx), unlock снимает блокировку и закрывает сессию
+func (m *Mutex) Lock(ctx context.Context) (func(), error) {
+	session, err := concurrency.NewSession(m.client, concurrency.WithTTL(m.ttl))
+	if err != nil {
+		return nil, fmt.Errorf("etcd session: %w", err)
+	}
+
+	mu := concurrency.NewMutex(session, m.key)
+	if err := mu.Lock(ctx); err != nil {
+		session.Close()
+		return nil, fmt.Errorf("etcd lock %s: %w", m.key, err)
+	}
+
+	unlock := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = mu.Unlock(ctx)
+		_ = session.Close()
+	}
+
+	return unlock, nil
+}
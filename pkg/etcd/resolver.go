@@ -0,0 +1,208 @@
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/grpc/resolver"
+)
+
+// ResolverScheme схема адреса gRPC соединения через service discovery: "etcd:///<ключ сервиса>"
+const ResolverScheme = "etcd"
+
+// RoundRobinServiceConfig конфиг gRPC соединения для распределения запросов между всеми экземплярами сервиса
+const RoundRobinServiceConfig = `{"loadBalancingConfig": [{"round_robin":{}}]}`
+
+// resolverRetryInterval пауза перед повторной подпиской после обрыва watch
+const resolverRetryInterval = 2 * time.Second
+
+// ResolverBuilder gRPC резолвер адресов сервисов по записям service discovery в etcd
+// Экземпляры сервиса - записи с ключом <discoveryBase>/<ключ сервиса> и <discoveryBase>/<ключ сервиса>/<ID экземпляра>
+type ResolverBuilder struct {
+	client        *clientv3.Client
+	discoveryBase string // базовый путь к зарегистрированным сервисам
+}
+
+func NewResolverBuilder(client *clientv3.Client, discoveryBase string) *ResolverBuilder {
+	return &ResolverBuilder{
+		client:        client,
+		discoveryBase: strings.TrimSuffix(discoveryBase, "/"),
+	}
+}
+
+func (b *ResolverBuilder) Scheme() string {
+	return ResolverScheme
+}
+
+func (b *ResolverBuilder) Build(target resolver.Target, cc resolver.ClientConn, opts resolver.BuildOptions) (resolver.Resolver, error) {
+	serviceKey := strings.TrimPrefix(target.Endpoint(), "/")
+	if serviceKey == "" {
+		return nil, fmt.Errorf("etcd resolver: empty service key in target %s", target.String())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &etcdResolver{
+		client:    b.client,
+		cc:        cc,
+		key:       b.discoveryBase + "/" + serviceKey,
+		instances: make(map[string]string),
+		resolveCh: make(chan struct{}, 1),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+
+	r.wg.Add(1)
+	go r.watch()
+
+	return r, nil
+}
+
+type etcdResolver struct {
+	client    *clientv3.Client
+	cc        resolver.ClientConn
+	key       string            // полный ключ сервиса в etcd
+	instances map[string]string // ключ экземпляра -> адрес
+	resolveCh chan struct{}     // запрос повторного чтения адресов (ResolveNow)
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+func (r *etcdResolver) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case r.resolveCh <- struct{}{}:
+	default:
+	}
+}
+
+func (r *etcdResolver) Close() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+// watch чтение текущих адресов и отслеживание их изменений
+// при обрыве подписки или запросе ResolveNow адреса перечитываются заново
+func (r *etcdResolver) watch() {
+	defer r.wg.Done()
+
+	for {
+		rev, err := r.load()
+		if err != nil {
+			r.cc.ReportError(err)
+			if !r.sleep(resolverRetryInterval) {
+				return
+			}
+			continue
+		}
+
+		watchCtx, watchCancel := context.WithCancel(r.ctx)
+		watchCh := r.client.Watch(watchCtx, r.key, clientv3.WithPrefix(), clientv3.WithRev(rev+1))
+
+		reload := false
+		for !reload {
+			select {
+			case <-r.ctx.Done():
+				watchCancel()
+				return
+			case <-r.resolveCh:
+				reload = true
+			case resp, ok := <-watchCh:
+				if !ok || resp.Err() != nil {
+					reload = true
+					break
+				}
+				for _, ev := range resp.Events {
+					key := string(ev.Kv.Key)
+					if !r.match(key) {
+						continue
+					}
+					if ev.Type == clientv3.EventTypeDelete {
+						delete(r.instances, key)
+					} else {
+						r.instances[key] = string(ev.Kv.Value)
+					}
+				}
+				r.update()
+			}
+		}
+		watchCancel()
+	}
+}
+
+// load чтение всех экземпляров сервиса, возвращает ревизию etcd для подписки на изменения
+func (r *etcdResolver) load() (int64, error) {
+	ctx, cancel := context.WithTimeout(r.ctx, 5*time.Second)
+	defer cancel()
+
+	resp, err := r.client.Get(ctx, r.key, clientv3.WithPrefix())
+	if err != nil {
+		return 0, fmt.Errorf("etcd resolver %s: %w", r.key, err)
+	}
+
+	r.instances = make(map[string]string)
+	for _, kv := range resp.Kvs {
+		if r.match(string(kv.Key)) {
+			r.instances[string(kv.Key)] = string(kv.Value)
+		}
+	}
+	r.update()
+
+	return resp.Header.Revision, nil
+}
+
+// match принадлежит ли ключ сервису (сам ключ или ключ экземпляра), а не сервису с похожим именем
+func (r *etcdResolver) match(key string) bool {
+	return key == r.key || strings.HasPrefix(key, r.key+"/")
+}
+
+// update передача текущего списка адресов в gRPC соединение
+// без экземпляров передается пустой список: одного ReportError мало, балансировщик продолжил бы вызывать ушедшие экземпляры
+func (r *etcdResolver) update() {
+	addrs := instanceAddresses(r.instances)
+	if len(addrs) == 0 {
+		_ = r.cc.UpdateState(resolver.State{})
+		r.cc.ReportError(fmt.Errorf("etcd resolver %s: no registered instances", r.key))
+		return
+	}
+
+	state := resolver.State{}
+	for _, addr := range addrs {
+		state.Addresses = append(state.Addresses, resolver.Address{Addr: addr})
+	}
+	_ = r.cc.UpdateState(state)
+}
+
+func (r *etcdResolver) sleep(d time.Duration) bool {
+	select {
+	case <-r.ctx.Done():
+		return false
+	case <-r.resolveCh:
+		return true
+	case <-time.After(d):
+		return true
+	}
+}
+
+// instanceAddresses уникальные адреса экземпляров в детерминированном порядке
+func instanceAddresses(instances map[string]string) []string {
+	seen := make(map[string]struct{}, len(instances))
+	addrs := make([]string, 0, len(instances))
+	for _, addr := range instances {
+		if addr == "" {
+			continue
+		}
+		if _, ok := seen[addr]; ok {
+			continue
+		}
+		seen[addr] = struct{}{}
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+
+	return addrs
+}
@@ -0,0 +1,59 @@
+package etcd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/resolver"
+)
+
+// testClientConn gRPC соединение, запоминающее переданные резолвером состояния и ошибки
+type testClientConn struct {
+	resolver.ClientConn
+	states []resolver.State
+	errs   []error
+}
+
+func (c *testClientConn) UpdateState(state resolver.State) error {
+	c.states = append(c.states, state)
+	return nil
+}
+
+func (c *testClientConn) ReportError(err error) {
+	c.errs = append(c.errs, err)
+}
+
+func TestResolverInstances(t *testing.T) {
+	r := &etcdResolver{key: "/service_discovery/services/miners:grpc"}
+
+	require.True(t, r.match("/service_discovery/services/miners:grpc"))
+	require.True(t, r.match("/service_discovery/services/miners:grpc/2"))
+	require.False(t, r.match("/service_discovery/services/miners:grpc2"))
+
+	addrs := instanceAddresses(map[string]string{
+		"/service_discovery/services/miners:grpc":   "10.0.0.2:7878",
+		"/service_discovery/services/miners:grpc/2": "10.0.0.1:7878",
+		"/service_discovery/services/miners:grpc/3": "10.0.0.2:7878",
+		"/service_discovery/services/miners:grpc/4": "",
+	})
+	require.Equal(t, []string{"10.0.0.1:7878", "10.0.0.2:7878"}, addrs)
+}
+
+func TestResolverNoInstances(t *testing.T) {
+	cc := &testClientConn{}
+	r := &etcdResolver{
+		cc:        cc,
+		key:       "/service_discovery/services/miners:grpc",
+		instances: map[string]string{"/service_discovery/services/miners:grpc/1": "10.0.0.1:7878"},
+	}
+
+	r.update()
+	require.Len(t, cc.states[0].Addresses, 1)
+
+	// последний экземпляр ушел - адреса очищаются, а не остаются прежними
+	delete(r.instances, "/service_discovery/services/miners:grpc/1")
+	r.update()
+	require.Len(t, cc.states, 2)
+	require.Empty(t, cc.states[1].Addresses)
+	require.Len(t, cc.errs, 1)
+}
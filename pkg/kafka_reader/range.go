@@ -0,0 +1,157 @@
+package kafka_reader
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// Range диапазон сообщений партиций топика для повторного чтения
+// начало задается смещением или временем (время важнее), конец - аналогично, не включается
+type Range struct {
+	Partitions []int32   // партиции (пусто - все партиции топика)
+	FromOffset int64     // первое смещение (если FromTime не задано)
+	ToOffset   int64     // смещение после последнего (<= 0 и ToTime не задано - до конца партиции на момент запуска)
+	FromTime   time.Time // первое сообщение с меткой времени не раньше FromTime
+	ToTime     time.Time // сообщения с меткой времени раньше ToTime
+}
+
+// ReadRange чтение диапазона сообщений без группы потребителей (смещения группы не меняются)
+// сообщения передаются в handle пакетами до batchSize по каждой партиции, ошибка handle прерывает чтение
+func ReadRange(ctx context.Context, brokers []string, topic string, r Range, batchSize int, handle func(batch []*sarama.ConsumerMessage) error) error {
+	client, err := sarama.NewClient(brokers, sarama.NewConfig())
+	if err != nil {
+		return fmt.Errorf("kafka client: %w", err)
+	}
+	defer client.Close()
+
+	partitions := r.Partitions
+	if len(partitions) == 0 {
+		partitions, err = client.Partitions(topic)
+		if err != nil {
+			return fmt.Errorf("partitions %s: %w", topic, err)
+		}
+	}
+
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		return fmt.Errorf("kafka consumer: %w", err)
+	}
+	defer consumer.Close()
+
+	for _, partition := range partitions {
+		from, to, err := partitionRange(client, topic, partition, r)
+		if err != nil {
+			return err
+		}
+		if from >= to {
+			continue
+		}
+		if err := readPartition(ctx, consumer, topic, partition, from, to, batchSize, handle); err != nil {
+			return fmt.Errorf("partition %d: %w", partition, err)
+		}
+	}
+
+	return nil
+}
+
+// partitionRange смещения [from, to) партиции по диапазону r с учетом доступных в топике сообщений
+func partitionRange(client sarama.Client, topic string, partition int32, r Range) (int64, int64, error) {
+	oldest, err := client.GetOffset(topic, partition, sarama.OffsetOldest)
+	if err != nil {
+		return 0, 0, fmt.Errorf("oldest offset %s/%d: %w", topic, partition, err)
+	}
+	newest, err := client.GetOffset(topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return 0, 0, fmt.Errorf("newest offset %s/%d: %w", topic, partition, err)
+	}
+
+	// смещение первого сообщения не раньше t, newest - таких сообщений нет
+	offsetByTime := func(t time.Time) (int64, error) {
+		offset, err := client.GetOffset(topic, partition, t.UnixMilli())
+		if err != nil {
+			return 0, fmt.Errorf("offset by time %s/%d: %w", topic, partition, err)
+		}
+		if offset < 0 {
+			return newest, nil
+		}
+		return offset, nil
+	}
+
+	from, to := r.FromOffset, newest
+	if !r.FromTime.IsZero() {
+		if from, err = offsetByTime(r.FromTime); err != nil {
+			return 0, 0, err
+		}
+	}
+	if !r.ToTime.IsZero() {
+		if to, err = offsetByTime(r.ToTime); err != nil {
+			return 0, 0, err
+		}
+	} else if r.ToOffset > 0 {
+		to = r.ToOffset
+	}
+
+	return max(from, oldest), min(to, newest), nil
+}
+
+// rangeIdleTimeout ожидание следующего сообщения партиции: если за это время сообщений нет, а все смещения диапазона
+// уже записаны в партицию (high watermark не меньше to), чтение завершается -
+// последние смещения диапазона без данных (служебные записи транзакций, удалены компактированием)
+const rangeIdleTimeout = 5 * time.Second
+
+// readPartition чтение смещений [from, to) партиции
+// чтение завершается на первом сообщении со смещением to-1 и дальше (смещения могут идти с пропусками)
+// или по rangeIdleTimeout, если сообщений до to больше не будет
+func readPartition(ctx context.Context, consumer sarama.Consumer, topic string, partition int32, from int64, to int64, batchSize int, handle func(batch []*sarama.ConsumerMessage) error) error {
+	pc, err := consumer.ConsumePartition(topic, partition, from)
+	if err != nil {
+		return err
+	}
+	defer pc.Close()
+
+	var batch []*sarama.ConsumerMessage
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := handle(batch)
+		batch = nil
+		return err
+	}
+
+	idle := time.NewTimer(rangeIdleTimeout)
+	defer idle.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-pc.Errors():
+			return err
+		case <-idle.C:
+			if pc.HighWaterMarkOffset() >= to {
+				return flush()
+			}
+			idle.Reset(rangeIdleTimeout)
+		case msg, ok := <-pc.Messages():
+			if !ok {
+				return fmt.Errorf("partition consumer closed at offset %d", from)
+			}
+			if msg.Offset < to {
+				batch = append(batch, msg)
+			}
+			done := msg.Offset+1 >= to
+			if len(batch) >= batchSize || done {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+			if done {
+				return nil
+			}
+			idle.Reset(rangeIdleTimeout)
+		}
+	}
+}
@@ -112,6 +112,16 @@ func (r *KafkaReader) Close() {
 	r.logger.Info(fmt.Sprintf("KafkaConsumer завершён, Group: %s, Topic: %s", r.group, r.topic))
 }
 
+// Pause приостановка получения сообщений из партиций (сессия группы и heartbeat сохраняются)
+func (r *KafkaReader) Pause(partitions map[string][]int32) {
+	r.consumerGroup.Pause(partitions)
+}
+
+// Resume возобновление получения сообщений из приостановленных партиций
+func (r *KafkaReader) Resume(partitions map[string][]int32) {
+	r.consumerGroup.Resume(partitions)
+}
+
 // SetGroupOffset Сбрасывает для текущей группы смещение топика в начало
 func (r *KafkaReader) SetGroupOffset(offset int64) error {
 	// Создание нового клиента
@@ -134,6 +144,7 @@ func (r *KafkaReader) SetGroupOffset(offset int64) error {
 	partitions, err := client.Partitions(r.topic)
 	if err != nil {
 		r.logger.Error(fmt.Sprintf("Failed to get partitions: %s", err))
+		return err
 	}
 
 	// Установка конкретных смещений по партициям
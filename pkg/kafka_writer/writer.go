@@ -76,6 +76,10 @@ func (k *KafkaWriter) Start() {
 	go func() {
 		defer k.wg.Done()
 		for success := range k.producer.Successes() {
+			if done, ok := success.Metadata.(chan error); ok { // ожидается в SendMessageWait
+				done <- nil
+				continue
+			}
 			// TODO удалить для ускорения
 			log.Printf("Сообщение успешно отправлено в партицию %d, с оффсетом %d", success.Partition, success.Offset)
 		}
@@ -86,6 +90,10 @@ func (k *KafkaWriter) Start() {
 	go func() {
 		defer k.wg.Done()
 		for err := range k.producer.Errors() {
+			if done, ok := err.Msg.Metadata.(chan error); ok { // ожидается в SendMessageWait
+				done <- err.Err
+				continue
+			}
 			k.logger.Error(fmt.Sprintf("Ошибка отправки сообщения: %v", err.Err))
 		}
 	}()
@@ -93,21 +101,54 @@ func (k *KafkaWriter) Start() {
 
 // SendMessage - метод для отправки сообщения в Kafka
 func (k *KafkaWriter) SendMessage(ctx context.Context, key string, value string) {
+	k.SendMessageWithHeaders(ctx, key, []byte(value), nil)
+}
+
+// SendMessageWithHeaders - отправка сообщения в Kafka с дополнительными заголовками
+// контекст трассировки из ctx добавляется в заголовки автоматически
+func (k *KafkaWriter) SendMessageWithHeaders(ctx context.Context, key string, value []byte, extHeaders map[string]string) {
+	// Отправка сообщения
+	k.producer.Input() <- k.newMessage(ctx, key, value, extHeaders)
+}
+
+// SendMessageWait - отправка сообщения с ожиданием подтверждения брокера (или ошибки отправки)
+// подтверждение приходит через Successes()/Errors(), поэтому продюсер должен быть запущен Start
+func (k *KafkaWriter) SendMessageWait(ctx context.Context, key string, value []byte, extHeaders map[string]string) error {
+	done := make(chan error, 1)
+	message := k.newMessage(ctx, key, value, extHeaders)
+	message.Metadata = done
+
+	select {
+	case k.producer.Input() <- message:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (k *KafkaWriter) newMessage(ctx context.Context, key string, value []byte, extHeaders map[string]string) *sarama.ProducerMessage {
 	message := &sarama.ProducerMessage{
 		Topic: k.topic,
 		Key:   sarama.StringEncoder(key), // Ключ сообщения
-		Value: sarama.StringEncoder(value),
+		Value: sarama.ByteEncoder(value),
 	}
 
 	// Заголовки Kafka и инъекция контекста трассировки в них
-	headers := SaramaHeadersCarrier(make([]sarama.RecordHeader, 0))
+	headers := SaramaHeadersCarrier(make([]sarama.RecordHeader, 0, len(extHeaders)))
 	propagator := otel.GetTextMapPropagator()
 	propagator.Inject(ctx, &headers) // Передаём указатель на адаптер
+	for hKey, hVal := range extHeaders {
+		headers.Set(hKey, hVal)
+	}
 	message.Headers = headers
 
-	// Отправка сообщения
-	k.producer.Input() <- message
+	return message
 }
 
 // Close - метод для закрытия продюсера
@@ -22,6 +22,7 @@ type Logger struct {
 var (
 	instance *Logger
 	once     sync.Once
+	level    = zap.NewAtomicLevel() // уровень логирования, изменяемый на ходу (SetLevel)
 )
 
 func getLogLevel(env string) zapcore.Level {
@@ -41,7 +42,7 @@ func getFileWriter(filePath string) zapcore.WriteSyncer {
 
 func InitLogger(env string, filePath string) {
 	once.Do(func() {
-		logLevel := getLogLevel(env)
+		level.SetLevel(getLogLevel(env))
 		encoderConfig := zap.NewProductionEncoderConfig()
 
 		if env == LogLevelProduction {
@@ -50,14 +51,14 @@ func InitLogger(env string, filePath string) {
 		}
 		encoder := zapcore.NewJSONEncoder(encoderConfig)
 		core := zapcore.NewTee(
-			zapcore.NewCore(encoder, getFileWriter(filePath), logLevel),
+			zapcore.NewCore(encoder, getFileWriter(filePath), level),
 		)
 
 		if env != LogLevelProduction {
 			// Добавить вывод в консоль в режиме отладки
 			encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder // добавим подсветку при выводе в консоль в режиме отладки
 			encoder = zapcore.NewConsoleEncoder(encoderConfig)
-			consoleCore := zapcore.NewCore(encoder, zapcore.AddSync(os.Stdout), logLevel)
+			consoleCore := zapcore.NewCore(encoder, zapcore.AddSync(os.Stdout), level)
 			core = zapcore.NewTee(core, consoleCore)
 		}
 
@@ -66,6 +67,22 @@ func InitLogger(env string, filePath string) {
 	})
 }
 
+// SetLevel изменение уровня логирования на ходу (debug, info, warn, error)
+func SetLevel(l string) error {
+	lvl, err := zapcore.ParseLevel(l)
+	if err != nil {
+		return err
+	}
+	level.SetLevel(lvl)
+
+	return nil
+}
+
+// Level текущий уровень логирования
+func Level() string {
+	return level.Level().String()
+}
+
 func Log() *Logger {
 	if instance == nil {
 		panic("Logger is not initialized. Call InitLogger() before using GetLogger()")
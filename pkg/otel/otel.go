@@ -30,6 +30,18 @@ type Config struct {
 //	cleanup := InitTracer(cfg)
 //	defer cleanup()
 func InitTracer(cfg Config) func() {
+	shutdown := InitTracerProvider(cfg)
+
+	return func() {
+		if err := shutdown(context.Background()); err != nil {
+			log.Fatalf("failed to shutdown TracerProvider: %v", err)
+		}
+	}
+}
+
+// InitTracerProvider Инициализация трассировщика, возвращает функцию остановки,
+// которая отправляет накопленные спаны в пределах ctx
+func InitTracerProvider(cfg Config) func(ctx context.Context) error {
 
 	ctx := context.Background()
 
@@ -65,11 +77,7 @@ func InitTracer(cfg Config) func() {
 	otel.SetTracerProvider(tp)
 	otel.SetTextMapPropagator(propagation.TraceContext{})
 
-	return func() {
-		if err := tp.Shutdown(ctx); err != nil {
-			log.Fatalf("failed to shutdown TracerProvider: %v", err)
-		}
-	}
+	return tp.Shutdown
 }
 
 // InitSimpleTracer Пример инициализации трассировщика с консольным экспортером:
@@ -8,15 +8,11 @@ import (
 	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
-	"github.com/golang-migrate/migrate/v4"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/dnsoftware/mpm-shares-processor/pkg/utils"
 
-	_ "github.com/golang-migrate/migrate/v4/database/clickhouse"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
-
 	"github.com/dnsoftware/mpm-shares-processor/config"
 	"github.com/dnsoftware/mpm-shares-processor/internal/constants"
 	"github.com/dnsoftware/mpm-shares-processor/internal/entity"
@@ -55,21 +51,16 @@ func TestAddShare(t *testing.T) {
 	err = conn.Ping(ctx)
 	require.NoError(t, err)
 
-	// Укажите путь к миграциям и строку подключения к базе данных
-	dsn := fmt.Sprintf("clickhouse://%s:%s@%s/%s", "default", "", cfg.Clickhouse.Addr[0], "default")
-	m, err := migrate.New(
-		"file://"+basePath+"/"+constants.MigrationDir,
-		dsn,
-	)
+	// Применить миграции (непримененные, без сброса истории)
+	migrator, err := clickhouse2.NewMigrator(clickhouse2.MigratorConfig{
+		Conn:     conn,
+		Dir:      basePath + "/" + constants.MigrationDir,
+		Database: cfg.Clickhouse.Database,
+		Cluster:  cfg.Clickhouse.Cluster,
+	})
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
 	require.NoError(t, err)
-
-	// Сброс миграций
-	m.Force(-1)
-
-	// Применить миграции
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		require.NoError(t, err)
-	}
 	log.Println("Миграции успешно применены")
 
 	conn.Close()
@@ -237,7 +237,7 @@ func TestConsumerGetShares(t *testing.T) {
 	//shareStorage, err := pb.NewShareStorage(connShares)
 	require.NoError(t, err)
 
-	usecase := share.NewShareUseCase(shareStorage, minerStorage, coinStorage, cacheMiner, cacheCoin)
+	usecase := share.NewShareUseCase(shareStorage, minerStorage, coinStorage, cacheMiner, cacheCoin, nil, nil)
 
 	/**************** Конец usecase ****************/
 
@@ -245,7 +245,7 @@ func TestConsumerGetShares(t *testing.T) {
 		BatchSize:     5,
 		FlushInterval: 1,
 	}
-	consumer, err := shares.NewShareConsumer(cfgConsumer, reader, usecase)
+	consumer, err := shares.NewShareConsumer(cfgConsumer, reader, usecase, nil, nil)
 	require.NoError(t, err)
 	//defer consumer.Close()
 
@@ -117,7 +117,7 @@ func TestSaveShareLocal(t *testing.T) {
 	shareStorage, err := pb.NewShareStorage(&grpc.ClientConn{})
 	require.NoError(t, err)
 
-	usecase := share.NewShareUseCase(shareStorage, minerStorage, coinStorage, cacheMiner, cacheCoin)
+	usecase := share.NewShareUseCase(shareStorage, minerStorage, coinStorage, cacheMiner, cacheCoin, nil, nil)
 
 	// Загружаем тестовые данные
 	var sfSlice []dto.ShareFound
@@ -224,7 +224,7 @@ func TestSaveShareRemote(t *testing.T) {
 	shareStorage, err := pb.NewShareStorage(connShares)
 	require.NoError(t, err)
 
-	usecase := share.NewShareUseCase(shareStorage, minerStorage, coinStorage, cacheMiner, cacheCoin)
+	usecase := share.NewShareUseCase(shareStorage, minerStorage, coinStorage, cacheMiner, cacheCoin, nil, nil)
 
 	// Загружаем тестовые данные
 	var sfSlice []dto.ShareFound
@@ -256,7 +256,7 @@ func TestSaveShareRemote(t *testing.T) {
 
 	// Записываем данные через API в ClickHouse, расположенный во внешнем микросервисе
 	start := time.Now().UnixMicro()
-	err = usecase.AddSharesBatch(sharesBatch)
+	err = usecase.AddSharesBatch(ctx, sharesBatch)
 	end := time.Now().UnixMicro()
 	require.NoError(t, err)
 